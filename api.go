@@ -1,5 +1,14 @@
 package wav
 
+import (
+	"errors"
+	"io"
+)
+
+// ErrPCMNotForwarded is returned by DataReader before the decoder was
+// forwarded to the data chunk.
+var ErrPCMNotForwarded = errors.New("decoder wasn't forwarded to the PCM data")
+
 // FormatChunk returns a copy of the parsed fmt chunk, if available.
 func (d *Decoder) FormatChunk() *FmtChunk {
 	if d == nil || d.FmtChunk == nil {
@@ -27,6 +36,32 @@ func (d *Decoder) SetRawChunks(chunks []RawChunk) {
 	d.UnknownChunks = cloneRawChunks(chunks)
 }
 
+// DataReader returns a reader over the raw bytes of the data chunk payload,
+// from the current position to its end, for feeding PCM data to other
+// libraries. It follows Rewind and SeekToFrame. The decoder must have been
+// forwarded to the data chunk by FwdToPCM, SkipToDataFast or a decode call,
+// otherwise ErrPCMNotForwarded is returned.
+func (d *Decoder) DataReader() (io.Reader, error) {
+	if d == nil || !d.pcmDataAccessed || d.PCMChunk == nil {
+		return nil, ErrPCMNotForwarded
+	}
+
+	return &pcmDataReader{d: d}, nil
+}
+
+// pcmDataReader reads from the current PCM chunk of a decoder.
+type pcmDataReader struct {
+	d *Decoder
+}
+
+func (r *pcmDataReader) Read(p []byte) (int, error) {
+	if r.d.PCMChunk == nil {
+		return 0, io.EOF
+	}
+
+	return r.d.PCMChunk.Read(p)
+}
+
 // FormatChunk returns a copy of the configured fmt chunk, if available.
 func (e *Encoder) FormatChunk() *FmtChunk {
 	if e == nil || e.FmtChunk == nil {
@@ -53,3 +88,20 @@ func (e *Encoder) SetRawChunks(chunks []RawChunk) {
 
 	e.UnknownChunks = cloneRawChunks(chunks)
 }
+
+// AppendRawChunk adds a copy of a single non-core chunk to UnknownChunks,
+// written before or after the data chunk. Odd sized payloads are padded on
+// write.
+func (e *Encoder) AppendRawChunk(id [4]byte, data []byte, beforeData bool) {
+	if e == nil {
+		return
+	}
+
+	e.UnknownChunks = append(e.UnknownChunks, RawChunk{
+		ID:         id,
+		Size:       uint32(len(data)),
+		Data:       append([]byte(nil), data...),
+		Order:      len(e.UnknownChunks),
+		BeforeData: beforeData,
+	})
+}
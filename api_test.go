@@ -1,6 +1,12 @@
 package wav
 
-import "testing"
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"testing"
+)
 
 func TestDecoderChunkAPIs(t *testing.T) {
 	subFormat := makeSubFormatGUID(wavFormatPCM)
@@ -135,3 +141,94 @@ func TestChunkAPIs_NilReceivers(t *testing.T) {
 
 	enc.SetRawChunks([]RawChunk{{ID: [4]byte{'a', 'b', 'c', 'd'}}})
 }
+
+func TestDecoderDataReader(t *testing.T) {
+	data, err := os.ReadFile("fixtures/kick.wav")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dec := NewDecoder(bytes.NewReader(data))
+
+	_, err = dec.DataReader()
+	if !errors.Is(err, ErrPCMNotForwarded) {
+		t.Fatalf("expected ErrPCMNotForwarded, got %v", err)
+	}
+
+	err = dec.FwdToPCM()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := dec.DataReader()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	payload, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(payload, data[44:44+8968]) {
+		t.Fatalf("expected the 8968 byte data payload, got %d bytes", len(payload))
+	}
+
+	err = dec.SeekToFrame(100)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	payload, err = io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(payload, data[44+200:44+8968]) {
+		t.Fatalf("expected the payload from frame 100, got %d bytes", len(payload))
+	}
+}
+
+func TestEncoder_AppendRawChunk(t *testing.T) {
+	enc := NewMemoryEncoder(8000, 16, 1, wavFormatPCM)
+
+	payload := []byte{1, 2, 3}
+	enc.AppendRawChunk([4]byte{'v', 'n', 'd', 'r'}, payload, true)
+	enc.AppendRawChunk([4]byte{'t', 'a', 'i', 'l'}, []byte{4, 5}, false)
+
+	payload[0] = 9
+
+	err := enc.WriteSilence(4)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := enc.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chunks, err := parseWavChunks(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vendor, vendorPos := findChunk(chunks, "vndr")
+	tail, tailPos := findChunk(chunks, "tail")
+	_, dataPos := findChunk(chunks, "data")
+
+	if vendor == nil || !bytes.Equal(vendor.data, []byte{1, 2, 3}) {
+		t.Fatalf("unexpected vendor chunk %+v", vendor)
+	}
+
+	if tail == nil || !bytes.Equal(tail.data, []byte{4, 5}) {
+		t.Fatalf("unexpected tail chunk %+v", tail)
+	}
+
+	if vendorPos > dataPos || tailPos < dataPos {
+		t.Fatalf("unexpected chunk order: vndr %d, data %d, tail %d", vendorPos, dataPos, tailPos)
+	}
+
+	var nilEnc *Encoder
+	nilEnc.AppendRawChunk([4]byte{'v', 'n', 'd', 'r'}, nil, true)
+}
@@ -0,0 +1,67 @@
+package wav
+
+import (
+	"crypto/md5"
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/go-audio/audio"
+)
+
+const audioHashBufferSize = 4096
+
+// AudioMD5 returns the MD5 digest of the decoded samples, ignoring metadata
+// and chunk layout, so files holding the same audio hash equally. Decoding
+// starts at the current position; use Rewind to hash the file again.
+//
+// The digest is stable across versions: each interleaved float32 sample is
+// clamped to [-1, 1], multiplied by 2^31, rounded half away from zero,
+// clamped to the int32 range and hashed as 4 little-endian bytes. The
+// samples are hashed as stored: SetGainDB and ClampFloatOutput don't change
+// the digest.
+func (d *Decoder) AudioMD5() ([16]byte, error) {
+	var digest [16]byte
+
+	if d == nil {
+		return digest, ErrPCMDataNotFound
+	}
+
+	// decode without the output settings, restoring them afterwards
+	gainSet, clamp := d.gainSet, d.ClampFloatOutput
+	d.gainSet, d.ClampFloatOutput = false, true
+
+	defer func() { d.gainSet, d.ClampFloatOutput = gainSet, clamp }()
+
+	hash := md5.New()
+	buf := &audio.Float32Buffer{Data: make([]float32, audioHashBufferSize)}
+	scratch := make([]byte, 4*audioHashBufferSize)
+
+	for {
+		n, err := d.pcmBuffer(buf)
+		if err != nil {
+			return digest, fmt.Errorf("failed to decode samples: %w", err)
+		}
+
+		if n == 0 {
+			break
+		}
+
+		for i, val := range buf.Data[:n] {
+			binary.LittleEndian.PutUint32(scratch[4*i:], uint32(hashQuantize(val)))
+		}
+
+		_, _ = hash.Write(scratch[:4*n])
+	}
+
+	copy(digest[:], hash.Sum(nil))
+
+	return digest, nil
+}
+
+// hashQuantize maps a float32 sample to int32 as documented on AudioMD5.
+func hashQuantize(val float32) int32 {
+	scaled := math.Round(float64(clampFloat32(val, -1, 1)) * scalePCMInt32)
+
+	return int32(max(min(scaled, math.MaxInt32), math.MinInt32))
+}
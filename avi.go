@@ -0,0 +1,206 @@
+package wav
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+var (
+	// ErrAVIAudioStreamNotFound is returned by NewAVIAudioDecoder when the
+	// AVI file has no audio stream or no audio data.
+	ErrAVIAudioStreamNotFound = errors.New("no audio stream found in the AVI file")
+
+	errNotAVI = errors.New("not an AVI file")
+)
+
+// maxAVIStrfSize bounds the stream format read into memory.
+const maxAVIStrfSize = 1 << 16
+
+// NewAVIAudioDecoder returns a decoder for the first audio stream of an AVI
+// file. The stream format (strf) becomes the fmt chunk and the stream's
+// audio chunks (00wb, 01wb, ...) of the movi lists, including those of
+// OpenDML AVIX extensions, are read in order as one data chunk. The samples
+// are read from r on demand, so the decoder supports everything a decoder of
+// a plain WAV file does, including Rewind and random access.
+func NewAVIAudioDecoder(r io.ReadSeeker) (*Decoder, error) {
+	if r == nil {
+		return nil, errNilDecoder
+	}
+
+	avi := &aviScanner{r: r, audioStream: -1}
+
+	err := avi.scan()
+	if err != nil {
+		return nil, err
+	}
+
+	if avi.strf == nil || len(avi.segments) == 0 {
+		return nil, ErrAVIAudioStreamNotFound
+	}
+
+	return NewDecoder(newVirtualWAVReader(r, avi.strf, avi.segments)), nil
+}
+
+// aviScanner walks the chunks of an AVI file and collects the format and
+// data chunks of its first audio stream.
+type aviScanner struct {
+	r io.ReadSeeker
+	// streams counts the strl lists seen, audioStream is the index of the
+	// audio stream or -1.
+	streams     int
+	audioStream int
+	strf        []byte
+	audioID     [4]byte
+	segments    []dataSegment
+}
+
+// scan reads the top level RIFF AVI chunk and any RIFF AVIX chunks after it.
+func (s *aviScanner) scan() error {
+	_, err := s.r.Seek(0, io.SeekStart)
+	if err != nil {
+		return fmt.Errorf("failed to seek to the start: %w", err)
+	}
+
+	for first := true; ; first = false {
+		id, size, err := s.readHeader()
+		if err != nil {
+			if first {
+				return fmt.Errorf("%w: %w", errNotAVI, err)
+			}
+
+			// a truncated trailing chunk header ends the scan
+			return nil
+		}
+
+		start, err := s.r.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return fmt.Errorf("failed to locate chunk %q: %w", id, err)
+		}
+
+		var form [4]byte
+		if id == [4]byte{'R', 'I', 'F', 'F'} && size >= 4 {
+			_, err = io.ReadFull(s.r, form[:])
+			if err != nil {
+				return fmt.Errorf("failed to read the RIFF form type: %w", err)
+			}
+		}
+
+		switch {
+		case first && form != [4]byte{'A', 'V', 'I', ' '}:
+			return fmt.Errorf("%w: form type %q", errNotAVI, form[:])
+		case form == [4]byte{'A', 'V', 'I', ' '} || form == [4]byte{'A', 'V', 'I', 'X'}:
+			err = s.scanList(start+4, start+int64(size))
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err = s.r.Seek(start+int64(size)+int64(size%2), io.SeekStart)
+		if err != nil {
+			return fmt.Errorf("failed to skip chunk %q: %w", id, err)
+		}
+	}
+}
+
+// scanList walks the chunks between start and end, descending into the
+// header, stream and movi lists.
+func (s *aviScanner) scanList(start, end int64) error {
+	for pos := start; pos+8 <= end; {
+		_, err := s.r.Seek(pos, io.SeekStart)
+		if err != nil {
+			return fmt.Errorf("failed to seek to chunk: %w", err)
+		}
+
+		id, size, err := s.readHeader()
+		if err != nil {
+			// a truncated chunk header ends the list
+			return nil
+		}
+
+		payload := pos + 8
+		size = uint32(min(int64(size), end-payload))
+
+		err = s.scanChunk(id, payload, size)
+		if err != nil {
+			return err
+		}
+
+		pos = payload + int64(size) + int64(size%2)
+	}
+
+	return nil
+}
+
+// scanChunk handles the chunk with the given payload position and size.
+func (s *aviScanner) scanChunk(id [4]byte, payload int64, size uint32) error {
+	switch id {
+	case CIDList:
+		if size < 4 {
+			return nil
+		}
+
+		var listType [4]byte
+
+		_, err := io.ReadFull(s.r, listType[:])
+		if err != nil {
+			return fmt.Errorf("failed to read the LIST type: %w", err)
+		}
+
+		switch listType {
+		case [4]byte{'s', 't', 'r', 'l'}:
+			err = s.scanList(payload+4, payload+int64(size))
+			s.streams++
+
+			return err
+		case [4]byte{'h', 'd', 'r', 'l'}, [4]byte{'m', 'o', 'v', 'i'}, [4]byte{'r', 'e', 'c', ' '}:
+			return s.scanList(payload+4, payload+int64(size))
+		}
+	case [4]byte{'s', 't', 'r', 'h'}:
+		var fccType [4]byte
+
+		_, err := io.ReadFull(s.r, fccType[:])
+		if err != nil {
+			return fmt.Errorf("failed to read the stream header: %w", err)
+		}
+
+		if s.audioStream < 0 && fccType == [4]byte{'a', 'u', 'd', 's'} {
+			s.audioStream = s.streams
+			copy(s.audioID[:], fmt.Sprintf("%02dwb", s.streams%100))
+		}
+	case [4]byte{'s', 't', 'r', 'f'}:
+		if s.audioStream != s.streams || s.strf != nil {
+			return nil
+		}
+
+		if size > maxAVIStrfSize {
+			return fmt.Errorf("%w: stream format of %d bytes", ErrUnexpectedFmtChunkSize, size)
+		}
+
+		s.strf = make([]byte, size)
+
+		_, err := io.ReadFull(s.r, s.strf)
+		if err != nil {
+			return fmt.Errorf("failed to read the audio stream format: %w", err)
+		}
+	default:
+		if s.audioStream >= 0 && id == s.audioID && size > 0 {
+			s.segments = append(s.segments, dataSegment{start: payload, size: int64(size)})
+		}
+	}
+
+	return nil
+}
+
+// readHeader reads a little-endian chunk ID and size.
+func (s *aviScanner) readHeader() ([4]byte, uint32, error) {
+	var hdr [8]byte
+
+	_, err := io.ReadFull(s.r, hdr[:])
+	if err != nil {
+		return [4]byte{}, 0, err
+	}
+
+	return [4]byte(hdr[:4]), binary.LittleEndian.Uint32(hdr[4:]), nil
+}
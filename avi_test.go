@@ -0,0 +1,107 @@
+package wav
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"os"
+	"testing"
+)
+
+func TestNewAVIAudioDecoder(t *testing.T) {
+	data, err := os.ReadFile("fixtures/sample.avi")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dec, err := NewAVIAudioDecoder(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !dec.IsValidFile() {
+		t.Fatal("expected the audio stream to be a valid file")
+	}
+
+	if dec.SampleRate != 22050 || dec.NumChans != 1 || dec.BitDepth != 8 || dec.WavAudioFormat != wavFormatPCM {
+		t.Fatalf("unexpected format %d Hz %d ch %d bit format %d", dec.SampleRate, dec.NumChans, dec.BitDepth, dec.WavAudioFormat)
+	}
+
+	// collect the raw 8-bit samples of the 01wb chunks in movi order
+	var want []byte
+
+	for off := 12; off+8 <= len(data); {
+		id := string(data[off : off+4])
+		size := int(binary.LittleEndian.Uint32(data[off+4 : off+8]))
+
+		switch {
+		case id == "LIST" && string(data[off+8:off+12]) == "movi":
+			off += 12
+
+			continue
+		case id == "01wb":
+			want = append(want, data[off+8:off+8+size]...)
+		}
+
+		off += 8 + size + size%2
+	}
+
+	buf, err := dec.FullPCMBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(buf.Data) != len(want) || len(want) == 0 {
+		t.Fatalf("expected %d samples, got %d", len(want), len(buf.Data))
+	}
+
+	for i, raw := range want {
+		if expected := normalizePCMInt(int(raw), 8); buf.Data[i] != expected {
+			t.Fatalf("sample %d: got %v, want %v", i, buf.Data[i], expected)
+		}
+	}
+
+	// random access reads from the middle of the audio chunks
+	err = dec.SeekToFrame(int64(len(want) - 10))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tail, err := dec.FullPCMBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertFloat32SlicesClose(t, tail.Data, buf.Data[len(want)-10:], 0)
+}
+
+func TestNewAVIAudioDecoder_Invalid(t *testing.T) {
+	wavData, err := os.ReadFile("fixtures/kick.wav")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = NewAVIAudioDecoder(bytes.NewReader(wavData))
+	if !errors.Is(err, errNotAVI) {
+		t.Fatalf("expected errNotAVI, got %v", err)
+	}
+
+	var b bytes.Buffer
+	b.WriteString("RIFF")
+	b.Write(make([]byte, 4))
+	b.WriteString("AVI ")
+
+	var hdrl bytes.Buffer
+	hdrl.WriteString("hdrl")
+	writeTestChunk(t, &hdrl, "avih", make([]byte, 56))
+	writeTestChunk(t, &b, "LIST", hdrl.Bytes())
+	writeTestChunk(t, &b, "LIST", []byte("movi"))
+
+	videoOnly := b.Bytes()
+	binary.LittleEndian.PutUint32(videoOnly[4:8], uint32(len(videoOnly)-8))
+
+	_, err = NewAVIAudioDecoder(bytes.NewReader(videoOnly))
+	if !errors.Is(err, ErrAVIAudioStreamNotFound) {
+		t.Fatalf("expected ErrAVIAudioStreamNotFound, got %v", err)
+	}
+}
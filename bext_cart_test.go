@@ -1,12 +1,17 @@
 package wav
 
 import (
+	"bytes"
+	"encoding/binary"
+	"errors"
 	"os"
 	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/go-audio/audio"
+	"github.com/go-audio/riff"
 )
 
 func TestDecoder_ReadMetadata_BWFBroadcastChunk(t *testing.T) {
@@ -165,3 +170,363 @@ func TestBroadcastAndCartMetadataRoundTrip(t *testing.T) {
 		t.Fatalf("cart mismatch:\n got: %#v\nwant: %#v", dec.Metadata.Cart, expectedCart)
 	}
 }
+
+func TestBroadcastExtensionCodingHistoryEntries(t *testing.T) {
+	bext := &BroadcastExtension{
+		CodingHistory: "A=ANALOGUE,M=stereo,T=Studer A816; SN1007; 38; telcom; Agfa PER528\r\n" +
+			"A=PCM,F=48000,W=18,M=stereo,T=NVision; NV1000; A/D\r\n\r\n",
+	}
+
+	want := []CodingEntry{
+		{Coding: "ANALOGUE", Mode: "stereo", Text: "Studer A816; SN1007; 38; telcom; Agfa PER528"},
+		{Coding: "PCM", SampleRate: "48000", WordLength: "18", Mode: "stereo", Text: "NVision; NV1000; A/D"},
+	}
+
+	got := bext.CodingHistoryEntries()
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("entries mismatch:\n got=%+v\nwant=%+v", got, want)
+	}
+
+	bext.AppendCodingHistory(CodingEntry{Coding: "PCM", SampleRate: "48000", WordLength: "24", Mode: "mono", Text: "cut, trimmed"})
+
+	if !strings.HasSuffix(bext.CodingHistory, "A=PCM,F=48000,W=24,M=mono,T=cut, trimmed\r\n") {
+		t.Fatalf("unexpected coding history: %q", bext.CodingHistory)
+	}
+
+	got = bext.CodingHistoryEntries()
+	if len(got) != 3 || got[2].Text != "cut, trimmed" || got[2].WordLength != "24" {
+		t.Fatalf("appended entry not parsed back: %+v", got)
+	}
+
+	empty := &BroadcastExtension{}
+	empty.AppendCodingHistory(CodingEntry{Coding: "PCM"})
+
+	if empty.CodingHistory != "A=PCM\r\n" {
+		t.Fatalf("unexpected coding history: %q", empty.CodingHistory)
+	}
+}
+
+func TestBroadcastExtensionUMIDHex(t *testing.T) {
+	bext := &BroadcastExtension{}
+
+	if bext.UMIDHex() != "" {
+		t.Fatalf("expected an empty UMID, got %q", bext.UMIDHex())
+	}
+
+	extended := strings.Repeat("060A2B340101010501010D4313000000", 2) + strings.Repeat("AB", 32)
+
+	err := bext.SetUMIDHex(strings.ToLower(extended))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if bext.UMID[0] != 0x06 || bext.UMID[63] != 0xAB {
+		t.Fatalf("unexpected UMID bytes %x", bext.UMID)
+	}
+
+	if bext.UMIDHex() != extended {
+		t.Fatalf("UMID round trip mismatch: %q", bext.UMIDHex())
+	}
+
+	basic := extended[:64]
+
+	err = bext.SetUMIDHex(basic)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if bext.UMIDHex() != basic+strings.Repeat("0", 64) {
+		t.Fatalf("expected a zero padded basic UMID, got %q", bext.UMIDHex())
+	}
+
+	for _, invalid := range []string{"0A0B", extended + "00", strings.Repeat("ZZ", 64)} {
+		if err := bext.SetUMIDHex(invalid); !errors.Is(err, ErrInvalidUMID) {
+			t.Fatalf("%q: expected ErrInvalidUMID, got %v", invalid, err)
+		}
+	}
+
+	err = bext.SetUMIDHex("")
+	if err != nil || bext.UMIDHex() != "" {
+		t.Fatalf("expected a cleared UMID, got %q, %v", bext.UMIDHex(), err)
+	}
+}
+
+func TestDecoder_BroadcastChunkPaddedCodingHistory(t *testing.T) {
+	data, err := os.ReadFile("fixtures/bext-padded-history.wav")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dec := NewDecoder(bytes.NewReader(data))
+	dec.ReadMetadata()
+
+	if err := dec.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	bext := dec.Metadata.BroadcastExtension
+	if bext == nil {
+		t.Fatal("expected bext metadata")
+	}
+
+	if bext.CodingHistory != "A=PCM,F=8000,W=16,M=mono,T=padded-writer\r\n" {
+		t.Fatalf("expected the padding to be cut off, got %q", bext.CodingHistory)
+	}
+
+	if bext.CodingHistorySize != 256 {
+		t.Fatalf("expected a coding history size of 256, got %d", bext.CodingHistorySize)
+	}
+
+	chunks, err := parseWavChunks(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	original, _ := findChunk(chunks, "bext")
+	if original == nil {
+		t.Fatal("fixture has no bext chunk")
+	}
+
+	if encoded := encodeBroadcastChunk(bext); !bytes.Equal(encoded, original.data) {
+		t.Fatalf("expected the re-encoded chunk to keep its padded size, got %d bytes, want %d", len(encoded), len(original.data))
+	}
+}
+
+func TestDecoder_BroadcastChunkOddSizeRoundTrip(t *testing.T) {
+	history := "A=PCM,F=8000,W=16,M=mono\r\n"
+	payload := encodeBroadcastChunk(&BroadcastExtension{Description: "odd"})
+	payload = append(payload, history...)
+	payload = append(payload, make([]byte, 6)...)
+
+	if len(payload)%2 == 0 {
+		payload = append(payload, 0)
+	}
+
+	var b bytes.Buffer
+	b.WriteString("RIFF")
+	b.Write(make([]byte, 4))
+	b.WriteString("WAVE")
+
+	fmtPayload := make([]byte, 16)
+	binary.LittleEndian.PutUint16(fmtPayload[0:2], wavFormatPCM)
+	binary.LittleEndian.PutUint16(fmtPayload[2:4], 1)
+	binary.LittleEndian.PutUint32(fmtPayload[4:8], 8000)
+	binary.LittleEndian.PutUint32(fmtPayload[8:12], 16000)
+	binary.LittleEndian.PutUint16(fmtPayload[12:14], 2)
+	binary.LittleEndian.PutUint16(fmtPayload[14:16], 16)
+	writeTestChunk(t, &b, "fmt ", fmtPayload)
+	writeTestChunk(t, &b, "bext", payload)
+	writeTestChunk(t, &b, "data", make([]byte, 4))
+
+	data := b.Bytes()
+	binary.LittleEndian.PutUint32(data[4:8], uint32(len(data)-8))
+
+	dec := NewDecoder(bytes.NewReader(data))
+
+	buf, err := dec.FullPCMBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dec.ReadMetadata()
+
+	if err := dec.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	bext := dec.Metadata.BroadcastExtension
+	if bext == nil || bext.CodingHistory != history {
+		t.Fatalf("unexpected bext metadata %#v", bext)
+	}
+
+	enc := NewMemoryEncoder(8000, 16, 1, wavFormatPCM)
+	enc.Metadata = dec.Metadata
+
+	err = enc.Write(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encoded, err := enc.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chunks, err := parseWavChunks(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	roundTrip, _ := findChunk(chunks, "bext")
+	if roundTrip == nil {
+		t.Fatal("expected a bext chunk")
+	}
+
+	if !bytes.Equal(roundTrip.data, payload) {
+		t.Fatalf("expected the %d byte bext chunk to round trip unchanged, got %d bytes", len(payload), roundTrip.size)
+	}
+}
+
+func TestEncodeBroadcastChunk_ConvertedCodingHistorySize(t *testing.T) {
+	testCases := []struct {
+		name    string
+		history string
+		size    int
+	}{
+		{name: "fits the padding", history: "A=PCM,T=Caf\xe9\r\n", size: 64},
+		{name: "outgrows the padding", history: "T=\xe9\xe9\xe9\xe9", size: 8},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			payload := encodeBroadcastChunk(&BroadcastExtension{})
+			field := make([]byte, testCase.size)
+			copy(field, testCase.history)
+			payload = append(payload, field...)
+
+			dec := &Decoder{CodePage: CodePageWindows1252}
+
+			err := DecodeBroadcastChunk(dec, &riff.Chunk{ID: CIDBext, Size: len(payload), R: bytes.NewReader(payload)})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			bext := dec.Metadata.BroadcastExtension
+			if bext.CodingHistory == testCase.history || bext.CodingHistorySize != testCase.size {
+				t.Fatalf("expected a converted history in a %d byte field, got %q in %d bytes", testCase.size, bext.CodingHistory, bext.CodingHistorySize)
+			}
+
+			encoded := encodeBroadcastChunk(bext)
+			want := max(len(payload), len(payload)-testCase.size+len(bext.CodingHistory))
+
+			if len(encoded) != want {
+				t.Fatalf("expected a %d byte chunk, got %d", want, len(encoded))
+			}
+
+			if got := string(encoded[len(payload)-testCase.size:][:len(bext.CodingHistory)]); got != bext.CodingHistory {
+				t.Fatalf("expected the UTF-8 history %q, got %q", bext.CodingHistory, got)
+			}
+		})
+	}
+}
+
+func TestDecoder_BroadcastChunkTruncated(t *testing.T) {
+	f, err := os.Open("fixtures/bext-truncated.wav")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	dec := NewDecoder(f)
+	dec.ReadMetadata()
+
+	if err := dec.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	bext := dec.Metadata.BroadcastExtension
+	if bext == nil {
+		t.Fatal("expected bext metadata")
+	}
+
+	if bext.Description != "Old BWF recorder" || bext.Originator != "Legacy Tool" ||
+		bext.OriginatorReference != "REF0001" || bext.OriginationDate != "1998-05-04" ||
+		bext.OriginationTime != "12:30:00" || bext.TimeReference != 48000 {
+		t.Fatalf("unexpected fields present in the chunk: %#v", bext)
+	}
+
+	// the chunk ends after the time reference, before the version field
+	if bext.Version != 0 {
+		t.Fatalf("expected version 0 without a version field, got %d", bext.Version)
+	}
+
+	if bext.UMID != ([bextUMIDLen]byte{}) || bext.Reserved != nil || bext.CodingHistory != "" {
+		t.Fatalf("expected the missing fields to be zero, got %#v", bext)
+	}
+
+	if err := dec.Rewind(); err != nil {
+		t.Fatal(err)
+	}
+
+	buf, err := dec.FullPCMBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(buf.Data) != 8 {
+		t.Fatalf("expected 8 samples after the truncated bext chunk, got %d", len(buf.Data))
+	}
+}
+
+func TestEncoder_MetadataBeforeData(t *testing.T) {
+	encode := func(beforeData bool) []byte {
+		t.Helper()
+
+		enc := NewMemoryEncoder(48000, 24, 2, wavFormatPCM)
+		enc.MetadataBeforeData = beforeData
+		enc.Metadata = &Metadata{
+			Title:              "take 1",
+			BroadcastExtension: &BroadcastExtension{Description: "scene 1", OriginationDate: "2024-05-01"},
+			Cart:               &Cart{Version: "0101", Title: "spot"},
+		}
+
+		err := enc.Write(&audio.Float32Buffer{
+			Format: &audio.Format{NumChannels: 2, SampleRate: 48000},
+			Data:   []float32{0.1, -0.1, 0.2, -0.2},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		data, err := enc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return data
+	}
+
+	for _, beforeData := range []bool{false, true} {
+		data := encode(beforeData)
+
+		chunks, err := parseWavChunks(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, dataPos := findChunk(chunks, "data")
+
+		for _, id := range []string{"bext", "cart", "LIST"} {
+			_, pos := findChunk(chunks, id)
+			if pos < 0 || (pos < dataPos) != beforeData {
+				t.Fatalf("MetadataBeforeData=%v: %s chunk at %d, data chunk at %d", beforeData, id, pos, dataPos)
+			}
+		}
+
+		dec := NewDecoder(bytes.NewReader(data))
+		dec.ReadMetadata()
+
+		if err := dec.Err(); err != nil {
+			t.Fatal(err)
+		}
+
+		if dec.Metadata == nil || dec.Metadata.BroadcastExtension == nil || dec.Metadata.BroadcastExtension.Description != "scene 1" ||
+			dec.Metadata.Cart == nil || dec.Metadata.Cart.Title != "spot" || dec.Metadata.Title != "take 1" {
+			t.Fatalf("MetadataBeforeData=%v: unexpected metadata %+v", beforeData, dec.Metadata)
+		}
+
+		err = dec.Rewind()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		buf, err := dec.FullPCMBuffer()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if buf.NumFrames() != 2 {
+			t.Fatalf("MetadataBeforeData=%v: expected 2 frames, got %d", beforeData, buf.NumFrames())
+		}
+	}
+}
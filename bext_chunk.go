@@ -3,6 +3,7 @@ package wav
 import (
 	"bytes"
 	"encoding/binary"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
@@ -22,11 +23,19 @@ const (
 )
 
 var (
-	errNilChunk   = errors.New("can't decode a nil chunk")
-	errNilDecoder = errors.New("nil decoder")
+	// ErrInvalidUMID is returned by SetUMIDHex for strings that aren't a hex
+	// encoded basic (32 byte) or extended (64 byte) UMID.
+	ErrInvalidUMID = errors.New("invalid UMID")
+	errNilChunk    = errors.New("can't decode a nil chunk")
+	errNilDecoder  = errors.New("nil decoder")
+	errNilBext     = errors.New("nil broadcast extension")
 )
 
-// DecodeBroadcastChunk decodes a bext chunk into decoder metadata.
+// DecodeBroadcastChunk decodes a bext chunk into decoder metadata. Chunks
+// shorter than the 602 bytes of fixed fields, as written by some early BWF
+// tools, are decoded up to their end: text cut off keeps what is present,
+// binary fields such as Version that are missing or incomplete are zero and
+// Reserved is nil when the chunk ends before it.
 func DecodeBroadcastChunk(dec *Decoder, chnk *riff.Chunk) error {
 	if chnk == nil {
 		return errNilChunk
@@ -48,6 +57,10 @@ func DecodeBroadcastChunk(dec *Decoder, chnk *riff.Chunk) error {
 		return fmt.Errorf("failed to read the bext chunk - %w", err)
 	}
 
+	// the RIFF pad byte of an odd sized chunk isn't part of the coding
+	// history padding
+	buf = buf[:min(dec.declaredChunkSize(chnk), len(buf))]
+
 	if dec.Metadata == nil {
 		dec.Metadata = &Metadata{}
 	}
@@ -67,8 +80,20 @@ func DecodeBroadcastChunk(dec *Decoder, chnk *riff.Chunk) error {
 		return out
 	}
 
+	// old or truncated bext chunks end before the 602 bytes of fixed
+	// fields; binary fields cut off by the chunk end are left zero rather
+	// than decoded from a partial value
+	takeWhole := func(size int) []byte {
+		if offset+size > len(buf) {
+			offset += size
+			return make([]byte, size)
+		}
+
+		return take(size)
+	}
+
 	readFixedString := func(size int) string {
-		s := nullTermStr(take(size))
+		s := dec.decodeText(take(size))
 		return strings.TrimRight(s, " ")
 	}
 
@@ -78,17 +103,30 @@ func DecodeBroadcastChunk(dec *Decoder, chnk *riff.Chunk) error {
 	bext.OriginationDate = readFixedString(bextOriginationDateLen)
 	bext.OriginationTime = readFixedString(bextOriginationTimeLen)
 
-	timeRefLow := binary.LittleEndian.Uint32(take(4))
-	timeRefHigh := binary.LittleEndian.Uint32(take(4))
+	timeRefLow := binary.LittleEndian.Uint32(takeWhole(4))
+	timeRefHigh := binary.LittleEndian.Uint32(takeWhole(4))
 	bext.TimeReference = uint64(timeRefHigh)<<32 | uint64(timeRefLow)
-	bext.Version = binary.LittleEndian.Uint16(take(2))
+	bext.Version = binary.LittleEndian.Uint16(takeWhole(2))
 
-	copy(bext.UMID[:], take(bextUMIDLen))
-	bext.Reserved = take(bextReservedLen)
+	copy(bext.UMID[:], takeWhole(bextUMIDLen))
 
 	if offset < len(buf) {
-		codingHistory := bytes.TrimRight(buf[offset:], "\x00")
-		bext.CodingHistory = string(codingHistory)
+		bext.Reserved = take(bextReservedLen)
+	} else {
+		offset += bextReservedLen
+	}
+
+	if offset < len(buf) {
+		// the coding history is null terminated, anything after the first
+		// null is padding. A single null is just the terminator or the RIFF
+		// pad byte and isn't kept.
+		codingHistory := buf[offset:]
+		if end := clen(codingHistory); end+1 < len(codingHistory) {
+			bext.CodingHistorySize = len(codingHistory)
+			codingHistory = codingHistory[:end]
+		}
+
+		bext.CodingHistory = dec.decodeText(codingHistory)
 	}
 
 	chnk.Drain()
@@ -98,6 +136,156 @@ func DecodeBroadcastChunk(dec *Decoder, chnk *riff.Chunk) error {
 	return nil
 }
 
+// CodingEntry is a single line of a BWF coding history as described in
+// EBU R 98, e.g. "A=PCM,F=48000,W=24,M=stereo,T=original".
+type CodingEntry struct {
+	// Coding is the coding algorithm (A=), e.g. PCM or ANALOGUE.
+	Coding string
+	// SampleRate is the sampling frequency in Hz (F=).
+	SampleRate string
+	// BitRate is the bit rate in kbit/s per channel for MPEG coding (B=).
+	BitRate string
+	// WordLength is the word length in bits (W=).
+	WordLength string
+	// Mode is the channel mode (M=), e.g. mono, stereo or dual-mono.
+	Mode string
+	// Text is free text (T=). It is always the last field of a line and may
+	// contain commas.
+	Text string
+}
+
+// String formats the entry as a coding history line without line ending.
+func (c CodingEntry) String() string {
+	fields := []struct {
+		key   string
+		value string
+	}{
+		{"A=", c.Coding},
+		{"F=", c.SampleRate},
+		{"B=", c.BitRate},
+		{"W=", c.WordLength},
+		{"M=", c.Mode},
+		{"T=", c.Text},
+	}
+
+	parts := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if field.value != "" {
+			parts = append(parts, field.key+field.value)
+		}
+	}
+
+	return strings.Join(parts, ",")
+}
+
+// CodingHistoryEntries parses CodingHistory into one entry per line. Empty
+// lines and unknown keys are skipped.
+func (b *BroadcastExtension) CodingHistoryEntries() []CodingEntry {
+	if b == nil {
+		return nil
+	}
+
+	var entries []CodingEntry
+
+	for _, line := range strings.Split(b.CodingHistory, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		entries = append(entries, parseCodingEntry(line))
+	}
+
+	return entries
+}
+
+// AppendCodingHistory adds an entry to CodingHistory, terminated by CR/LF as
+// required by EBU R 98.
+func (b *BroadcastExtension) AppendCodingHistory(entry CodingEntry) {
+	if b == nil {
+		return
+	}
+
+	if b.CodingHistory != "" && !strings.HasSuffix(b.CodingHistory, "\n") {
+		b.CodingHistory += "\r\n"
+	}
+
+	b.CodingHistory += entry.String() + "\r\n"
+}
+
+// UMIDHex returns the UMID as 128 upper case hex characters, or an empty
+// string when no UMID is set.
+func (b *BroadcastExtension) UMIDHex() string {
+	if b == nil || b.UMID == ([bextUMIDLen]byte{}) {
+		return ""
+	}
+
+	return strings.ToUpper(hex.EncodeToString(b.UMID[:]))
+}
+
+// SetUMIDHex sets the UMID from its hex representation, as shown by broadcast
+// tools. It accepts 128 characters for an extended UMID or 64 for a basic
+// UMID, whose remaining bytes are zeroed. An empty string clears the UMID.
+func (b *BroadcastExtension) SetUMIDHex(s string) error {
+	if b == nil {
+		return errNilBext
+	}
+
+	if s == "" {
+		b.UMID = [bextUMIDLen]byte{}
+
+		return nil
+	}
+
+	if len(s) != 2*bextUMIDLen && len(s) != bextUMIDLen {
+		return fmt.Errorf("%w: %d hex characters, want %d or %d", ErrInvalidUMID, len(s), bextUMIDLen, 2*bextUMIDLen)
+	}
+
+	raw, err := hex.DecodeString(s)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidUMID, err.Error())
+	}
+
+	b.UMID = [bextUMIDLen]byte{}
+	copy(b.UMID[:], raw)
+
+	return nil
+}
+
+func parseCodingEntry(line string) CodingEntry {
+	var entry CodingEntry
+
+	for line != "" {
+		if strings.HasPrefix(line, "T=") {
+			entry.Text = line[2:]
+			break
+		}
+
+		field, rest, _ := strings.Cut(line, ",")
+		line = rest
+
+		key, value, ok := strings.Cut(field, "=")
+		if !ok {
+			continue
+		}
+
+		switch strings.TrimSpace(key) {
+		case "A":
+			entry.Coding = value
+		case "F":
+			entry.SampleRate = value
+		case "B":
+			entry.BitRate = value
+		case "W":
+			entry.WordLength = value
+		case "M":
+			entry.Mode = value
+		}
+	}
+
+	return entry
+}
+
 func encodeBroadcastChunk(bext *BroadcastExtension) []byte {
 	if bext == nil {
 		return nil
@@ -129,8 +317,14 @@ func encodeBroadcastChunk(bext *BroadcastExtension) []byte {
 	copy(reserved, bext.Reserved)
 	payload.Write(reserved)
 
-	if bext.CodingHistory != "" {
-		payload.WriteString(bext.CodingHistory)
+	// the padding follows from the bytes written, not the decoded text: a
+	// history converted from a code page takes more bytes as UTF-8 and eats
+	// into the padding, keeping the field at its original size
+	history := []byte(bext.CodingHistory)
+	payload.Write(history)
+
+	if padding := bext.CodingHistorySize - len(history); padding > 0 {
+		payload.Write(make([]byte, padding))
 	}
 
 	return payload.Bytes()
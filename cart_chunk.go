@@ -35,7 +35,8 @@ var (
 	errCartNilDecoder = errors.New("nil decoder")
 )
 
-// DecodeCartChunk decodes a cart chunk into decoder metadata.
+// DecodeCartChunk decodes a cart chunk into decoder metadata. Its text
+// fields are converted to UTF-8 like INFO strings, see CodePage.
 func DecodeCartChunk(dec *Decoder, chnk *riff.Chunk) error {
 	if chnk == nil {
 		return errCartNilChunk
@@ -77,7 +78,7 @@ func DecodeCartChunk(dec *Decoder, chnk *riff.Chunk) error {
 	}
 
 	readFixedString := func(size int) string {
-		s := nullTermStr(take(size))
+		s := dec.decodeText(take(size))
 		return strings.TrimRight(s, " ")
 	}
 
@@ -109,7 +110,7 @@ func DecodeCartChunk(dec *Decoder, chnk *riff.Chunk) error {
 		if idx := bytes.IndexByte(extra, 0); idx >= 0 {
 			cart.URL = string(extra[:idx])
 			tail := bytes.TrimRight(extra[idx+1:], "\x00")
-			cart.TagText = string(tail)
+			cart.TagText = dec.decodeText(tail)
 		} else {
 			cart.URL = string(extra)
 		}
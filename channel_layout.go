@@ -0,0 +1,85 @@
+package wav
+
+import "fmt"
+
+// Speaker position bits used in the WAVE_FORMAT_EXTENSIBLE channel mask.
+const (
+	SpeakerFrontLeft          = 0x1
+	SpeakerFrontRight         = 0x2
+	SpeakerFrontCenter        = 0x4
+	SpeakerLowFrequency       = 0x8
+	SpeakerBackLeft           = 0x10
+	SpeakerBackRight          = 0x20
+	SpeakerFrontLeftOfCenter  = 0x40
+	SpeakerFrontRightOfCenter = 0x80
+	SpeakerBackCenter         = 0x100
+	SpeakerSideLeft           = 0x200
+	SpeakerSideRight          = 0x400
+)
+
+const (
+	channelMaskMono        = SpeakerFrontCenter
+	channelMaskStereo      = SpeakerFrontLeft | SpeakerFrontRight
+	channelMask51Back      = channelMaskStereo | SpeakerFrontCenter | SpeakerLowFrequency | SpeakerBackLeft | SpeakerBackRight
+	channelMask51Side      = channelMaskStereo | SpeakerFrontCenter | SpeakerLowFrequency | SpeakerSideLeft | SpeakerSideRight
+	channelMask71Surround  = channelMask51Back | SpeakerSideLeft | SpeakerSideRight
+	channelMask71WideFront = channelMask51Back | SpeakerFrontLeftOfCenter | SpeakerFrontRightOfCenter
+)
+
+// defaultChannelMask returns the conventional speaker assignment for
+// numChans channels in their usual order, or 0 (no assignment) for channel
+// counts without one.
+func defaultChannelMask(numChans int) uint32 {
+	switch numChans {
+	case 1:
+		return channelMaskMono
+	case 2:
+		return channelMaskStereo
+	case 3:
+		return channelMaskStereo | SpeakerFrontCenter
+	case 4:
+		return channelMaskStereo | SpeakerBackLeft | SpeakerBackRight
+	case 5:
+		return channelMaskStereo | SpeakerFrontCenter | SpeakerBackLeft | SpeakerBackRight
+	case 6:
+		return channelMask51Back
+	case 7:
+		return channelMask51Side | SpeakerBackCenter
+	case 8:
+		return channelMask71Surround
+	default:
+		return 0
+	}
+}
+
+// ChannelLayout returns a friendly name for the channel configuration:
+// "mono", "stereo", "5.1", "7.1" or "N channels". When the fmt chunk is
+// extensible and carries a channel mask, the mask must match the standard
+// layout for the name to be used.
+func (d *Decoder) ChannelLayout() string {
+	if d == nil {
+		return channelLayoutName(0, 0)
+	}
+
+	var mask uint32
+	if d.FmtChunk != nil && d.FmtChunk.Extensible != nil {
+		mask = d.FmtChunk.Extensible.ChannelMask
+	}
+
+	return channelLayoutName(int(d.NumChans), mask)
+}
+
+func channelLayoutName(numChans int, mask uint32) string {
+	switch {
+	case numChans == 1 && (mask == 0 || mask == channelMaskMono):
+		return "mono"
+	case numChans == 2 && (mask == 0 || mask == channelMaskStereo):
+		return "stereo"
+	case numChans == 6 && (mask == 0 || mask == channelMask51Back || mask == channelMask51Side):
+		return "5.1"
+	case numChans == 8 && (mask == 0 || mask == channelMask71Surround || mask == channelMask71WideFront):
+		return "7.1"
+	default:
+		return fmt.Sprintf("%d channels", numChans)
+	}
+}
@@ -0,0 +1,134 @@
+package wav
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/go-audio/audio"
+)
+
+func TestChannelLayoutName(t *testing.T) {
+	testCases := []struct {
+		numChans int
+		mask     uint32
+		want     string
+	}{
+		{numChans: 1, want: "mono"},
+		{numChans: 1, mask: channelMaskMono, want: "mono"},
+		{numChans: 2, want: "stereo"},
+		{numChans: 2, mask: 0x3, want: "stereo"},
+		{numChans: 2, mask: SpeakerFrontLeft | SpeakerBackLeft, want: "2 channels"},
+		{numChans: 4, want: "4 channels"},
+		{numChans: 6, want: "5.1"},
+		{numChans: 6, mask: 0x3F, want: "5.1"},
+		{numChans: 6, mask: 0x60F, want: "5.1"},
+		{numChans: 6, mask: 0x63, want: "6 channels"},
+		{numChans: 8, want: "7.1"},
+		{numChans: 8, mask: 0x63F, want: "7.1"},
+		{numChans: 0, want: "0 channels"},
+	}
+
+	for _, testCase := range testCases {
+		got := channelLayoutName(testCase.numChans, testCase.mask)
+		if got != testCase.want {
+			t.Fatalf("channelLayoutName(%d, 0x%X) = %q, want %q", testCase.numChans, testCase.mask, got, testCase.want)
+		}
+	}
+}
+
+func TestDecoder_ChannelLayout(t *testing.T) {
+	testCases := []struct {
+		in   string
+		want string
+	}{
+		{in: "fixtures/kick.wav", want: "mono"},
+		{in: "fixtures/bass.wav", want: "stereo"},
+		{in: "fixtures/6_Channel_ID.wav", want: "5.1"},
+		{in: "fixtures/8_Channel_ID.wav", want: "8 channels"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.in, func(t *testing.T) {
+			file, err := os.Open(testCase.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer file.Close()
+
+			dec := NewDecoder(file)
+			dec.ReadInfo()
+
+			if got := dec.ChannelLayout(); got != testCase.want {
+				t.Fatalf("ChannelLayout() = %q, want %q", got, testCase.want)
+			}
+		})
+	}
+
+	var dec *Decoder
+	if got := dec.ChannelLayout(); got != "0 channels" {
+		t.Fatalf("nil decoder ChannelLayout() = %q", got)
+	}
+}
+
+func TestEncoder_AutoExtensible(t *testing.T) {
+	testCases := []struct {
+		name           string
+		numChans       int
+		bitDepth       int
+		autoExtensible bool
+		wantTag        uint16
+		wantMask       uint32
+	}{
+		{name: "5.1", numChans: 6, bitDepth: 16, autoExtensible: true, wantTag: wavFormatExtensible, wantMask: channelMask51Back},
+		{name: "stereo 24-bit", numChans: 2, bitDepth: 24, autoExtensible: true, wantTag: wavFormatExtensible, wantMask: channelMaskStereo},
+		{name: "stereo 16-bit", numChans: 2, bitDepth: 16, autoExtensible: true, wantTag: wavFormatPCM},
+		{name: "disabled", numChans: 6, bitDepth: 24, wantTag: wavFormatPCM},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			enc := NewMemoryEncoder(48000, testCase.bitDepth, testCase.numChans, wavFormatPCM)
+			enc.AutoExtensible = testCase.autoExtensible
+
+			frames := make([]float32, 4*testCase.numChans)
+			for i := range frames {
+				frames[i] = float32(i%7)/8 - 0.375
+			}
+
+			err := enc.Write(&audio.Float32Buffer{
+				Format: &audio.Format{NumChannels: testCase.numChans, SampleRate: 48000},
+				Data:   frames,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			data, err := enc.Close()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			dec := NewDecoder(bytes.NewReader(data))
+
+			buf, err := dec.FullPCMBuffer()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if dec.FmtChunk.FormatTag != testCase.wantTag {
+				t.Fatalf("expected format tag 0x%X, got 0x%X", testCase.wantTag, dec.FmtChunk.FormatTag)
+			}
+
+			if testCase.wantTag == wavFormatExtensible {
+				ext := dec.FmtChunk.Extensible
+				if ext == nil || ext.ChannelMask != testCase.wantMask || int(ext.ValidBitsPerSample) != testCase.bitDepth ||
+					dec.FmtChunk.EffectiveFormatTag() != wavFormatPCM {
+					t.Fatalf("unexpected extensible fmt chunk %+v", ext)
+				}
+			}
+
+			assertFloat32SlicesClose(t, buf.Data, frames, 1e-4)
+		})
+	}
+}
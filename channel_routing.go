@@ -0,0 +1,50 @@
+package wav
+
+import (
+	"errors"
+	"fmt"
+)
+
+var errInvalidChannelIndex = errors.New("invalid channel index")
+
+// channelRouting returns, for each channel of a buffer with numChannels
+// channels, the source channel to read and the sign to apply, according to
+// InvertChannels and SwapChannels. Inversion refers to the channels of the
+// passed buffer and is applied before swapping. It returns nil slices when no
+// routing is configured.
+func (e *Encoder) channelRouting(numChannels int) ([]int, []float32, error) {
+	if len(e.InvertChannels) == 0 && len(e.SwapChannels) == 0 {
+		return nil, nil, nil
+	}
+
+	valid := func(idx int) bool {
+		return idx >= 0 && idx < e.NumChans && idx < numChannels
+	}
+
+	source := make([]int, numChannels)
+	sign := make([]float32, numChannels)
+
+	for i := range source {
+		source[i] = i
+		sign[i] = 1
+	}
+
+	for _, idx := range e.InvertChannels {
+		if !valid(idx) {
+			return nil, nil, fmt.Errorf("%w: can't invert channel %d of %d", errInvalidChannelIndex, idx, e.NumChans)
+		}
+
+		sign[idx] = -sign[idx]
+	}
+
+	for _, pair := range e.SwapChannels {
+		if !valid(pair[0]) || !valid(pair[1]) {
+			return nil, nil, fmt.Errorf("%w: can't swap channels %d and %d of %d", errInvalidChannelIndex, pair[0], pair[1], e.NumChans)
+		}
+
+		source[pair[0]], source[pair[1]] = source[pair[1]], source[pair[0]]
+		sign[pair[0]], sign[pair[1]] = sign[pair[1]], sign[pair[0]]
+	}
+
+	return source, sign, nil
+}
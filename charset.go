@@ -0,0 +1,114 @@
+package wav
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/go-audio/riff"
+)
+
+// Code pages of the CSET chunk that are decoded.
+const (
+	CodePageDefault     = 0
+	CodePageWindows1252 = 1252
+	CodePageLatin1      = 28591
+	CodePageUTF8        = 65001
+)
+
+var (
+	// CIDCset is the chunk ID of the character set chunk.
+	CIDCset = [4]byte{'C', 'S', 'E', 'T'}
+
+	// ErrUnsupportedCodePage is reported as a decoder warning when the CSET
+	// chunk declares a code page that isn't decoded. Strings are then read
+	// as if no CSET chunk was present.
+	ErrUnsupportedCodePage = errors.New("unsupported CSET code page")
+)
+
+// windows1252High maps the bytes 0x80 to 0x9F of Windows-1252, which differ
+// from Latin-1. Undefined bytes map to U+FFFD.
+var windows1252High = [32]rune{
+	'€', '�', '‚', 'ƒ', '„', '…', '†', '‡', 'ˆ', '‰', 'Š', '‹', 'Œ', '�', 'Ž', '�',
+	'�', '‘', '’', '“', '”', '•', '–', '—', '˜', '™', 'š', '›', 'œ', '�', 'ž', 'Ÿ',
+}
+
+// DecodeCsetChunk decodes the CSET chunk and stores its code page on the
+// decoder. The country, language and dialect fields are ignored.
+func DecodeCsetChunk(d *Decoder, ch *riff.Chunk) error {
+	if ch == nil {
+		return errNilChunk
+	}
+
+	if d == nil {
+		return errNilDecoder
+	}
+
+	var codePage uint16
+
+	err := readChunk(ch, d.byteOrder(), &codePage)
+	if err != nil {
+		return fmt.Errorf("failed to read the CSET code page: %w", err)
+	}
+
+	ch.Drain()
+
+	d.CodePage = codePage
+
+	switch codePage {
+	case CodePageDefault, CodePageWindows1252, CodePageLatin1, CodePageUTF8:
+	default:
+		d.addWarning(fmt.Errorf("%w: %d", ErrUnsupportedCodePage, codePage))
+	}
+
+	return nil
+}
+
+// decodeText converts a NUL terminated string of an INFO, bext, cart or labl
+// entry to UTF-8 according to the CSET code page. Without a supported code
+// page the raw bytes are kept, unless SanitizeMetadata is set: then valid
+// UTF-8 is passed through and anything else is read as Latin-1.
+func (d *Decoder) decodeText(b []byte) string {
+	raw := b[:clen(b)]
+
+	var codePage uint16
+	if d != nil {
+		codePage = d.CodePage
+	}
+
+	switch codePage {
+	case CodePageUTF8:
+		return strings.ToValidUTF8(string(raw), "�")
+	case CodePageWindows1252:
+		return decodeSingleByte(raw, true)
+	case CodePageLatin1:
+		return decodeSingleByte(raw, false)
+	default:
+		if d == nil || !d.SanitizeMetadata || utf8.Valid(raw) {
+			return string(raw)
+		}
+
+		return decodeSingleByte(raw, false)
+	}
+}
+
+// decodeSingleByte decodes Latin-1, or Windows-1252 if cp1252 is set.
+func decodeSingleByte(raw []byte, cp1252 bool) string {
+	var sb strings.Builder
+
+	sb.Grow(len(raw))
+
+	for _, c := range raw {
+		switch {
+		case c < 0x80:
+			sb.WriteByte(c)
+		case cp1252 && c < 0xA0:
+			sb.WriteRune(windows1252High[c-0x80])
+		default:
+			sb.WriteRune(rune(c))
+		}
+	}
+
+	return sb.String()
+}
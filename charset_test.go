@@ -0,0 +1,128 @@
+package wav
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+)
+
+func makeWavWithCharset(t *testing.T, codePage int, title []byte) []byte {
+	t.Helper()
+
+	var b bytes.Buffer
+	b.WriteString("RIFF")
+	b.Write(make([]byte, 4))
+	b.WriteString("WAVE")
+
+	fmtPayload := make([]byte, 16)
+	binary.LittleEndian.PutUint16(fmtPayload[0:2], wavFormatPCM)
+	binary.LittleEndian.PutUint16(fmtPayload[2:4], 1)
+	binary.LittleEndian.PutUint32(fmtPayload[4:8], 8000)
+	binary.LittleEndian.PutUint32(fmtPayload[8:12], 16000)
+	binary.LittleEndian.PutUint16(fmtPayload[12:14], 2)
+	binary.LittleEndian.PutUint16(fmtPayload[14:16], 16)
+	writeTestChunk(t, &b, "fmt ", fmtPayload)
+
+	if codePage >= 0 {
+		cset := make([]byte, 8)
+		binary.LittleEndian.PutUint16(cset[0:2], uint16(codePage))
+		writeTestChunk(t, &b, "CSET", cset)
+	}
+
+	entry := append(append([]byte(nil), title...), 0)
+	list := []byte("INFOINAM")
+	list = binary.LittleEndian.AppendUint32(list, uint32(len(entry)))
+	list = append(list, entry...)
+	writeTestChunk(t, &b, "LIST", list)
+
+	bext := encodeBroadcastChunk(&BroadcastExtension{})
+	copy(bext, title)
+	writeTestChunk(t, &b, "bext", bext)
+
+	cart := encodeCartChunk(&Cart{})
+	copy(cart[cartVersionLen:], title)
+	writeTestChunk(t, &b, "cart", cart)
+	writeTestChunk(t, &b, "data", make([]byte, 4))
+
+	data := b.Bytes()
+	binary.LittleEndian.PutUint32(data[4:8], uint32(len(data)-8))
+
+	return data
+}
+
+func TestDecoder_CsetCodePage(t *testing.T) {
+	testCases := []struct {
+		name     string
+		codePage int
+		sanitize bool
+		title    []byte
+		want     string
+	}{
+		{"no CSET, UTF-8", -1, false, []byte("Caf\xc3\xa9"), "Café"},
+		{"no CSET, Latin-1 raw", -1, false, []byte("Caf\xe9"), "Caf\xe9"},
+		{"no CSET, Latin-1 sanitized", -1, true, []byte("Caf\xe9"), "Café"},
+		{"no CSET, UTF-8 sanitized", -1, true, []byte("Caf\xc3\xa9"), "Café"},
+		{"Windows-1252", CodePageWindows1252, false, []byte("\x80 Caf\xe9 \x93x\x94"), "€ Café “x”"},
+		{"Latin-1", CodePageLatin1, false, []byte("Caf\xc3\xa9"), "CafÃ©"},
+		{"UTF-8", CodePageUTF8, false, []byte("Caf\xc3\xa9 \xff"), "Café �"},
+		{"UTF-8 sanitized", CodePageUTF8, true, []byte("Caf\xc3\xa9 \xff"), "Café �"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			dec := NewDecoder(bytes.NewReader(makeWavWithCharset(t, testCase.codePage, testCase.title)))
+			dec.SanitizeMetadata = testCase.sanitize
+			dec.ReadMetadata()
+
+			if err := dec.Err(); err != nil {
+				t.Fatal(err)
+			}
+
+			if testCase.codePage >= 0 && dec.CodePage != uint16(testCase.codePage) {
+				t.Fatalf("expected code page %d, got %d", testCase.codePage, dec.CodePage)
+			}
+
+			if dec.Metadata == nil || dec.Metadata.Title != testCase.want {
+				t.Fatalf("expected title %q, got %+v", testCase.want, dec.Metadata)
+			}
+
+			if dec.Metadata.BroadcastExtension == nil || dec.Metadata.BroadcastExtension.Description != testCase.want {
+				t.Fatalf("expected bext description %q, got %+v", testCase.want, dec.Metadata.BroadcastExtension)
+			}
+
+			if dec.Metadata.Cart == nil || dec.Metadata.Cart.Title != testCase.want {
+				t.Fatalf("expected cart title %q, got %+v", testCase.want, dec.Metadata.Cart)
+			}
+
+			if len(dec.Warnings()) != 0 {
+				t.Fatalf("unexpected warnings: %v", dec.Warnings())
+			}
+		})
+	}
+
+	dec := NewDecoder(bytes.NewReader(makeWavWithCharset(t, 932, []byte("Caf\xe9"))))
+	dec.ReadMetadata()
+
+	if dec.Metadata == nil || dec.Metadata.Title != "Caf\xe9" {
+		t.Fatalf("expected the raw bytes for an unsupported code page, got %+v", dec.Metadata)
+	}
+
+	sanitized := NewDecoder(bytes.NewReader(makeWavWithCharset(t, 932, []byte("Caf\xe9"))))
+	sanitized.SanitizeMetadata = true
+	sanitized.ReadMetadata()
+
+	if sanitized.Metadata == nil || sanitized.Metadata.Title != "Café" {
+		t.Fatalf("expected the Latin-1 fallback for an unsupported code page, got %+v", sanitized.Metadata)
+	}
+
+	var found bool
+
+	for _, warning := range dec.Warnings() {
+		found = found || errors.Is(warning, ErrUnsupportedCodePage)
+	}
+
+	if !found {
+		t.Fatalf("expected an ErrUnsupportedCodePage warning, got %v", dec.Warnings())
+	}
+}
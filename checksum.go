@@ -0,0 +1,71 @@
+package wav
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+
+	"github.com/go-audio/riff"
+)
+
+var (
+	// ErrChecksumNotFound is returned by VerifyChecksum for files without a
+	// csum chunk.
+	ErrChecksumNotFound = errors.New("csum chunk not found")
+	errChecksumSize     = errors.New("invalid csum chunk size")
+)
+
+// VerifyChecksum recomputes the CRC-32 of the data chunk payload and compares
+// it with the csum chunk written by Encoder.WriteChecksum. It returns
+// ErrChecksumNotFound when the file has no csum chunk. The decoder is reset
+// afterwards, see ForEachChunk.
+func (d *Decoder) VerifyChecksum() (bool, error) {
+	var (
+		stored, computed  uint32
+		haveSum, haveData bool
+	)
+
+	err := d.ForEachChunk(func(id [4]byte, r io.Reader) error {
+		switch id {
+		case CIDCsum:
+			payload, err := io.ReadAll(r)
+			if err != nil {
+				return fmt.Errorf("failed to read checksum: %w", err)
+			}
+
+			if len(payload) != 4 {
+				return fmt.Errorf("%w: %d", errChecksumSize, len(payload))
+			}
+
+			stored = binary.LittleEndian.Uint32(payload)
+			haveSum = true
+		case riff.DataFormatID:
+			crc := crc32.NewIEEE()
+
+			_, err := io.Copy(crc, r)
+			if err != nil {
+				return fmt.Errorf("failed to read data chunk: %w", err)
+			}
+
+			computed = crc.Sum32()
+			haveData = true
+		}
+
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	if !haveSum {
+		return false, ErrChecksumNotFound
+	}
+
+	if !haveData {
+		return false, ErrPCMChunkNotFound
+	}
+
+	return stored == computed, nil
+}
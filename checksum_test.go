@@ -0,0 +1,144 @@
+package wav
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/go-audio/audio"
+)
+
+func encodeWithChecksum(t *testing.T) []byte {
+	t.Helper()
+
+	enc := NewMemoryEncoder(8000, 16, 1, wavFormatPCM)
+	enc.WriteChecksum = true
+
+	err := enc.Write(&audio.Float32Buffer{
+		Format: &audio.Format{NumChannels: 1, SampleRate: 8000},
+		Data:   []float32{0.5, -0.5, 0.25},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = enc.WriteSilence(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = enc.WriteFrame(float32(0.75))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := enc.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return data
+}
+
+func TestDecoder_VerifyChecksum(t *testing.T) {
+	data := encodeWithChecksum(t)
+
+	chunks, err := parseWavChunks(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ch, _ := findChunk(chunks, "csum"); ch == nil || len(ch.data) != 4 {
+		t.Fatalf("expected a 4 byte csum chunk, got %+v", ch)
+	}
+
+	dec := NewDecoder(bytes.NewReader(data))
+
+	ok, err := dec.VerifyChecksum()
+	if err != nil || !ok {
+		t.Fatalf("expected a matching checksum, got %v, %v", ok, err)
+	}
+
+	// the decoder must still be usable after verification
+	buf, err := dec.FullPCMBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if buf.NumFrames() != 6 {
+		t.Fatalf("expected 6 frames, got %d", buf.NumFrames())
+	}
+
+	_, dataPos := findChunk(chunks, "data")
+	offset := 12
+
+	for _, ch := range chunks[:dataPos] {
+		offset += 8 + len(ch.data) + len(ch.data)%2
+	}
+
+	corrupted := append([]byte(nil), data...)
+	corrupted[offset+8] ^= 0x01
+
+	ok, err = NewDecoder(bytes.NewReader(corrupted)).VerifyChecksum()
+	if err != nil || ok {
+		t.Fatalf("expected a checksum mismatch, got %v, %v", ok, err)
+	}
+
+	_, err = NewDecoder(bytes.NewReader(makeWavWithUnknownChunks(t))).VerifyChecksum()
+	if !errors.Is(err, ErrChecksumNotFound) {
+		t.Fatalf("expected ErrChecksumNotFound, got %v", err)
+	}
+}
+
+func TestEncoder_WriteChecksumReplacesPreservedChunk(t *testing.T) {
+	dec := NewDecoder(bytes.NewReader(encodeWithChecksum(t)))
+	dec.ReadMetadata()
+
+	err := dec.Rewind()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf, err := dec.FullPCMBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	enc := NewMemoryEncoder(8000, 16, 1, wavFormatPCM)
+	enc.UnknownChunks = dec.UnknownChunks
+	enc.WriteChecksum = true
+
+	buf.Data[0] = 0
+
+	err = enc.Write(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := enc.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chunks, err := parseWavChunks(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var sums int
+
+	for _, ch := range chunks {
+		if ch.id == "csum" {
+			sums++
+		}
+	}
+
+	if sums != 1 {
+		t.Fatalf("expected a single csum chunk, got %d", sums)
+	}
+
+	ok, err := NewDecoder(bytes.NewReader(data)).VerifyChecksum()
+	if err != nil || !ok {
+		t.Fatalf("expected a matching checksum, got %v, %v", ok, err)
+	}
+}
@@ -0,0 +1,96 @@
+package wav
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/go-audio/riff"
+)
+
+// ForEachChunk walks all top-level chunks following the RIFF/RIFX header,
+// including fmt and data, and calls fn with the chunk id and a reader limited
+// to the chunk payload. Whatever fn leaves unread is drained, pad bytes of odd
+// sized chunks are skipped. Iteration stops at the first error returned by
+// fn. Afterwards the reader is seeked back to the start and the parser state
+// is reset, so the decoder can be used or rewound as usual.
+func (d *Decoder) ForEachChunk(fn func(id [4]byte, r io.Reader) error) error {
+	if d == nil || d.r == nil {
+		return errNilDecoder
+	}
+
+	_, err := d.r.Seek(0, io.SeekStart)
+	if err != nil {
+		return fmt.Errorf("failed to seek back to the start %w", err)
+	}
+
+	d.resetParser()
+
+	err = d.walkChunks(fn)
+
+	_, seekErr := d.r.Seek(0, io.SeekStart)
+	d.resetParser()
+
+	if err != nil {
+		return err
+	}
+
+	if seekErr != nil {
+		return fmt.Errorf("failed to seek back to the start %w", seekErr)
+	}
+
+	return nil
+}
+
+func (d *Decoder) walkChunks(fn func(id [4]byte, r io.Reader) error) error {
+	id, _, err := d.parser.IDnSize()
+	if err != nil {
+		return fmt.Errorf("failed to read chunk ID and size: %w", err)
+	}
+
+	switch id {
+	case riff.RiffID:
+		d.bigEndian = false
+	case RIFXID:
+		d.bigEndian = true
+	default:
+		return fmt.Errorf("%s - %w", id, riff.ErrFmtNotSupported)
+	}
+
+	var format [4]byte
+
+	_, err = io.ReadFull(d.r, format[:])
+	if err != nil {
+		return fmt.Errorf("failed to read format: %w", err)
+	}
+
+	for {
+		id, size, err := d.readChunkHeader()
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil
+		}
+
+		if err != nil {
+			return fmt.Errorf("error reading chunk header - %w", err)
+		}
+
+		payload := &io.LimitedReader{R: d.r, N: int64(size)}
+
+		err = fn(id, payload)
+		if err != nil {
+			return fmt.Errorf("%q chunk: %w", id, err)
+		}
+
+		_, err = io.Copy(io.Discard, io.LimitReader(d.r, payload.N+int64(size%2)))
+		if err != nil {
+			return fmt.Errorf("failed to drain %q chunk: %w", id, err)
+		}
+
+		if id == riff.FmtID {
+			_, err = io.CopyN(io.Discard, d.r, d.fmtChunkSlack())
+			if err != nil {
+				return fmt.Errorf("failed to skip undeclared fmt bytes: %w", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package wav
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/go-audio/riff"
+)
+
+// ChunkInfo locates a top-level chunk in the file, see Decoder.ChunkLayout.
+type ChunkInfo struct {
+	ID [4]byte
+	// Offset is the file offset of the chunk header, the payload starts 8
+	// bytes later.
+	Offset int64
+	// Size is the declared payload size, without the pad byte.
+	Size uint32
+	// Padded reports an odd size followed by a pad byte.
+	Padded bool
+}
+
+// ChunkLayout returns the location of every top-level chunk in file order. It
+// reads only the chunk headers and seeks over the payloads, so tools can patch
+// a single chunk in place without reading the whole file. The last chunk may
+// extend past the end of a truncated file. Like ForEachChunk, it leaves the
+// decoder reset to the start of the file.
+func (d *Decoder) ChunkLayout() ([]ChunkInfo, error) {
+	if d == nil || d.r == nil {
+		return nil, errNilDecoder
+	}
+
+	_, err := d.r.Seek(0, io.SeekStart)
+	if err != nil {
+		return nil, fmt.Errorf("failed to seek back to the start %w", err)
+	}
+
+	d.resetParser()
+
+	chunks, err := d.scanChunkLayout()
+
+	_, seekErr := d.r.Seek(0, io.SeekStart)
+	d.resetParser()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if seekErr != nil {
+		return nil, fmt.Errorf("failed to seek back to the start %w", seekErr)
+	}
+
+	return chunks, nil
+}
+
+func (d *Decoder) scanChunkLayout() ([]ChunkInfo, error) {
+	var header [12]byte
+
+	_, err := io.ReadFull(d.r, header[:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the RIFF header: %w", err)
+	}
+
+	var order binary.ByteOrder = binary.LittleEndian
+
+	switch [4]byte(header[:4]) {
+	case riff.RiffID:
+	case RIFXID:
+		order = binary.BigEndian
+	default:
+		return nil, fmt.Errorf("%s - %w", header[:4], riff.ErrFmtNotSupported)
+	}
+
+	var chunks []ChunkInfo
+
+	offset := int64(len(header))
+
+	for {
+		var chunkHeader [8]byte
+
+		_, err := io.ReadFull(d.r, chunkHeader[:])
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return chunks, nil
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("error reading chunk header - %w", err)
+		}
+
+		info := ChunkInfo{
+			ID:     [4]byte(chunkHeader[:4]),
+			Offset: offset,
+			Size:   order.Uint32(chunkHeader[4:]),
+		}
+		info.Padded = info.Size%2 == 1
+		chunks = append(chunks, info)
+
+		offset += 8 + int64(info.Size) + int64(info.Size%2)
+
+		_, err = d.r.Seek(offset, io.SeekStart)
+		if err != nil {
+			return nil, fmt.Errorf("failed to skip %q chunk: %w", info.ID, err)
+		}
+
+		if info.ID == riff.FmtID {
+			offset += d.fmtChunkSlack()
+
+			_, err = d.r.Seek(offset, io.SeekStart)
+			if err != nil {
+				return nil, fmt.Errorf("failed to skip undeclared fmt bytes: %w", err)
+			}
+		}
+	}
+}
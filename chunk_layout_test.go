@@ -0,0 +1,105 @@
+package wav
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"os"
+	"testing"
+)
+
+func TestDecoder_ChunkLayout(t *testing.T) {
+	enc := NewMemoryEncoder(8000, 16, 1, wavFormatPCM)
+	enc.AppendRawChunk([4]byte{'o', 'd', 'd', ' '}, []byte{1, 2, 3}, true)
+	enc.AppendRawChunk([4]byte{'t', 'a', 'i', 'l'}, []byte{4, 5}, false)
+
+	err := enc.WriteSilence(5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := enc.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dec := NewDecoder(bytes.NewReader(data))
+
+	layout, err := dec.ChunkLayout()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantIDs := []string{"fmt ", "odd ", "data", "tail"}
+	if len(layout) != len(wantIDs) {
+		t.Fatalf("expected %d chunks, got %+v", len(wantIDs), layout)
+	}
+
+	for i, info := range layout {
+		if string(info.ID[:]) != wantIDs[i] {
+			t.Fatalf("chunk %d: expected %q, got %q", i, wantIDs[i], info.ID)
+		}
+
+		if got := binary.LittleEndian.Uint32(data[info.Offset+4:]); got != info.Size {
+			t.Fatalf("%q: size %d doesn't match the header %d", info.ID, info.Size, got)
+		}
+
+		if info.Padded != (info.Size%2 == 1) {
+			t.Fatalf("%q: unexpected padding flag", info.ID)
+		}
+	}
+
+	odd := layout[1]
+	if !bytes.Equal(data[odd.Offset+8:odd.Offset+8+int64(odd.Size)], []byte{1, 2, 3}) || !odd.Padded {
+		t.Fatalf("unexpected odd chunk %+v", odd)
+	}
+
+	// the decoder is reset afterwards
+	buf, err := dec.FullPCMBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if buf.NumFrames() != 5 {
+		t.Fatalf("expected 5 frames, got %d", buf.NumFrames())
+	}
+}
+
+func TestDecoder_ChunkLayoutMatchesForEachChunk(t *testing.T) {
+	for _, fixture := range []string{"fixtures/bwf.wav", "fixtures/fmt-size-lie.wav", "fixtures/trailing-padded-chunks.wav"} {
+		t.Run(fixture, func(t *testing.T) {
+			data, err := os.ReadFile(fixture)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			dec := NewDecoder(bytes.NewReader(data))
+
+			var ids [][4]byte
+
+			err = dec.ForEachChunk(func(id [4]byte, _ io.Reader) error {
+				ids = append(ids, id)
+
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			layout, err := dec.ChunkLayout()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(layout) != len(ids) {
+				t.Fatalf("expected %d chunks, got %d", len(ids), len(layout))
+			}
+
+			for i, info := range layout {
+				if info.ID != ids[i] || !bytes.Equal(data[info.Offset:info.Offset+4], info.ID[:]) {
+					t.Fatalf("chunk %d: expected %q at offset %d, got %q", i, ids[i], info.Offset, info.ID)
+				}
+			}
+		})
+	}
+}
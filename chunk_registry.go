@@ -33,6 +33,7 @@ func newDefaultChunkRegistry() *ChunkRegistry {
 			&cueChunkHandler{},
 			&bextChunkHandler{},
 			&cartChunkHandler{},
+			&csetChunkHandler{},
 		},
 	}
 }
@@ -104,10 +105,12 @@ func (h *factChunkHandler) Decode(dec *Decoder, chunk *riff.Chunk) error {
 		return nil
 	}
 
+	dec.HadFactChunk = true
+
 	var sampleCount uint32
 
-	err := chunk.ReadLE(&sampleCount)
-	if err == nil {
+	err := readChunk(chunk, dec.byteOrder(), &sampleCount)
+	if err == nil && dec.factSamplesOverride == nil {
 		dec.CompressedSamples = sampleCount
 	}
 
@@ -144,8 +147,12 @@ func (h *smplChunkHandler) Decode(d *Decoder, ch *riff.Chunk) error {
 	return DecodeSamplerChunk(d, ch)
 }
 
-func (h *smplChunkHandler) Encode(_ *Encoder) error {
-	return errChunkEncodeNotSupported
+func (h *smplChunkHandler) Encode(e *Encoder) error {
+	if e == nil || e.Metadata == nil || e.Metadata.SamplerInfo == nil {
+		return nil
+	}
+
+	return e.writeRawChunk(RawChunk{ID: CIDSmpl, Data: encodeSamplerChunk(e.Metadata.SamplerInfo)})
 }
 
 type cueChunkHandler struct{}
@@ -158,8 +165,22 @@ func (h *cueChunkHandler) Decode(d *Decoder, ch *riff.Chunk) error {
 	return DecodeCueChunk(d, ch)
 }
 
-func (h *cueChunkHandler) Encode(_ *Encoder) error {
-	return errChunkEncodeNotSupported
+func (h *cueChunkHandler) Encode(e *Encoder) error {
+	if e == nil || e.Metadata == nil || len(e.Metadata.CuePoints) == 0 {
+		return nil
+	}
+
+	err := e.writeRawChunk(RawChunk{ID: CIDCue, Data: encodeCueChunk(e.Metadata.CuePoints)})
+	if err != nil {
+		return err
+	}
+
+	adtl := encodeAdtlList(e.Metadata.CuePoints)
+	if adtl == nil {
+		return nil
+	}
+
+	return e.writeRawChunk(RawChunk{ID: CIDList, Data: adtl})
 }
 
 type bextChunkHandler struct{}
@@ -197,3 +218,17 @@ func (h *cartChunkHandler) Encode(e *Encoder) error {
 
 	return e.writeRawChunk(RawChunk{ID: CIDCart, Data: encodeCartChunk(e.Metadata.Cart)})
 }
+
+type csetChunkHandler struct{}
+
+func (h *csetChunkHandler) CanHandle(chunkID [4]byte, _ [4]byte) bool {
+	return chunkID == CIDCset
+}
+
+func (h *csetChunkHandler) Decode(d *Decoder, ch *riff.Chunk) error {
+	return DecodeCsetChunk(d, ch)
+}
+
+func (h *csetChunkHandler) Encode(_ *Encoder) error {
+	return errChunkEncodeNotSupported
+}
@@ -0,0 +1,41 @@
+package wav
+
+// ClipCount returns the number of samples decoded so far that sit at full
+// scale: the largest positive or negative value of the valid bits for integer
+// PCM, or at or beyond ±1.0 for float. Many of them indicate a source that
+// was recorded into clipping. Call it after decoding the whole data chunk,
+// e.g. with FullPCMBuffer. Compressed formats always report 0 and rewinding
+// resets the count.
+func (d *Decoder) ClipCount() int {
+	if d == nil {
+		return 0
+	}
+
+	return d.clipCount
+}
+
+// countClips adds the full scale samples in samples to the clip count. It
+// runs before the gain, so the samples still hold the source levels.
+func (d *Decoder) countClips(samples []float32) {
+	var positive float32
+
+	switch d.WavAudioFormat {
+	case wavFormatIEEEFloat:
+		positive = 1
+	case wavFormatPCM:
+		positive = 1
+
+		// signed samples can't reach +1, 8-bit samples are centered at 127.5
+		if bits := d.validBitsPerSample(); bits > 8 {
+			positive = float32(1 - 1/float64(int64(1)<<(bits-1)))
+		}
+	default:
+		return
+	}
+
+	for _, val := range samples {
+		if val >= positive || val <= -1 {
+			d.clipCount++
+		}
+	}
+}
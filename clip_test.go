@@ -0,0 +1,69 @@
+package wav
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/go-audio/audio"
+)
+
+func TestDecoder_ClipCount(t *testing.T) {
+	testCases := []struct {
+		name        string
+		bitDepth    int
+		audioFormat int
+		data        []float32
+		want        int
+	}{
+		{"pcm8", 8, wavFormatPCM, []float32{1, -1, 0.99, 0}, 2},
+		{"pcm16", 16, wavFormatPCM, []float32{1, -1, 32766.0 / 32768, 0}, 2},
+		{"pcm24", 24, wavFormatPCM, []float32{1, 0.5, -1, -0.999}, 2},
+		{"float32", 32, wavFormatIEEEFloat, []float32{1, -1, 1.5, 0.99}, 3},
+		{"alaw", 8, wavFormatALaw, []float32{1, -1, 0, 0}, 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			enc := NewMemoryEncoder(8000, tc.bitDepth, 1, tc.audioFormat)
+
+			err := enc.Write(&audio.Float32Buffer{
+				Format: &audio.Format{NumChannels: 1, SampleRate: 8000},
+				Data:   tc.data,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			data, err := enc.Close()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			dec := NewDecoder(bytes.NewReader(data))
+
+			_, err = dec.FullPCMBuffer()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if dec.ClipCount() != tc.want {
+				t.Fatalf("expected %d clipped samples, got %d", tc.want, dec.ClipCount())
+			}
+
+			// decoding again after a rewind doesn't count twice
+			err = dec.Rewind()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = dec.FullPCMBuffer()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if dec.ClipCount() != tc.want {
+				t.Fatalf("expected %d clipped samples after rewinding, got %d", tc.want, dec.ClipCount())
+			}
+		})
+	}
+}
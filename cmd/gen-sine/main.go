@@ -64,5 +64,7 @@ func run(args []string) (err error) {
 		return fmt.Errorf("failed to close encoder: %w", err)
 	}
 
+	log.Printf("wrote %d frames, %d bytes of audio", wavOut.FramesWritten(), wavOut.DataBytesWritten())
+
 	return nil
 }
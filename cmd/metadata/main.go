@@ -3,6 +3,7 @@ package main
 
 import (
 	"errors"
+	"flag"
 	"fmt"
 	"io"
 	"log"
@@ -30,6 +31,16 @@ func main() {
 var errMissingPath = errors.New("missing path argument")
 
 func run(args []string, out io.Writer) (err error) {
+	flagSet := flag.NewFlagSet("metadata", flag.ContinueOnError)
+
+	asJSON := flagSet.Bool("json", false, "print the metadata as JSON")
+
+	err = flagSet.Parse(args)
+	if err != nil {
+		return fmt.Errorf("failed to parse flags: %w", err)
+	}
+
+	args = flagSet.Args()
 	if len(args) < 1 {
 		return errMissingPath
 	}
@@ -47,6 +58,23 @@ func run(args []string, out io.Writer) (err error) {
 	}()
 
 	dec := wav.NewDecoder(file)
+
+	if *asJSON {
+		data, err := dec.MetadataJSON()
+		if err != nil {
+			return fmt.Errorf("failed to read metadata: %w", err)
+		}
+
+		_, _ = fmt.Fprintln(out, string(data))
+
+		return nil
+	}
+
+	stat, err := dec.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to read file layout: %w", err)
+	}
+
 	dec.ReadMetadata()
 
 	err = dec.Err()
@@ -54,6 +82,14 @@ func run(args []string, out io.Writer) (err error) {
 		return fmt.Errorf("failed to read metadata: %w", err)
 	}
 
+	_, _ = fmt.Fprintf(out, "Format: %s\n", stat.Format)
+	_, _ = fmt.Fprintf(out, "Channels: %s\n", stat.ChannelLayout)
+	_, _ = fmt.Fprintf(out, "Sample rate: %d Hz\n", stat.SampleRate)
+	_, _ = fmt.Fprintf(out, "Bit depth: %d\n", stat.BitDepth)
+	_, _ = fmt.Fprintf(out, "Duration: %s (%d frames)\n", stat.Duration, stat.Frames)
+	_, _ = fmt.Fprintf(out, "Data: %d bytes at offset %d\n", stat.DataSize, stat.DataOffset)
+	_, _ = fmt.Fprintf(out, "Chunks: %q\n", stat.Chunks)
+
 	if dec.Metadata == nil {
 		_, _ = fmt.Fprintln(out, "No metadata present")
 		return nil
@@ -75,6 +111,8 @@ func run(args []string, out io.Writer) (err error) {
 	_, _ = fmt.Fprintf(out, "Source: %s\n", dec.Metadata.Source)
 	_, _ = fmt.Fprintf(out, "Location: %s\n", dec.Metadata.Location)
 	_, _ = fmt.Fprintf(out, "TrackNbr: %s\n", dec.Metadata.TrackNbr)
+	_, _ = fmt.Fprintf(out, "SMPTETimecode: %s\n", dec.Metadata.SMPTETimecode)
+	_, _ = fmt.Fprintf(out, "DigitizationTime: %s\n", dec.Metadata.DigitizationTime)
 
 	_, _ = fmt.Fprintln(out, "Sample Info:")
 	_, _ = fmt.Fprintf(out, "%+v\n", dec.Metadata.SamplerInfo)
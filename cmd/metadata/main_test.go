@@ -2,6 +2,7 @@ package main
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
 	"strings"
 	"testing"
@@ -56,6 +57,12 @@ func TestRunNoMetadata(t *testing.T) {
 	if !strings.Contains(out, "No metadata present") {
 		t.Fatalf("expected 'No metadata present' in output, got:\n%s", out)
 	}
+
+	for _, want := range []string{"Format: PCM", "Channels: mono", "Sample rate: 22050 Hz", "Data: 8968 bytes at offset 44", `Chunks: ["fmt " "data"]`} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in output, got:\n%s", want, out)
+		}
+	}
 }
 
 func TestRunInvalidPath(t *testing.T) {
@@ -93,6 +100,8 @@ func TestRunPrintsAllMetadataFields(t *testing.T) {
 		"Source:",
 		"Location:",
 		"TrackNbr:",
+		"SMPTETimecode:",
+		"DigitizationTime:",
 		"Sample Info:",
 	}
 
@@ -102,3 +111,23 @@ func TestRunPrintsAllMetadataFields(t *testing.T) {
 		}
 	}
 }
+
+func TestRunPrintsJSON(t *testing.T) {
+	var outBuf bytes.Buffer
+
+	err := run([]string{"-json", "../../fixtures/listinfo.wav"}, &outBuf)
+	if err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+
+	var got map[string]any
+
+	err = json.Unmarshal(outBuf.Bytes(), &got)
+	if err != nil {
+		t.Fatalf("output isn't valid JSON: %v\n%s", err, outBuf.String())
+	}
+
+	if got["artist"] != "artist" || got["title"] != "track title" {
+		t.Fatalf("unexpected JSON output:\n%s", outBuf.String())
+	}
+}
@@ -74,6 +74,12 @@ func tagFile(path string) error {
 	}
 
 	decoder := wav.NewDecoder(in)
+	decoder.ReadMetadata()
+
+	err = decoder.Rewind()
+	if err != nil {
+		return fmt.Errorf("couldn't read %s %w", path, err)
+	}
 
 	buf, err := decoder.FullPCMBuffer()
 	if err != nil {
@@ -117,10 +123,11 @@ func tagFile(path string) error {
 		return fmt.Errorf("failed to write audio buffer - %w", err)
 	}
 
-	encoder.Metadata = &wav.Metadata{}
-	if *flagArtist != "" {
-		encoder.Metadata.Artist = *flagArtist
-	}
+	builder := wav.NewMetadataBuilder().
+		Artist(*flagArtist).
+		Comments(*flagComments).
+		Copyright(*flagCopyright).
+		Genre(*flagGenre)
 
 	if *flagTitleRegexp != "" {
 		filename := filepath.Base(path)
@@ -129,26 +136,25 @@ func tagFile(path string) error {
 
 		matches := re.FindStringSubmatch(filename)
 		if len(matches) > 0 {
-			encoder.Metadata.Title = matches[1]
+			builder.Title(matches[1])
 		} else {
 			fmt.Printf("No matches for title regexp %s in %s\n", *flagTitleRegexp, filename)
 		}
 	}
 
 	if *flagTitle != "" {
-		encoder.Metadata.Title = *flagTitle
+		builder.Title(*flagTitle)
 	}
 
-	if *flagComments != "" {
-		encoder.Metadata.Comments = *flagComments
-	}
+	metadata := builder.Build()
 
-	if *flagCopyright != "" {
-		encoder.Metadata.Copyright = *flagCopyright
+	for _, change := range decoder.Metadata.Diff(metadata) {
+		fmt.Printf("%s: %s %q -> %q\n", filepath.Base(path), change.Field, change.Old, change.New)
 	}
 
-	if *flagGenre != "" {
-		encoder.Metadata.Genre = *flagGenre
+	err = encoder.SetMetadata(metadata)
+	if err != nil {
+		return fmt.Errorf("invalid metadata for %s - %w", outPath, err)
 	}
 
 	err = encoder.Close()
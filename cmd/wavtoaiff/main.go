@@ -8,7 +8,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"math"
 	"os"
 	"os/user"
 	"path/filepath"
@@ -44,6 +43,7 @@ var (
 	errMissingPath    = errors.New("missing -path flag")
 	errResolveHomeDir = errors.New("failed to resolve current user")
 	errInvalidWAVFile = errors.New("invalid WAV file")
+	errBitDepth       = errors.New("unsupported AIFF bit depth")
 )
 
 func run(args []string, currentUser func() (*user.User, error), out io.Writer) error {
@@ -81,6 +81,25 @@ func run(args []string, currentUser func() (*user.User, error), out io.Writer) e
 		return errInvalidWAVFile
 	}
 
+	decoder.ReadMetadata()
+
+	err = decoder.Err()
+	if err != nil {
+		return fmt.Errorf("failed to read WAV metadata: %w", err)
+	}
+
+	markers, inst := aiffMarkersFromMetadata(decoder.Metadata)
+
+	err = decoder.Rewind()
+	if err != nil {
+		return fmt.Errorf("failed to rewind WAV file: %w", err)
+	}
+
+	bitDepth := int(decoder.BitDepth)
+	if bitDepth < 8 || bitDepth > 32 {
+		return fmt.Errorf("%w: %d", errBitDepth, bitDepth)
+	}
+
 	outPath := sourcePath[:len(sourcePath)-len(filepath.Ext(sourcePath))] + ".aif"
 
 	outFile, err := os.Create(outPath)
@@ -89,35 +108,39 @@ func run(args []string, currentUser func() (*user.User, error), out io.Writer) e
 	}
 	defer outFile.Close()
 
-	encoder := aiff.NewEncoder(outFile, int(decoder.SampleRate), int(decoder.BitDepth), int(decoder.NumChans))
+	encoder := aiff.NewEncoder(outFile, int(decoder.SampleRate), bitDepth, int(decoder.NumChans))
 
-	format := &audio.Format{
-		NumChannels: int(decoder.NumChans),
-		SampleRate:  int(decoder.SampleRate),
-	}
+	sampleEncoding := decoder.SampleEncoding()
+	// integer PCM is copied as is, float and compressed data is quantized
+	intSource := !sampleEncoding.Float && !sampleEncoding.Compressed && bitDepth%8 == 0
 
 	bufferSize := 1000000
-	buf := &audio.Float32Buffer{Data: make([]float32, bufferSize), Format: format}
-
-	var num int
-	for err == nil {
-		num, err = decoder.PCMBuffer(buf)
-		if err != nil {
-			break
+	floatBuf := &audio.Float32Buffer{Data: make([]float32, bufferSize)}
+	intData := make([]int, bufferSize)
+
+	for {
+		var (
+			intBuf *audio.IntBuffer
+			num    int
+		)
+
+		if intSource {
+			intBuf = &audio.IntBuffer{Data: intData}
+			num, err = decoder.PCMIntBuffer(intBuf)
+			intBuf.Data = intBuf.Data[:max(num, 0)]
+		} else {
+			num, err = decoder.PCMBuffer(floatBuf)
+			intBuf = wav.Float32BufferToIntBuffer(&audio.Float32Buffer{
+				Format: floatBuf.Format,
+				Data:   floatBuf.Data[:max(num, 0)],
+			}, bitDepth)
 		}
 
-		if num == 0 {
+		if err != nil || num == 0 {
 			break
 		}
 
-		data := buf.Data
-		if num != len(data) {
-			data = data[:num]
-		}
-
-		intBuf := float32ToIntBuffer(data, format, int(decoder.BitDepth))
-
-		err := encoder.Write(intBuf)
+		err = encoder.Write(aiffIntBuffer(intBuf))
 		if err != nil {
 			return fmt.Errorf("failed to write AIFF data: %w", err)
 		}
@@ -128,95 +151,24 @@ func run(args []string, currentUser func() (*user.User, error), out io.Writer) e
 		return fmt.Errorf("failed to close AIFF encoder: %w", err)
 	}
 
-	fmt.Fprintf(out, "Wav file converted to %s\n", outPath)
-
-	return nil
-}
-
-func float32ToIntBuffer(data []float32, format *audio.Format, bitDepth int) *audio.IntBuffer {
-	intBuf := &audio.IntBuffer{
-		Format:         format,
-		SourceBitDepth: bitDepth,
-		Data:           make([]int, len(data)),
-	}
-	for i, v := range data {
-		intBuf.Data[i] = float32ToPCMInt(v, bitDepth)
-	}
-
-	return intBuf
-}
-
-func float32ToPCMInt(value float32, bitDepth int) int {
-	value = clampFloat32(value)
-
-	switch bitDepth {
-	case 8:
-		return int(float32ToPCMUint8(value))
-	case 16:
-		return int(float32ToPCMInt32(value, 16))
-	case 24:
-		return int(float32ToPCMInt32(value, 24))
-	case 32:
-		return int(float32ToPCMInt32(value, 32))
-	default:
-		return 0
-	}
-}
-
-func float32ToPCMUint8(value float32) uint8 {
-	value = clampFloat32(value)
-
-	scaled := int(math.Round(float64((value + 1.0) * 127.5)))
-	if scaled < 0 {
-		return 0
-	}
-
-	if scaled > 255 {
-		return 255
-	}
-
-	return uint8(scaled)
-}
-
-func float32ToPCMInt32(value float32, bitDepth int) int32 {
-	value = clampFloat32(value)
-
-	switch bitDepth {
-	case 16:
-		return clampScaledPCM(value, 32768.0, 32767)
-	case 24:
-		return clampScaledPCM(value, 8388608.0, 8388607)
-	case 32:
-		return clampScaledPCM(value, 2147483648.0, 2147483647)
-	default:
-		return 0
+	err = appendAIFFMarkers(outFile, markers, inst)
+	if err != nil {
+		return err
 	}
-}
-
-func clampScaledPCM(value float32, scale float64, maxVal int64) int32 {
-	sample := min(int64(math.Round(float64(value)*scale)), maxVal)
 
-	minVal := int64(-scale)
-	if sample < minVal {
-		sample = minVal
-	}
+	fmt.Fprintf(out, "Wav file converted to %s\n", outPath)
 
-	return int32(sample)
+	return nil
 }
 
-func clampFloat32(value float32) float32 {
-	const (
-		minVal = -1.0
-		maxVal = 1.0
-	)
-
-	if value < minVal {
-		return minVal
-	}
-
-	if value > maxVal {
-		return maxVal
+// aiffIntBuffer converts buf from WAV to AIFF integer samples in place. AIFF
+// stores 8-bit samples signed, WAV unsigned.
+func aiffIntBuffer(buf *audio.IntBuffer) *audio.IntBuffer {
+	if buf.SourceBitDepth == 8 {
+		for i, v := range buf.Data {
+			buf.Data[i] = v - 128
+		}
 	}
 
-	return value
+	return buf
 }
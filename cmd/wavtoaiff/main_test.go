@@ -2,6 +2,7 @@ package main
 
 import (
 	"bytes"
+	"encoding/binary"
 	"errors"
 	"os"
 	"os/user"
@@ -9,131 +10,96 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/cwbudde/wav"
+	"github.com/go-audio/aiff"
 	"github.com/go-audio/audio"
 )
 
 var errNoUser = errors.New("no user")
 
-func TestClampFloat32(t *testing.T) {
-	tests := []struct {
-		name  string
-		value float32
-		want  float32
-	}{
-		{name: "below", value: -2, want: -1},
-		{name: "inside", value: 0.25, want: 0.25},
-		{name: "above", value: 2, want: 1},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got := clampFloat32(tt.value)
-			if got != tt.want {
-				t.Fatalf("clampFloat32(%f)=%f, want %f", tt.value, got, tt.want)
-			}
-		})
-	}
-}
-
-func TestFloat32ToPCMInt(t *testing.T) {
+func TestAIFFIntBuffer(t *testing.T) {
 	tests := []struct {
 		name     string
 		value    float32
 		bitDepth int
 		want     int
 	}{
-		{name: "8bit min", value: -1, bitDepth: 8, want: 0},
-		{name: "8bit max", value: 1, bitDepth: 8, want: 255},
+		{name: "8bit signed min", value: -1, bitDepth: 8, want: -128},
+		{name: "8bit signed max", value: 1, bitDepth: 8, want: 127},
 		{name: "16bit half", value: 0.5, bitDepth: 16, want: 16384},
+		{name: "16bit clamped low", value: -2, bitDepth: 16, want: -32768},
+		{name: "16bit clamped high", value: 2, bitDepth: 16, want: 32767},
 		{name: "24bit half", value: 0.5, bitDepth: 24, want: 4194304},
 		{name: "32bit quarter", value: 0.25, bitDepth: 32, want: 536870912},
-		{name: "unsupported", value: 0.5, bitDepth: 12, want: 0},
+		{name: "32bit max", value: 1, bitDepth: 32, want: 2147483647},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := float32ToPCMInt(tt.value, tt.bitDepth)
+			buf := wav.Float32BufferToIntBuffer(&audio.Float32Buffer{Data: []float32{tt.value}}, tt.bitDepth)
+
+			got := aiffIntBuffer(buf).Data[0]
 			if got != tt.want {
-				t.Fatalf("float32ToPCMInt(%f,%d)=%d, want %d", tt.value, tt.bitDepth, got, tt.want)
+				t.Fatalf("sample %f at %d bits=%d, want %d", tt.value, tt.bitDepth, got, tt.want)
 			}
 		})
 	}
 }
 
-func TestFloat32ToIntBuffer(t *testing.T) {
-	format := &audio.Format{NumChannels: 1, SampleRate: 48000}
-	in := []float32{-1.5, 0, 0.5, 1.5}
+func TestRunConvertsInt32Losslessly(t *testing.T) {
+	dir := t.TempDir()
+	inPath := filepath.Join(dir, "int32.wav")
 
-	got := float32ToIntBuffer(in, format, 16)
-	if got.SourceBitDepth != 16 {
-		t.Fatalf("unexpected bit depth %d", got.SourceBitDepth)
-	}
+	// neighbouring 32-bit values that a float32 can't tell apart
+	samples := []int{0x7FFFFF01, 0x7FFFFF02, -0x7FFFFF03, 1}
 
-	if got.Format != format {
-		t.Fatalf("expected returned format pointer to match input")
+	file, err := os.Create(inPath)
+	if err != nil {
+		t.Fatal(err)
 	}
 
-	want := []int{-32768, 0, 16384, 32767}
-	if len(got.Data) != len(want) {
-		t.Fatalf("unexpected data length %d", len(got.Data))
+	enc := wav.NewEncoder(file, 48000, 32, 1, 1)
+
+	err = enc.WriteIntBufferConverting(&audio.IntBuffer{
+		Format:         &audio.Format{NumChannels: 1, SampleRate: 48000},
+		Data:           samples,
+		SourceBitDepth: 32,
+	})
+	if err != nil {
+		t.Fatal(err)
 	}
 
-	for i := range want {
-		if got.Data[i] != want[i] {
-			t.Fatalf("sample[%d]=%d, want %d", i, got.Data[i], want[i])
-		}
+	err = enc.Close()
+	if err != nil {
+		t.Fatal(err)
 	}
-}
 
-func TestFloat32ToPCMUint8(t *testing.T) {
-	tests := []struct {
-		name  string
-		value float32
-		want  uint8
-	}{
-		{name: "clamped low", value: -2, want: 0},
-		{name: "zero maps to center", value: 0, want: 128},
-		{name: "clamped high", value: 2, want: 255},
+	file.Close()
+
+	err = run([]string{"-path", inPath}, user.Current, &bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("run convert failed: %v", err)
 	}
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			if got := float32ToPCMUint8(tt.value); got != tt.want {
-				t.Fatalf("float32ToPCMUint8(%f)=%d, want %d", tt.value, got, tt.want)
-			}
-		})
+	aif, err := os.Open(filepath.Join(dir, "int32.aif"))
+	if err != nil {
+		t.Fatal(err)
 	}
-}
+	defer aif.Close()
 
-func TestFloat32ToPCMInt32(t *testing.T) {
-	tests := []struct {
-		name     string
-		value    float32
-		bitDepth int
-		want     int32
-	}{
-		{name: "16-bit min", value: -1, bitDepth: 16, want: -32768},
-		{name: "16-bit max", value: 1, bitDepth: 16, want: 32767},
-		{name: "24-bit min", value: -1, bitDepth: 24, want: -8388608},
-		{name: "24-bit max", value: 1, bitDepth: 24, want: 8388607},
-		{name: "32-bit min", value: -1, bitDepth: 32, want: -2147483648},
-		{name: "32-bit max", value: 1, bitDepth: 32, want: 2147483647},
-		{name: "unsupported", value: 0.3, bitDepth: 12, want: 0},
+	got, err := aiff.NewDecoder(aif).FullPCMBuffer()
+	if err != nil {
+		t.Fatal(err)
 	}
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			if got := float32ToPCMInt32(tt.value, tt.bitDepth); got != tt.want {
-				t.Fatalf("float32ToPCMInt32(%f,%d)=%d, want %d", tt.value, tt.bitDepth, got, tt.want)
-			}
-		})
+	if len(got.Data) != len(samples) {
+		t.Fatalf("expected %d samples, got %d", len(samples), len(got.Data))
 	}
-}
 
-func TestClampScaledPCMClampsMin(t *testing.T) {
-	got := clampScaledPCM(-2, 32768.0, 32767)
-	if got != -32768 {
-		t.Fatalf("clampScaledPCM min clamp=%d, want -32768", got)
+	for i, want := range samples {
+		if got.Data[i] != want {
+			t.Fatalf("sample[%d]=%d, want %d", i, got.Data[i], want)
+		}
 	}
 }
 
@@ -249,3 +215,115 @@ func TestRunFlagParseError(t *testing.T) {
 		t.Fatal("expected error for unknown flag")
 	}
 }
+
+func TestRunPreservesCuePointsAndLoops(t *testing.T) {
+	dir := t.TempDir()
+	inPath := filepath.Join(dir, "markers.wav")
+
+	cuePayload := make([]byte, 4+2*24)
+	binary.LittleEndian.PutUint32(cuePayload[0:4], 2)
+
+	for i, pos := range []uint32{3, 7} {
+		entry := cuePayload[4+i*24:]
+		binary.LittleEndian.PutUint32(entry[0:4], uint32(i+1))
+		binary.LittleEndian.PutUint32(entry[4:8], pos)
+		copy(entry[8:12], "data")
+		binary.LittleEndian.PutUint32(entry[20:24], pos)
+	}
+
+	smplPayload := make([]byte, 36+24)
+	binary.LittleEndian.PutUint32(smplPayload[12:16], 60)
+	binary.LittleEndian.PutUint32(smplPayload[28:32], 1)
+	binary.LittleEndian.PutUint32(smplPayload[36+8:36+12], 2)
+	binary.LittleEndian.PutUint32(smplPayload[36+12:36+16], 9)
+
+	enc := wav.NewMemoryEncoder(44100, 16, 1, 1)
+	enc.UnknownChunks = []wav.RawChunk{
+		{ID: wav.CIDCue, Size: uint32(len(cuePayload)), Data: cuePayload},
+		{ID: wav.CIDSmpl, Size: uint32(len(smplPayload)), Data: smplPayload},
+	}
+
+	err := enc.Write(&audio.Float32Buffer{
+		Format: &audio.Format{NumChannels: 1, SampleRate: 44100},
+		Data:   make([]float32, 16),
+	})
+	if err != nil {
+		t.Fatalf("encode wav: %v", err)
+	}
+
+	data, err := enc.Close()
+	if err != nil {
+		t.Fatalf("close wav encoder: %v", err)
+	}
+
+	err = os.WriteFile(inPath, data, 0o644)
+	if err != nil {
+		t.Fatalf("write temp wav: %v", err)
+	}
+
+	err = run([]string{"-path", inPath}, user.Current, &bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("run convert failed: %v", err)
+	}
+
+	out, err := os.ReadFile(filepath.Join(dir, "markers.aif"))
+	if err != nil {
+		t.Fatalf("read output: %v", err)
+	}
+
+	if got := binary.BigEndian.Uint32(out[4:8]); int(got) != len(out)-8 {
+		t.Fatalf("FORM size=%d, want %d", got, len(out)-8)
+	}
+
+	chunks := map[string][]byte{}
+
+	for pos := 12; pos+8 <= len(out); {
+		size := int(binary.BigEndian.Uint32(out[pos+4 : pos+8]))
+		chunks[string(out[pos:pos+4])] = out[pos+8 : pos+8+size]
+		pos += 8 + size + size%2
+	}
+
+	mark, ok := chunks["MARK"]
+	if !ok {
+		t.Fatal("missing MARK chunk")
+	}
+
+	wantMarkers := []aiffMarker{
+		{id: 1, position: 3, name: "cue 1"},
+		{id: 2, position: 7, name: "cue 2"},
+		{id: 3, position: 2, name: "loop 1 start"},
+		{id: 4, position: 10, name: "loop 1 end"},
+	}
+	if !bytes.Equal(mark, encodeMarkChunk(wantMarkers)) {
+		t.Fatalf("unexpected MARK payload % x", mark)
+	}
+
+	inst, ok := chunks["INST"]
+	if !ok || len(inst) != 20 {
+		t.Fatalf("missing or malformed INST chunk: % x", inst)
+	}
+
+	if inst[0] != 60 {
+		t.Fatalf("base note=%d, want 60", inst[0])
+	}
+
+	sustain := inst[8:14]
+	if !bytes.Equal(sustain, []byte{0, 1, 0, 3, 0, 4}) {
+		t.Fatalf("unexpected sustain loop % x", sustain)
+	}
+
+	file, err := os.Open(filepath.Join(dir, "markers.aif"))
+	if err != nil {
+		t.Fatalf("open output: %v", err)
+	}
+	defer file.Close()
+
+	pcm, err := aiff.NewDecoder(file).FullPCMBuffer()
+	if err != nil {
+		t.Fatalf("decode aiff: %v", err)
+	}
+
+	if pcm.NumFrames() != 16 {
+		t.Fatalf("frames=%d, want 16", pcm.NumFrames())
+	}
+}
@@ -0,0 +1,238 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/cwbudde/wav"
+)
+
+const (
+	aiffPlayModeNoLooping       = 0
+	aiffPlayModeForwardLooping  = 1
+	aiffPlayModeForwardBackward = 2
+	wavLoopTypeAlternating      = 1
+	maxAIFFLoops                = 2
+	maxMIDINote                 = 127
+)
+
+// aiffMarker is a single entry of the AIFF MARK chunk.
+type aiffMarker struct {
+	id       int16
+	position uint32
+	name     string
+}
+
+// aiffLoop is a sustain or release loop of the AIFF INST chunk. The loop
+// bounds reference marker ids.
+type aiffLoop struct {
+	playMode  int16
+	beginLoop int16
+	endLoop   int16
+}
+
+// aiffInstrument mirrors the fixed size AIFF INST chunk.
+type aiffInstrument struct {
+	baseNote     int8
+	detune       int8
+	lowNote      int8
+	highNote     int8
+	lowVelocity  int8
+	highVelocity int8
+	gain         int16
+	sustainLoop  aiffLoop
+	releaseLoop  aiffLoop
+}
+
+// aiffMarkersFromMetadata maps the wav cue points and sampler loops to AIFF
+// markers and an instrument chunk. The instrument is nil when the source has
+// no sampler info. AIFF only knows a sustain and a release loop, additional
+// loops are dropped.
+func aiffMarkersFromMetadata(meta *wav.Metadata) ([]aiffMarker, *aiffInstrument) {
+	if meta == nil {
+		return nil, nil
+	}
+
+	var markers []aiffMarker
+
+	addMarker := func(position uint32, name string) int16 {
+		if len(markers) >= math.MaxInt16 {
+			return 0
+		}
+
+		id := int16(len(markers) + 1)
+		markers = append(markers, aiffMarker{id: id, position: position, name: name})
+
+		return id
+	}
+
+	for _, cue := range meta.CuePoints {
+		if cue == nil {
+			continue
+		}
+
+		addMarker(cue.Position, fmt.Sprintf("cue %d", binary.LittleEndian.Uint32(cue.ID[:])))
+	}
+
+	if meta.SamplerInfo == nil {
+		return markers, nil
+	}
+
+	inst := &aiffInstrument{
+		baseNote:     int8(min(meta.SamplerInfo.MIDIUnityNote, maxMIDINote)),
+		lowNote:      0,
+		highNote:     maxMIDINote,
+		lowVelocity:  1,
+		highVelocity: maxMIDINote,
+	}
+
+	loops := []*aiffLoop{&inst.sustainLoop, &inst.releaseLoop}
+
+	var numLoops int
+
+	for _, loop := range meta.SamplerInfo.Loops {
+		if loop == nil || numLoops == maxAIFFLoops {
+			continue
+		}
+
+		// smpl loop ends are inclusive, AIFF loops end before the end marker.
+		begin := addMarker(loop.Start, fmt.Sprintf("loop %d start", numLoops+1))
+		end := addMarker(loop.End+1, fmt.Sprintf("loop %d end", numLoops+1))
+
+		playMode := int16(aiffPlayModeForwardLooping)
+		if loop.Type == wavLoopTypeAlternating {
+			playMode = aiffPlayModeForwardBackward
+		}
+
+		*loops[numLoops] = aiffLoop{playMode: playMode, beginLoop: begin, endLoop: end}
+		numLoops++
+	}
+
+	for _, loop := range loops[numLoops:] {
+		loop.playMode = aiffPlayModeNoLooping
+	}
+
+	return markers, inst
+}
+
+// encodeMarkChunk serializes the MARK chunk payload.
+func encodeMarkChunk(markers []aiffMarker) []byte {
+	var buf bytes.Buffer
+
+	_ = binary.Write(&buf, binary.BigEndian, uint16(len(markers)))
+
+	for _, marker := range markers {
+		_ = binary.Write(&buf, binary.BigEndian, marker.id)
+		_ = binary.Write(&buf, binary.BigEndian, marker.position)
+
+		// pstring: count byte followed by the text, padded to an even length.
+		name := marker.name
+		if len(name) > math.MaxUint8 {
+			name = name[:math.MaxUint8]
+		}
+
+		buf.WriteByte(byte(len(name)))
+		buf.WriteString(name)
+
+		if (len(name)+1)%2 != 0 {
+			buf.WriteByte(0)
+		}
+	}
+
+	return buf.Bytes()
+}
+
+// encodeInstChunk serializes the INST chunk payload.
+func encodeInstChunk(inst *aiffInstrument) []byte {
+	var buf bytes.Buffer
+
+	_ = binary.Write(&buf, binary.BigEndian, []int8{
+		inst.baseNote, inst.detune, inst.lowNote, inst.highNote, inst.lowVelocity, inst.highVelocity,
+	})
+	_ = binary.Write(&buf, binary.BigEndian, inst.gain)
+	_ = binary.Write(&buf, binary.BigEndian, inst.sustainLoop)
+	_ = binary.Write(&buf, binary.BigEndian, inst.releaseLoop)
+
+	return buf.Bytes()
+}
+
+// appendAIFFMarkers appends MARK and INST chunks to a closed AIFF stream and
+// patches the FORM size. The go-audio/aiff encoder has no support for these
+// chunks, so they are written after the sound data.
+func appendAIFFMarkers(w io.WriteSeeker, markers []aiffMarker, inst *aiffInstrument) error {
+	if len(markers) == 0 && inst == nil {
+		return nil
+	}
+
+	end, err := w.Seek(0, io.SeekEnd)
+	if err != nil {
+		return fmt.Errorf("failed to seek to the end of the AIFF file: %w", err)
+	}
+
+	// the encoder does not pad an odd sized SSND chunk.
+	if end%2 != 0 {
+		_, err = w.Write([]byte{0})
+		if err != nil {
+			return fmt.Errorf("failed to pad the sound data chunk: %w", err)
+		}
+
+		end++
+	}
+
+	writeChunk := func(id string, payload []byte) error {
+		err := binary.Write(w, binary.BigEndian, []byte(id))
+		if err == nil {
+			err = binary.Write(w, binary.BigEndian, uint32(len(payload)))
+		}
+
+		if err == nil {
+			_, err = w.Write(payload)
+		}
+
+		if err == nil && len(payload)%2 != 0 {
+			_, err = w.Write([]byte{0})
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to write %s chunk: %w", id, err)
+		}
+
+		end += int64(8 + len(payload) + len(payload)%2)
+
+		return nil
+	}
+
+	if len(markers) > 0 {
+		err = writeChunk("MARK", encodeMarkChunk(markers))
+		if err != nil {
+			return err
+		}
+	}
+
+	if inst != nil {
+		err = writeChunk("INST", encodeInstChunk(inst))
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = w.Seek(4, io.SeekStart)
+	if err != nil {
+		return fmt.Errorf("failed to seek to the FORM size: %w", err)
+	}
+
+	err = binary.Write(w, binary.BigEndian, uint32(end-8))
+	if err != nil {
+		return fmt.Errorf("failed to update the FORM size: %w", err)
+	}
+
+	_, err = w.Seek(0, io.SeekEnd)
+	if err != nil {
+		return fmt.Errorf("failed to seek to the end of the AIFF file: %w", err)
+	}
+
+	return nil
+}
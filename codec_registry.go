@@ -0,0 +1,236 @@
+package wav
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/go-audio/audio"
+)
+
+// SampleDecoder decodes the data chunk payload of a format tag to float
+// samples. Implementations read the stream layout from the decoder, e.g.
+// NumChans, BitDepth, FmtChunk and IsBigEndian.
+type SampleDecoder interface {
+	// DecodeSamples decodes up to len(buf.Data) samples from r, the
+	// remainder of the data chunk, into buf.Data and returns the number of
+	// samples decoded. It returns 0 once the data is exhausted. buf.Format
+	// and buf.SourceBitDepth are preset from the fmt chunk and may be
+	// adjusted, e.g. by codecs without a fixed bit depth.
+	DecodeSamples(d *Decoder, r io.Reader, buf *audio.Float32Buffer) (int, error)
+}
+
+// fullSampleDecoder is implemented by built-in codecs that decode a whole
+// data chunk more efficiently than repeated DecodeSamples calls.
+type fullSampleDecoder interface {
+	decodeAll(d *Decoder, format *audio.Format) (*audio.Float32Buffer, error)
+}
+
+// CodecRegistry resolves format tags to sample decoders.
+type CodecRegistry struct {
+	decoders map[uint16]SampleDecoder
+}
+
+func newDefaultCodecRegistry() *CodecRegistry {
+	pcm := &pcmSampleDecoder{}
+
+	return &CodecRegistry{
+		decoders: map[uint16]SampleDecoder{
+			wavFormatPCM:       pcm,
+			wavFormatIEEEFloat: pcm,
+			wavFormatALaw:      pcm,
+			wavFormatMuLaw:     pcm,
+			wavFormatGSM610:    &gsmSampleDecoder{},
+		},
+	}
+}
+
+// Register sets the decoder for a format tag, replacing any previous one.
+func (r *CodecRegistry) Register(tag uint16, dec SampleDecoder) {
+	if r == nil || dec == nil {
+		return
+	}
+
+	r.decoders[tag] = dec
+}
+
+// Lookup returns the decoder registered for a format tag.
+func (r *CodecRegistry) Lookup(tag uint16) (SampleDecoder, bool) {
+	if r == nil {
+		return nil, false
+	}
+
+	dec, ok := r.decoders[tag]
+
+	return dec, ok
+}
+
+// RegisterCodec registers a sample decoder for a format tag, so files using
+// a custom or proprietary codec can be decoded. Extensible files are looked
+// up by their SubFormat tag. Registering a built-in tag replaces the
+// built-in decoder for this decoder only.
+func (d *Decoder) RegisterCodec(tag uint16, dec SampleDecoder) {
+	if d == nil {
+		return
+	}
+
+	if d.codecs == nil {
+		d.codecs = newDefaultCodecRegistry()
+	}
+
+	d.codecs.Register(tag, dec)
+}
+
+// sampleDecoder returns the sample decoder for the effective format tag.
+func (d *Decoder) sampleDecoder() (SampleDecoder, error) {
+	if d.codecs == nil {
+		d.codecs = newDefaultCodecRegistry()
+	}
+
+	dec, ok := d.codecs.Lookup(d.WavAudioFormat)
+	if ok {
+		return dec, nil
+	}
+
+	if isUnsupportedCompressedFormat(d.WavAudioFormat) {
+		return nil, unsupportedCompressedFormatError(d.WavAudioFormat)
+	}
+
+	return nil, fmt.Errorf("%w: %d", errUnsupportedWavFormat, d.WavAudioFormat)
+}
+
+// decodeBufferWith decodes the remaining data chunk with repeated
+// DecodeSamples calls.
+func (d *Decoder) decodeBufferWith(codec SampleDecoder, format *audio.Format) (*audio.Float32Buffer, error) {
+	buf := &audio.Float32Buffer{
+		Format:         format,
+		SourceBitDepth: int(d.BitDepth),
+	}
+
+	block := &audio.Float32Buffer{
+		Data:           make([]float32, max(d.readBufferSize()/bytesPerSample(d.containerBitDepth()), 1)),
+		Format:         format,
+		SourceBitDepth: int(d.BitDepth),
+	}
+
+	for {
+		n, err := codec.DecodeSamples(d, d.PCMChunk.R, block)
+		if n > 0 {
+			buf.Data = append(buf.Data, block.Data[:n]...)
+		}
+
+		if limitErr := d.decodeLimitErr(len(buf.Data)); limitErr != nil {
+			buf.Data = buf.Data[:d.maxDecodeSamples()]
+			d.finishDecoded(buf.Data)
+
+			return buf, limitErr
+		}
+
+		if err != nil && !errors.Is(err, io.EOF) {
+			return buf, err
+		}
+
+		if n <= 0 || err != nil {
+			break
+		}
+	}
+
+	buf.SourceBitDepth = block.SourceBitDepth
+	d.finishDecoded(buf.Data)
+
+	return buf, nil
+}
+
+// pcmSampleDecoder decodes integer PCM, IEEE float and G.711 samples.
+type pcmSampleDecoder struct{}
+
+func (c *pcmSampleDecoder) DecodeSamples(d *Decoder, r io.Reader, buf *audio.Float32Buffer) (n int, err error) {
+	decodeF, err := sampleDecodeFloat32Func(d.containerBitDepth(), d.validBitsPerSample(), d.WavAudioFormat, d.byteOrder(), d.clampDecodedFloat())
+	if err != nil {
+		return 0, fmt.Errorf("could not get sample decode func %w", err)
+	}
+
+	bPerSample := bytesPerSample(d.containerBitDepth())
+	// populate a file buffer to avoid multiple very small reads
+	// we need to cap the buffer size to not be bigger than the pcm chunk.
+	size := len(buf.Data) * bPerSample
+	tmpBuf := make([]byte, size)
+
+	var tmp int
+
+	tmp, err = r.Read(tmpBuf)
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return tmp, nil
+		}
+
+		return tmp, fmt.Errorf("failed to read PCM data: %w", err)
+	}
+
+	if tmp == 0 {
+		return tmp, nil
+	}
+
+	bufR := bytes.NewReader(tmpBuf[:tmp])
+	sampleBuf := make([]byte, bPerSample)
+
+	var misaligned bool
+	if tmp%bPerSample > 0 {
+		misaligned = true
+	}
+
+	// Note that we populate the buffer even if the
+	// size of the buffer doesn't fit an even number of frames.
+	for n = 0; n < len(buf.Data); n++ {
+		buf.Data[n], err = decodeF(bufR, sampleBuf)
+		if err != nil {
+			// the last sample isn't a full sample but just padding.
+			if misaligned {
+				n--
+			}
+
+			break
+		}
+	}
+
+	if errors.Is(err, io.EOF) {
+		err = nil
+	}
+
+	return n, err
+}
+
+func (c *pcmSampleDecoder) decodeAll(d *Decoder, format *audio.Format) (*audio.Float32Buffer, error) {
+	return d.decodePCMBuffer(format)
+}
+
+// gsmSampleDecoder decodes GSM 6.10 in the WAV49 layout. The decoder state
+// carries over between calls and is reset with the decoder.
+type gsmSampleDecoder struct{}
+
+func (c *gsmSampleDecoder) DecodeSamples(d *Decoder, r io.Reader, buf *audio.Float32Buffer) (int, error) {
+	if d.gsmDec == nil {
+		d.gsmDec = newGSMDecoder(int(d.CompressedSamples))
+	}
+
+	buf.SourceBitDepth = 16
+
+	n, err := d.gsmDec.decodeToBuffer(r, buf.Data)
+	d.gsmDecoded += n
+
+	if err != nil {
+		return n, err
+	}
+
+	if n < len(buf.Data) && !d.gsmDec.checkedFactSamples {
+		d.gsmDec.checkedFactSamples = true
+		d.addWarning(checkGSMFactSamples(d.gsmDecoded, int(d.CompressedSamples)))
+	}
+
+	return n, nil
+}
+
+func (c *gsmSampleDecoder) decodeAll(d *Decoder, format *audio.Format) (*audio.Float32Buffer, error) {
+	return d.decodeGSMBuffer(format)
+}
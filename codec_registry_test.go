@@ -0,0 +1,146 @@
+package wav
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/go-audio/audio"
+)
+
+const testCodecTag = 0x7A7A
+
+// testNibbleCodec decodes two 4-bit signed samples per byte, high nibble
+// first.
+type testNibbleCodec struct {
+	pending []float32
+}
+
+func (c *testNibbleCodec) DecodeSamples(_ *Decoder, r io.Reader, buf *audio.Float32Buffer) (int, error) {
+	buf.SourceBitDepth = 4
+
+	var n int
+
+	for n < len(buf.Data) {
+		if len(c.pending) == 0 {
+			var b [1]byte
+
+			_, err := io.ReadFull(r, b[:])
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			if err != nil {
+				return n, err
+			}
+
+			c.pending = []float32{float32(int8(b[0])>>4) / 8, float32(int8(b[0]<<4)>>4) / 8}
+		}
+
+		buf.Data[n] = c.pending[0]
+		c.pending = c.pending[1:]
+		n++
+	}
+
+	return n, nil
+}
+
+func makeCustomCodecWav(t *testing.T) []byte {
+	t.Helper()
+
+	var b bytes.Buffer
+	b.WriteString("RIFF")
+	b.Write(make([]byte, 4))
+	b.WriteString("WAVE")
+
+	fmtPayload := make([]byte, 16)
+	binary.LittleEndian.PutUint16(fmtPayload[0:2], testCodecTag)
+	binary.LittleEndian.PutUint16(fmtPayload[2:4], 1)
+	binary.LittleEndian.PutUint32(fmtPayload[4:8], 8000)
+	binary.LittleEndian.PutUint32(fmtPayload[8:12], 4000)
+	binary.LittleEndian.PutUint16(fmtPayload[12:14], 1)
+	binary.LittleEndian.PutUint16(fmtPayload[14:16], 4)
+	writeTestChunk(t, &b, "fmt ", fmtPayload)
+	writeTestChunk(t, &b, "data", []byte{0x4C, 0x0F, 0x80, 0x70})
+
+	data := b.Bytes()
+	binary.LittleEndian.PutUint32(data[4:8], uint32(len(data)-8))
+
+	return data
+}
+
+func TestDecoder_RegisterCodec(t *testing.T) {
+	data := makeCustomCodecWav(t)
+	want := []float32{0.5, -0.5, 0, -0.125, -1, 0, 0.875, 0}
+
+	_, err := NewDecoder(bytes.NewReader(data)).FullPCMBuffer()
+	if !errors.Is(err, errUnsupportedWavFormat) {
+		t.Fatalf("expected an unsupported format error without a codec, got %v", err)
+	}
+
+	dec := NewDecoder(bytes.NewReader(data))
+	dec.RegisterCodec(testCodecTag, &testNibbleCodec{})
+
+	buf, err := dec.FullPCMBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if buf.SourceBitDepth != 4 {
+		t.Fatalf("expected the codec bit depth, got %d", buf.SourceBitDepth)
+	}
+
+	assertFloat32SlicesClose(t, buf.Data, want, 0)
+
+	// streaming decodes the same samples across small buffers
+	dec = NewDecoder(bytes.NewReader(data))
+	dec.RegisterCodec(testCodecTag, &testNibbleCodec{})
+	dec.SetGainDB(-6.020599913279624)
+
+	var got []float32
+
+	block := &audio.Float32Buffer{Data: make([]float32, 4)}
+
+	for {
+		n, err := dec.PCMBuffer(block)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if n == 0 {
+			break
+		}
+
+		got = append(got, block.Data[:n]...)
+	}
+
+	assertFloat32SlicesClose(t, got, []float32{0.25, -0.25, 0, -0.0625, -0.5, 0, 0.4375, 0}, 1e-6)
+}
+
+func TestDecoder_RegisterCodecReplacesBuiltin(t *testing.T) {
+	enc := NewMemoryEncoder(8000, 8, 1, wavFormatPCM)
+
+	err := enc.WriteFrames([][]float32{{0.5}, {-0.5}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := enc.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dec := NewDecoder(bytes.NewReader(data))
+	dec.RegisterCodec(wavFormatPCM, &testNibbleCodec{})
+
+	buf, err := dec.FullPCMBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(buf.Data) != 4 {
+		t.Fatalf("expected the registered codec to decode two samples per byte, got %v", buf.Data)
+	}
+}
@@ -0,0 +1,90 @@
+package wav
+
+import (
+	"fmt"
+
+	"github.com/go-audio/audio"
+)
+
+const compareBufferSize = 4096
+
+// SamplesEqual decodes a and b in lockstep and reports whether their samples
+// match within tolerance, along with the index of the first frame that
+// differs, or -1 when all frames match. Files of different lengths differ at
+// the first frame missing from the shorter one, files with different channel
+// counts at frame 0. Only the samples are compared, not the sample rate or
+// metadata. Decoding starts at the current position of each decoder.
+func SamplesEqual(a, b *Decoder, tolerance float32) (bool, int, error) {
+	if a == nil || b == nil {
+		return false, 0, ErrPCMDataNotFound
+	}
+
+	streamA := &sampleStream{dec: a, buf: &audio.Float32Buffer{Data: make([]float32, compareBufferSize)}}
+	streamB := &sampleStream{dec: b, buf: &audio.Float32Buffer{Data: make([]float32, compareBufferSize)}}
+
+	var samples int
+
+	for {
+		err := streamA.fill()
+		if err != nil {
+			return false, 0, fmt.Errorf("failed to decode the first file: %w", err)
+		}
+
+		err = streamB.fill()
+		if err != nil {
+			return false, 0, fmt.Errorf("failed to decode the second file: %w", err)
+		}
+
+		if a.NumChans != b.NumChans {
+			return false, 0, nil
+		}
+
+		channels := max(int(a.NumChans), 1)
+
+		if streamA.done || streamB.done {
+			if streamA.done && streamB.done {
+				return true, -1, nil
+			}
+
+			return false, samples / channels, nil
+		}
+
+		count := min(len(streamA.pending), len(streamB.pending))
+
+		for i := range count {
+			diff := streamA.pending[i] - streamB.pending[i]
+			if diff > tolerance || diff < -tolerance {
+				return false, (samples + i) / channels, nil
+			}
+		}
+
+		samples += count
+		streamA.pending = streamA.pending[count:]
+		streamB.pending = streamB.pending[count:]
+	}
+}
+
+// sampleStream buffers the decoded samples of one side of SamplesEqual.
+type sampleStream struct {
+	dec     *Decoder
+	buf     *audio.Float32Buffer
+	pending []float32
+	done    bool
+}
+
+// fill decodes the next block once all pending samples were consumed.
+func (s *sampleStream) fill() error {
+	if len(s.pending) > 0 || s.done {
+		return nil
+	}
+
+	n, err := s.dec.pcmBuffer(s.buf)
+	if err != nil {
+		return err
+	}
+
+	s.pending = s.buf.Data[:n]
+	s.done = n == 0
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package wav
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/go-audio/audio"
+)
+
+func encodeCompareFixture(t *testing.T, data []float32) []byte {
+	t.Helper()
+
+	enc := NewMemoryEncoder(8000, 16, 2, wavFormatPCM)
+
+	err := enc.Write(&audio.Float32Buffer{
+		Format: &audio.Format{NumChannels: 2, SampleRate: 8000},
+		Data:   data,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := enc.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return out
+}
+
+func TestSamplesEqual(t *testing.T) {
+	open := func(path string) *Decoder {
+		t.Helper()
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return NewDecoder(bytes.NewReader(data))
+	}
+
+	equal, frame, err := SamplesEqual(open("fixtures/M1F1-int16-AFsp.wav"), open("fixtures/M1F1-int16WE-AFsp.wav"), 0)
+	if err != nil || !equal || frame != -1 {
+		t.Fatalf("expected equal samples, got %v, %d, %v", equal, frame, err)
+	}
+
+	equal, frame, err = SamplesEqual(open("fixtures/M1F1-int16-AFsp.wav"), open("fixtures/M1F1-int24-AFsp.wav"), 1.0/32768)
+	if err != nil || !equal || frame != -1 {
+		t.Fatalf("expected 16 and 24 bit files to match within one LSB, got %v, %d, %v", equal, frame, err)
+	}
+
+	samples := make([]float32, 2*5000)
+	for i := range samples {
+		samples[i] = float32(i%200)/200 - 0.5
+	}
+
+	reference := encodeCompareFixture(t, samples)
+
+	changed := append([]float32(nil), samples...)
+	changed[2*4321+1] += 0.1
+
+	equal, frame, err = SamplesEqual(NewDecoder(bytes.NewReader(reference)), NewDecoder(bytes.NewReader(encodeCompareFixture(t, changed))), 0.01)
+	if err != nil || equal || frame != 4321 {
+		t.Fatalf("expected a difference at frame 4321, got %v, %d, %v", equal, frame, err)
+	}
+
+	equal, frame, err = SamplesEqual(NewDecoder(bytes.NewReader(reference)), NewDecoder(bytes.NewReader(encodeCompareFixture(t, samples[:2*4500]))), 0)
+	if err != nil || equal || frame != 4500 {
+		t.Fatalf("expected the shorter file to differ at frame 4500, got %v, %d, %v", equal, frame, err)
+	}
+
+	equal, frame, err = SamplesEqual(NewDecoder(bytes.NewReader(reference)), open("fixtures/kick.wav"), 1)
+	if err != nil || equal || frame != 0 {
+		t.Fatalf("expected a channel count mismatch at frame 0, got %v, %d, %v", equal, frame, err)
+	}
+}
@@ -2,6 +2,7 @@ package wav
 
 import (
 	"bytes"
+	"encoding/binary"
 	"errors"
 	"os"
 	"path/filepath"
@@ -78,6 +79,81 @@ func TestChunkInventory_RoundTripUnknownFixture(t *testing.T) {
 	}
 }
 
+func TestChunkInventory_RoundTripPCMFactChunk(t *testing.T) {
+	var b bytes.Buffer
+	b.WriteString("RIFF")
+	b.Write(make([]byte, 4))
+	b.WriteString("WAVE")
+
+	fmtPayload := make([]byte, 16)
+	binary.LittleEndian.PutUint16(fmtPayload[0:2], wavFormatPCM)
+	binary.LittleEndian.PutUint16(fmtPayload[2:4], 1)
+	binary.LittleEndian.PutUint32(fmtPayload[4:8], 8000)
+	binary.LittleEndian.PutUint32(fmtPayload[8:12], 16000)
+	binary.LittleEndian.PutUint16(fmtPayload[12:14], 2)
+	binary.LittleEndian.PutUint16(fmtPayload[14:16], 16)
+	writeTestChunk(t, &b, "fmt ", fmtPayload)
+	writeTestChunk(t, &b, "fact", []byte{0x02, 0x00, 0x00, 0x00})
+	writeTestChunk(t, &b, "data", []byte{0x01, 0x00, 0x02, 0x00})
+
+	input := b.Bytes()
+	binary.LittleEndian.PutUint32(input[4:8], uint32(len(input)-8))
+
+	dec := NewDecoder(bytes.NewReader(input))
+
+	pcm, err := dec.FullPCMBuffer()
+	if err != nil {
+		t.Fatalf("decode PCM: %v", err)
+	}
+
+	if !dec.HadFactChunk {
+		t.Fatal("expected HadFactChunk to be set")
+	}
+
+	outPath := filepath.Join(t.TempDir(), "fact_roundtrip.wav")
+
+	out, err := os.Create(outPath)
+	if err != nil {
+		t.Fatalf("create output: %v", err)
+	}
+
+	enc := NewEncoderFromDecoder(out, dec)
+
+	err = enc.Write(pcm)
+	if err != nil {
+		t.Fatalf("encode PCM: %v", err)
+	}
+
+	err = enc.Close()
+	if err != nil {
+		t.Fatalf("close encoder: %v", err)
+	}
+
+	err = out.Close()
+	if err != nil {
+		t.Fatalf("close output: %v", err)
+	}
+
+	after, err := parseWavChunksFromFile(outPath)
+	if err != nil {
+		t.Fatalf("parse output chunks: %v", err)
+	}
+
+	before, err := parseWavChunks(input)
+	if err != nil {
+		t.Fatalf("parse input chunks: %v", err)
+	}
+
+	if !reflect.DeepEqual(buildChunkInventory(before), buildChunkInventory(after)) {
+		t.Fatalf("chunk inventory mismatch:\n before=%v\n after=%v", buildChunkInventory(before), buildChunkInventory(after))
+	}
+
+	fact, _ := findChunk(after, "fact")
+	if got := binary.LittleEndian.Uint32(fact.data); got != 2 {
+		t.Fatalf("fact sample count mismatch: got %d want 2", got)
+	}
+}
+
 func TestUnsupportedCompressedFormats_ErrorMessageIncludesCodec(t *testing.T) {
 	testCases := []struct {
 		path string
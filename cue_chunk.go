@@ -17,6 +17,8 @@ var (
 	ErrDataChunkIDNotFound = errors.New("failed to read the data chunk id")
 	errCueNilChunk         = errors.New("can't decode a nil chunk")
 	errCueNilDecoder       = errors.New("nil decoder")
+
+	adtlLabl = [4]byte{'l', 'a', 'b', 'l'}
 )
 
 // CuePoint defines an offset which marks a noteworthy sections of the audio
@@ -58,6 +60,9 @@ type CuePoint struct {
 	// (may or may not be bytes) from the Block Start to the sample that
 	// corresponds to the cue point.
 	SampleOffset uint32
+	// Label is the text of the matching labl entry of the associated data
+	// list (LIST adtl chunk), if any.
+	Label string
 }
 
 // DecodeCueChunk decodes the optional cue chunk and extracts cue points.
@@ -135,6 +140,7 @@ func DecodeCueChunk(d *Decoder, ch *riff.Chunk) error {
 					return fmt.Errorf("failed to read sample offset: %w", err)
 				}
 
+				cuePoint.Label = d.cueLabels[cuePoint.ID]
 				d.Metadata.CuePoints = append(d.Metadata.CuePoints, cuePoint)
 			}
 		}
@@ -142,3 +148,118 @@ func DecodeCueChunk(d *Decoder, ch *riff.Chunk) error {
 
 	return nil
 }
+
+// decodeAdtlList reads the labl entries of an associated data list, the
+// reader is positioned after the adtl list type. Other entries such as note
+// and ltxt are skipped.
+func decodeAdtlList(d *Decoder, r io.Reader) error {
+	var (
+		id   [4]byte
+		size uint32
+	)
+
+	for {
+		err := binary.Read(r, binary.BigEndian, &id)
+		if err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				return nil
+			}
+
+			return fmt.Errorf("failed to read adtl sub chunk ID: %w", err)
+		}
+
+		err = binary.Read(r, binary.LittleEndian, &size)
+		if err != nil {
+			return fmt.Errorf("failed to read adtl sub chunk size: %w", err)
+		}
+
+		payload := make([]byte, int(size)+int(size%2))
+
+		n, err := io.ReadFull(r, payload)
+		if err != nil && (!errors.Is(err, io.ErrUnexpectedEOF) || n < int(size)) {
+			return fmt.Errorf("failed to read adtl sub chunk %q: %w", id, err)
+		}
+
+		if id != adtlLabl || size < 4 {
+			continue
+		}
+
+		var cueID [4]byte
+
+		copy(cueID[:], payload[:4])
+
+		label := d.decodeText(payload[4:size])
+
+		if d.cueLabels == nil {
+			d.cueLabels = map[[4]byte]string{}
+		}
+
+		d.cueLabels[cueID] = label
+
+		if d.Metadata == nil {
+			continue
+		}
+
+		for _, cue := range d.Metadata.CuePoints {
+			if cue != nil && cue.ID == cueID {
+				cue.Label = label
+			}
+		}
+	}
+}
+
+// encodeCueChunk encodes the payload of a cue chunk.
+func encodeCueChunk(points []*CuePoint) []byte {
+	buf := bytes.NewBuffer(nil)
+
+	var cues []*CuePoint
+
+	for _, cue := range points {
+		if cue != nil {
+			cues = append(cues, cue)
+		}
+	}
+
+	binary.Write(buf, binary.LittleEndian, uint32(len(cues)))
+
+	for _, cue := range cues {
+		buf.Write(cue.ID[:])
+		binary.Write(buf, binary.LittleEndian, cue.Position)
+		buf.Write(cue.DataChunkID[:])
+		binary.Write(buf, binary.LittleEndian, cue.ChunkStart)
+		binary.Write(buf, binary.LittleEndian, cue.BlockStart)
+		binary.Write(buf, binary.LittleEndian, cue.SampleOffset)
+	}
+
+	return buf.Bytes()
+}
+
+// encodeAdtlList encodes the payload of a LIST adtl chunk holding a labl
+// entry per labeled cue point. It returns nil when no cue point is labeled.
+func encodeAdtlList(points []*CuePoint) []byte {
+	buf := bytes.NewBuffer(nil)
+
+	for _, cue := range points {
+		if cue == nil || cue.Label == "" {
+			continue
+		}
+
+		size := 4 + len(cue.Label) + 1
+
+		buf.Write(adtlLabl[:])
+		binary.Write(buf, binary.LittleEndian, uint32(size))
+		buf.Write(cue.ID[:])
+		buf.WriteString(cue.Label)
+		buf.WriteByte(0)
+
+		if size%2 == 1 {
+			buf.WriteByte(0)
+		}
+	}
+
+	if buf.Len() == 0 {
+		return nil
+	}
+
+	return append([]byte{'a', 'd', 't', 'l'}, buf.Bytes()...)
+}
@@ -0,0 +1,94 @@
+package wav
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/go-audio/audio"
+)
+
+// DetectCuePoints decodes the data chunk from its start and returns a cue
+// point at every onset, i.e. the first frame in which any channel exceeds
+// thresholdDBFS after at least minGapFrames frames at or below it; values
+// below 1 count as 1. The first onset always gets a cue point. Cue points are
+// numbered from 1 and placed like MetadataBuilder.Cue does, so they can be
+// assigned to Metadata.CuePoints and written by the encoder, for instance to
+// split a long recording into tracks. The decoder is left at the end of the
+// data.
+func (d *Decoder) DetectCuePoints(thresholdDBFS float64, minGapFrames int) ([]*CuePoint, error) {
+	if d == nil {
+		return nil, ErrPCMDataNotFound
+	}
+
+	if d.pcmDataAccessed {
+		err := d.Rewind()
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		err := d.FwdToPCM()
+		if err != nil {
+			return nil, d.err
+		}
+	}
+
+	numChans := max(int(d.NumChans), 1)
+	threshold := math.Pow(10, thresholdDBFS/decibelScale)
+	buf := &audio.Float32Buffer{Data: make([]float32, onsetBufferSize*numChans)}
+
+	gap := int64(max(minGapFrames, 1))
+
+	var (
+		cues          []*CuePoint
+		frame, silent int64
+	)
+
+	for {
+		n, err := d.pcmBuffer(buf)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode samples: %w", err)
+		}
+
+		if n == 0 {
+			return cues, nil
+		}
+
+		for i := 0; i+numChans <= n; i += numChans {
+			loud := false
+
+			for _, val := range buf.Data[i : i+numChans] {
+				if math.Abs(float64(val)) > threshold {
+					loud = true
+
+					break
+				}
+			}
+
+			switch {
+			case !loud:
+				silent++
+			case silent >= gap || len(cues) == 0:
+				cues = append(cues, newDetectedCuePoint(len(cues)+1, frame))
+				silent = 0
+			default:
+				silent = 0
+			}
+
+			frame++
+		}
+	}
+}
+
+// newDetectedCuePoint returns a cue point at frame in the data chunk.
+func newDetectedCuePoint(id int, frame int64) *CuePoint {
+	cue := &CuePoint{
+		Position:     uint32(frame),
+		DataChunkID:  [4]byte{'d', 'a', 't', 'a'},
+		SampleOffset: uint32(frame),
+	}
+
+	binary.LittleEndian.PutUint32(cue.ID[:], uint32(id))
+
+	return cue
+}
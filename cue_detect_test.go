@@ -0,0 +1,97 @@
+package wav
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/go-audio/audio"
+)
+
+func TestDecoder_DetectCuePoints(t *testing.T) {
+	// silence, a burst, a short dip, silence, and a final burst
+	segments := []struct {
+		frames int
+		value  float32
+	}{
+		{1000, 0}, {500, 0.5}, {20, 0}, {300, 0.5}, {6000, 0.001}, {400, -0.5},
+	}
+
+	var samples []float32
+
+	for _, seg := range segments {
+		for range seg.frames {
+			// the right channel stays silent
+			samples = append(samples, seg.value, 0)
+		}
+	}
+
+	enc := NewMemoryEncoder(8000, 16, 2, wavFormatPCM)
+
+	err := enc.Write(&audio.Float32Buffer{
+		Format: &audio.Format{NumChannels: 2, SampleRate: 8000},
+		Data:   samples,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := enc.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dec := NewDecoder(bytes.NewReader(data))
+
+	cues, err := dec.DetectCuePoints(-40, 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []uint32{1000, 7820}
+	if len(cues) != len(want) {
+		t.Fatalf("expected %d cue points, got %d", len(want), len(cues))
+	}
+
+	for i, cue := range cues {
+		if cue.Position != want[i] || cue.SampleOffset != want[i] {
+			t.Fatalf("cue %d: expected frame %d, got %+v", i, want[i], cue)
+		}
+
+		if binary.LittleEndian.Uint32(cue.ID[:]) != uint32(i+1) {
+			t.Fatalf("cue %d: unexpected ID %v", i, cue.ID)
+		}
+	}
+
+	// a shorter gap splits at the dip as well, and detection restarts from
+	// the beginning of the data
+	cues, err = dec.DetectCuePoints(-40, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(cues) != 3 || cues[1].Position != 1520 {
+		t.Fatalf("expected a cue at the dip, got %d cue points", len(cues))
+	}
+
+	// the cue points can be written back
+	enc = NewMemoryEncoder(8000, 16, 2, wavFormatPCM)
+	enc.Metadata = &Metadata{CuePoints: cues}
+
+	err = enc.WriteSilence(8220)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err = enc.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dec = NewDecoder(bytes.NewReader(data))
+	dec.ReadMetadata()
+
+	if dec.Metadata == nil || len(dec.Metadata.CuePoints) != 3 || dec.Metadata.CuePoints[2].Position != 7820 {
+		t.Fatalf("cue points didn't round trip: %+v", dec.Metadata)
+	}
+}
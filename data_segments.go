@@ -0,0 +1,265 @@
+package wav
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/go-audio/riff"
+)
+
+var (
+	// ErrDataChunkFormatMismatch is returned when ConcatenateDataChunks is
+	// set and the data chunks can't be joined into one stream, because a
+	// later fmt chunk describes a different format or a data chunk ends in
+	// the middle of a frame.
+	ErrDataChunkFormatMismatch = errors.New("data chunks don't share a format")
+)
+
+// dataSegment is the payload of one data chunk, or a run of silence of a
+// wave list that isn't stored in the file.
+type dataSegment struct {
+	start int64
+	size  int64
+	// silent segments read as size bytes of fill.
+	silent bool
+	fill   byte
+}
+
+// collectDataSegments scans the chunks following the first data chunk for
+// slnt chunks and, with ConcatenateDataChunks set, further data chunks. If
+// any are found or slnt chunks preceded the data chunk, the PCM chunk reader
+// is replaced by one that reads all of them in order, with every slnt chunk
+// expanded to its silent frames. Without ConcatenateDataChunks the scan stops
+// at the next data chunk. The first data chunk has the given declared size and
+// is padded to chunkSize.
+func (d *Decoder) collectDataSegments(declaredSize, chunkSize int) error {
+	blockAlign := d.frameSize()
+
+	var segments []dataSegment
+
+	if d.leadingSilence > 0 && blockAlign > 0 {
+		segments = append(segments, dataSegment{size: d.leadingSilence * blockAlign, silent: true, fill: d.silenceByte()})
+	}
+
+	segments = append(segments, dataSegment{start: d.pcmStart, size: int64(declaredSize)})
+
+	_, err := d.r.Seek(d.pcmStart+int64(chunkSize), io.SeekStart)
+	if err != nil {
+		if d.ConcatenateDataChunks {
+			return fmt.Errorf("failed to seek past the data chunk: %w", err)
+		}
+
+		// silence is only added for readers that can seek
+		d.SilentFrames = 0
+
+		return nil
+	}
+
+scan:
+	for {
+		id, size, err := d.readChunkHeader()
+		if err != nil {
+			// a truncated trailing chunk header ends the scan
+			break
+		}
+
+		start, err := d.r.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return fmt.Errorf("failed to locate chunk %q: %w", id, err)
+		}
+
+		switch id {
+		case riff.DataFormatID:
+			if !d.ConcatenateDataChunks {
+				break scan
+			}
+
+			segments = append(segments, dataSegment{start: start, size: int64(size)})
+		case riff.FmtID:
+			if d.ConcatenateDataChunks {
+				err = d.checkSegmentFmt(&riff.Chunk{ID: id, Size: int(size), R: io.LimitReader(d.r, int64(size))})
+				if err != nil {
+					return err
+				}
+			}
+		case CIDSlnt:
+			frames, err := d.readSilentFrames(io.LimitReader(d.r, int64(size)))
+			if err != nil {
+				d.addWarning(err)
+			} else if frames > 0 && blockAlign > 0 {
+				segments = append(segments, dataSegment{size: frames * blockAlign, silent: true, fill: d.silenceByte()})
+			}
+		}
+
+		_, err = d.r.Seek(start+int64(size)+int64(size%2), io.SeekStart)
+		if err != nil {
+			return fmt.Errorf("failed to skip chunk %q: %w", id, err)
+		}
+	}
+
+	_, err = d.r.Seek(d.pcmStart, io.SeekStart)
+	if err != nil {
+		return fmt.Errorf("failed to seek back to the data chunk: %w", err)
+	}
+
+	if len(segments) == 1 {
+		return nil
+	}
+
+	// the frames of a data chunk followed by another one must be complete,
+	// silence reads the same at any byte offset
+	lastStored := 0
+
+	for i, seg := range segments {
+		if !seg.silent {
+			lastStored = i
+		}
+	}
+
+	var total, silent int64
+
+	chunkIndex := 0
+
+	for i, seg := range segments {
+		total += seg.size
+
+		if seg.silent {
+			silent += seg.size
+
+			continue
+		}
+
+		if blockAlign > 0 && i < lastStored && seg.size%blockAlign != 0 {
+			return fmt.Errorf("%w: data chunk %d holds %d bytes, not a multiple of the %d byte frame", ErrDataChunkFormatMismatch, chunkIndex, seg.size, blockAlign)
+		}
+
+		chunkIndex++
+	}
+
+	if blockAlign > 0 {
+		d.SilentFrames = silent / blockAlign
+	}
+
+	d.dataSegments = segments
+	d.PCMSize = int(total)
+	d.PCMChunk.Size = int(total)
+	d.PCMChunk.R = newSegmentReader(d.r, segments, 0)
+
+	return nil
+}
+
+// checkSegmentFmt compares a fmt chunk found between data chunks with the
+// one the decoder was set up with.
+func (d *Decoder) checkSegmentFmt(chunk *riff.Chunk) error {
+	fmtChunk, err := decodeWavHeaderChunk(chunk, &riff.Parser{}, d.byteOrder(), d.addWarning)
+	if err != nil {
+		return fmt.Errorf("failed to decode fmt chunk: %w", err)
+	}
+
+	cur := d.FmtChunk
+	if cur == nil {
+		return nil
+	}
+
+	if fmtChunk.EffectiveFormatTag() != cur.EffectiveFormatTag() ||
+		fmtChunk.NumChannels != cur.NumChannels ||
+		fmtChunk.SampleRate != cur.SampleRate ||
+		fmtChunk.BitsPerSample != cur.BitsPerSample ||
+		fmtChunk.BlockAlign != cur.BlockAlign {
+		return fmt.Errorf("%w: %d ch %d Hz %d bit after %d ch %d Hz %d bit", ErrDataChunkFormatMismatch,
+			fmtChunk.NumChannels, fmtChunk.SampleRate, fmtChunk.BitsPerSample,
+			cur.NumChannels, cur.SampleRate, cur.BitsPerSample)
+	}
+
+	return nil
+}
+
+// segmentReader reads a list of data segments as one stream, starting at a
+// logical offset. It seeks the underlying reader whenever a stored segment
+// starts.
+type segmentReader struct {
+	r        io.ReadSeeker
+	segments []dataSegment
+	idx      int
+	// off is the read offset within the current segment.
+	off    int64
+	seeked bool
+}
+
+func newSegmentReader(r io.ReadSeeker, segments []dataSegment, offset int64) *segmentReader {
+	sr := &segmentReader{r: r, segments: segments}
+
+	for sr.idx < len(segments) && offset >= segments[sr.idx].size {
+		offset -= segments[sr.idx].size
+		sr.idx++
+	}
+
+	sr.off = offset
+
+	return sr
+}
+
+// Read fills p across segment boundaries, since callers such as PCMBuffer
+// issue a single Read per block.
+func (sr *segmentReader) Read(p []byte) (int, error) {
+	var total int
+
+	for total < len(p) && sr.idx < len(sr.segments) {
+		seg := sr.segments[sr.idx]
+
+		if sr.off >= seg.size {
+			sr.idx++
+			sr.off = 0
+			sr.seeked = false
+
+			continue
+		}
+
+		dst := p[total:]
+		if rem := seg.size - sr.off; int64(len(dst)) > rem {
+			dst = dst[:rem]
+		}
+
+		if seg.silent {
+			for i := range dst {
+				dst[i] = seg.fill
+			}
+
+			total += len(dst)
+			sr.off += int64(len(dst))
+
+			continue
+		}
+
+		if !sr.seeked {
+			_, err := sr.r.Seek(seg.start+sr.off, io.SeekStart)
+			if err != nil {
+				return total, fmt.Errorf("failed to seek to data chunk: %w", err)
+			}
+
+			sr.seeked = true
+		}
+
+		n, err := io.ReadFull(sr.r, dst)
+		total += n
+		sr.off += int64(n)
+
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			// a truncated file ends the stream like a short data chunk
+			sr.idx = len(sr.segments)
+
+			break
+		}
+
+		if err != nil {
+			return total, fmt.Errorf("failed to read data chunk: %w", err)
+		}
+	}
+
+	if total == 0 && len(p) > 0 {
+		return 0, io.EOF
+	}
+
+	return total, nil
+}
@@ -0,0 +1,132 @@
+package wav
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/go-audio/audio"
+)
+
+func TestDecoder_ConcatenateDataChunks(t *testing.T) {
+	first := []float32{0, 0.25, 0.5, -0.25}
+	want := append(append([]float32(nil), first...), -0.5, 0.125, 0, 32767.0/32768)
+
+	data, err := os.ReadFile("fixtures/two-data-chunks.wav")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf, err := NewDecoder(bytes.NewReader(data)).FullPCMBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertFloat32SlicesClose(t, buf.Data, first, 0)
+
+	dec := NewDecoder(bytes.NewReader(data))
+	dec.ConcatenateDataChunks = true
+
+	buf, err = dec.FullPCMBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertFloat32SlicesClose(t, buf.Data, want, 0)
+
+	if dec.PCMLen() != 16 {
+		t.Fatalf("expected 16 bytes of PCM data, got %d", dec.PCMLen())
+	}
+
+	// stream across the chunk boundary in small blocks
+	err = dec.Rewind()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var streamed []float32
+
+	small := &audio.Float32Buffer{Data: make([]float32, 3)}
+
+	for {
+		n, err := dec.PCMBuffer(small)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if n == 0 {
+			break
+		}
+
+		streamed = append(streamed, small.Data[:n]...)
+	}
+
+	assertFloat32SlicesClose(t, streamed, want, 0)
+
+	err = dec.SeekToFrame(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := dec.PCMBuffer(small)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertFloat32SlicesClose(t, small.Data[:n], want[3:6], 0)
+}
+
+func TestDecoder_ConcatenateDataChunksMismatch(t *testing.T) {
+	fmtPayload := func(channels uint16) []byte {
+		payload := make([]byte, 16)
+		binary.LittleEndian.PutUint16(payload[0:2], wavFormatPCM)
+		binary.LittleEndian.PutUint16(payload[2:4], channels)
+		binary.LittleEndian.PutUint32(payload[4:8], 8000)
+		binary.LittleEndian.PutUint32(payload[8:12], 8000*2*uint32(channels))
+		binary.LittleEndian.PutUint16(payload[12:14], 2*channels)
+		binary.LittleEndian.PutUint16(payload[14:16], 16)
+
+		return payload
+	}
+
+	build := func(chunks func(b *bytes.Buffer)) []byte {
+		var b bytes.Buffer
+		b.WriteString("RIFF")
+		b.Write(make([]byte, 4))
+		b.WriteString("WAVE")
+		chunks(&b)
+
+		data := b.Bytes()
+		binary.LittleEndian.PutUint32(data[4:8], uint32(len(data)-8))
+
+		return data
+	}
+
+	testCases := map[string][]byte{
+		"fmt change": build(func(b *bytes.Buffer) {
+			writeTestChunk(t, b, "fmt ", fmtPayload(1))
+			writeTestChunk(t, b, "data", make([]byte, 4))
+			writeTestChunk(t, b, "fmt ", fmtPayload(2))
+			writeTestChunk(t, b, "data", make([]byte, 8))
+		}),
+		"partial frame": build(func(b *bytes.Buffer) {
+			writeTestChunk(t, b, "fmt ", fmtPayload(1))
+			writeTestChunk(t, b, "data", make([]byte, 3))
+			writeTestChunk(t, b, "data", make([]byte, 4))
+		}),
+	}
+
+	for name, data := range testCases {
+		t.Run(name, func(t *testing.T) {
+			dec := NewDecoder(bytes.NewReader(data))
+			dec.ConcatenateDataChunks = true
+
+			_, err := dec.FullPCMBuffer()
+			if !errors.Is(err, ErrDataChunkFormatMismatch) {
+				t.Fatalf("expected ErrDataChunkFormatMismatch, got %v", err)
+			}
+		})
+	}
+}
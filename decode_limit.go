@@ -0,0 +1,32 @@
+package wav
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrDecodeLimitExceeded is returned by FullPCMBuffer and the methods built
+// on it when the data chunk holds more than Decoder.MaxDecodeFrames frames.
+// The returned buffer, if any, is cut off at the limit.
+var ErrDecodeLimitExceeded = errors.New("decode frame limit exceeded")
+
+// maxDecodeSamples returns the number of samples MaxDecodeFrames allows, or
+// -1 without a limit.
+func (d *Decoder) maxDecodeSamples() int {
+	if d.MaxDecodeFrames <= 0 {
+		return -1
+	}
+
+	return d.MaxDecodeFrames * max(int(d.NumChans), 1)
+}
+
+// decodeLimitErr returns ErrDecodeLimitExceeded if more samples than
+// MaxDecodeFrames allows were decoded.
+func (d *Decoder) decodeLimitErr(samples int) error {
+	limit := d.maxDecodeSamples()
+	if limit >= 0 && samples > limit {
+		return fmt.Errorf("%w: more than %d frames", ErrDecodeLimitExceeded, d.MaxDecodeFrames)
+	}
+
+	return nil
+}
@@ -0,0 +1,53 @@
+package wav
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"testing"
+)
+
+func TestDecoder_MaxDecodeFrames(t *testing.T) {
+	for _, path := range []string{"fixtures/kick.wav", "fixtures/addf8-GSM-GW.wav"} {
+		t.Run(path, func(t *testing.T) {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			full, err := NewDecoder(bytes.NewReader(data)).FullPCMBuffer()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			numChans := full.Format.NumChannels
+			frames := len(full.Data) / numChans
+
+			dec := NewDecoder(bytes.NewReader(data))
+			dec.MaxDecodeFrames = frames / 2
+
+			buf, err := dec.FullPCMBuffer()
+			if !errors.Is(err, ErrDecodeLimitExceeded) {
+				t.Fatalf("expected ErrDecodeLimitExceeded, got %v", err)
+			}
+
+			if buf == nil || len(buf.Data) != frames/2*numChans {
+				t.Fatalf("expected the buffer to be cut off at %d frames", frames/2)
+			}
+
+			assertFloat32SlicesClose(t, buf.Data, full.Data[:len(buf.Data)], 0)
+
+			dec = NewDecoder(bytes.NewReader(data))
+			dec.MaxDecodeFrames = frames
+
+			buf, err = dec.FullPCMBuffer()
+			if err != nil {
+				t.Fatalf("expected the limit to allow all %d frames, got %v", frames, err)
+			}
+
+			if len(buf.Data) != len(full.Data) {
+				t.Fatalf("expected %d samples, got %d", len(full.Data), len(buf.Data))
+			}
+		})
+	}
+}
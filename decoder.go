@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"math"
+	"math/bits"
 	"time"
 
 	"github.com/go-audio/audio"
@@ -28,11 +29,21 @@ var (
 	CIDBext = [4]byte{'b', 'e', 'x', 't'}
 	// CIDCart is the chunk ID for the cart chunk.
 	CIDCart = [4]byte{'c', 'a', 'r', 't'}
+	// CIDCsum is the chunk ID for the non-standard checksum chunk, see
+	// Encoder.WriteChecksum.
+	CIDCsum = [4]byte{'c', 's', 'u', 'm'}
+	// RIFXID is the container ID of big-endian RIFF files.
+	RIFXID = [4]byte{'R', 'I', 'F', 'X'}
 
 	// ErrPCMDataNotFound is returned when PCM data chunk is not found.
 	ErrPCMDataNotFound = errors.New("PCM data not found")
+	// ErrFmtChunkNotFound is returned when no fmt chunk could be located.
+	ErrFmtChunkNotFound = errors.New("fmt chunk not found")
 	// ErrDurationNilPointer is returned when calculating duration on a nil decoder.
 	ErrDurationNilPointer = errors.New("can't calculate the duration of a nil pointer")
+	// ErrUnexpectedFmtChunkSize is reported as a decoder warning when the fmt
+	// chunk size doesn't match the 16, 18 + cbSize or 40 byte layouts.
+	ErrUnexpectedFmtChunkSize = errors.New("unexpected fmt chunk size")
 	// ErrUnsupportedCompressedFormat is returned when a compressed audio format
 	// (e.g. GSM 6.10, TrueSpeech, Voxware) is encountered that has no decoder
 	// implementation. The WAV file structure is valid but the audio codec is not
@@ -63,6 +74,7 @@ type Decoder struct {
 	err             error
 	PCMSize         int
 	pcmDataAccessed bool
+	pcmStart        int64
 	// pcmChunk is available so we can use the LimitReader
 	PCMChunk *riff.Chunk
 	// Metadata for the current file
@@ -72,21 +84,120 @@ type Decoder struct {
 	// CompressedSamples stores the sample count from the fact chunk for
 	// compressed formats (diagnostic/informational only).
 	CompressedSamples uint32
+	// HadFactChunk reports whether a fact chunk was present in the source,
+	// regardless of the audio format.
+	HadFactChunk bool
+	// SilentFrames is the number of silent frames slnt chunks add to the
+	// decoded stream, either next to the data chunk or within a wavl wave
+	// list. They decode as zero samples.
+	SilentFrames int64
+	// OnChunk is optionally called by ReadMetadata and ReadAll for each chunk
+	// they read, with the declared chunk size and its payload. Chunks before
+	// the fmt chunk are reported, the fmt chunk itself isn't: ReadInfo decodes
+	// it beforehand. The payload of the data chunk isn't buffered, data is nil
+	// for it. The callback must not retain data.
+	OnChunk func(id [4]byte, size int, data []byte)
+	// ChunkErrors collects typed chunk decode failures that were skipped in
+	// lenient mode. See SetErrorMode.
+	ChunkErrors []error
+	// ClampFloatOutput limits IEEE float samples to [-1, 1]. It is enabled by
+	// NewDecoder; disable it to keep inter-sample overs of mastered material,
+	// for instance for true-peak analysis. With a gain set by SetGainDB, the
+	// limit applies to the amplified samples of all formats.
+	ClampFloatOutput bool
+	// ConcatenateDataChunks makes FwdToPCM join all data chunks of a
+	// segmented file into one PCM stream; by default decoding stops at the
+	// end of the first one. A fmt chunk between the data chunks must describe
+	// the same format, otherwise ErrDataChunkFormatMismatch is returned.
+	ConcatenateDataChunks bool
+	// MaxDecodeFrames bounds the number of frames FullPCMBuffer decodes, so
+	// a file claiming an enormous duration can't exhaust the memory of a
+	// server decoding untrusted input. Decoding stops with
+	// ErrDecodeLimitExceeded once the limit is passed. Streaming with
+	// PCMBuffer is bounded by the caller's buffer and isn't limited. Zero
+	// means no limit.
+	MaxDecodeFrames int
+	// CodePage is the code page declared by the CSET chunk, 0 if there is
+	// none. INFO, bext, cart and cue label strings are decoded to UTF-8
+	// with it, provided the CSET chunk precedes them.
+	CodePage uint16
+	// SanitizeMetadata converts INFO, bext, cart and cue label strings that
+	// aren't valid UTF-8 from Latin-1 when no supported CSET code page is
+	// declared. By default such strings keep their raw bytes.
+	SanitizeMetadata bool
+	// SampleOrder is the layout of the buffers filled by PCMBuffer and
+	// FullPCMBuffer. With SampleOrderPlanar, the data holds all samples of
+	// channel 0, then of channel 1 and so on, as Encoder.Write takes them
+	// with the same setting; PCMBuffer then only decodes whole frames.
+	// Defaults to SampleOrderInterleaved.
+	SampleOrder SampleOrder
+
+	// gain is the linear gain set by SetGainDB, gainSet reports whether it
+	// applies.
+	gain    float32
+	gainSet bool
+	// readBufSize is the read granularity set by SetReadBufferSize, 0 for
+	// the default.
+	readBufSize int
+	// clipCount is the number of full scale samples decoded, see ClipCount.
+	clipCount int
 
 	gsmDec            *gsmDecoder
+	gsmDecoded        int
+	warnings          []error
 	unknownChunkOrder int
+	bigEndian         bool
+	strictChunks      bool
+	// factSamplesOverride is set by SetFactSampleCount and replaces the
+	// fact chunk sample count.
+	factSamplesOverride *uint32
+	// chunkPadded is set by NextChunk when the last chunk had an odd declared
+	// size and its pad byte was included in the chunk.
+	chunkPadded bool
+	// dataSegments lists the data chunks joined by ConcatenateDataChunks,
+	// it is nil for a single data chunk.
+	dataSegments []dataSegment
+	// leadingSilence counts the frames of the slnt chunks found before the
+	// data chunk.
+	leadingSilence int64
+	// cueLabels holds adtl labels by cue point ID, so labels read before the
+	// cue chunk can still be attached to their cue points.
+	cueLabels map[[4]byte]string
+	// codecs resolves format tags to sample decoders, it is created on
+	// first use.
+	codecs *CodecRegistry
+	// pcmConsumed counts the data chunk bytes read by PCMBuffer and
+	// pcmDone is set once the data is exhausted, see Progress.
+	pcmConsumed int64
+	pcmDone     bool
+	// fmtSlack is the number of bytes found after the declared end of the fmt
+	// chunk before the next chunk header, see fmtChunkSlack.
+	fmtSlack int64
 }
 
 // NewDecoder creates a decoder for the passed wav reader.
 // Note that the reader doesn't get rewinded as the container is processed.
 func NewDecoder(r io.ReadSeeker) *Decoder {
 	return &Decoder{
-		r:      r,
-		parser: riff.New(r),
-		chunks: newDefaultChunkRegistry(),
+		r:                r,
+		parser:           riff.New(r),
+		chunks:           newDefaultChunkRegistry(),
+		ClampFloatOutput: true,
 	}
 }
 
+// Unwrap returns the reader passed to NewDecoder, positioned wherever the
+// last decoder call left it, so callers can take over parsing once the
+// decoder is done, e.g. to read a custom trailer. Mixing decoder calls and
+// direct use of the reader afterwards is undefined.
+func (d *Decoder) Unwrap() io.ReadSeeker {
+	if d == nil {
+		return nil
+	}
+
+	return d.r
+}
+
 // Seek provides access to the cursor position in the PCM data.
 func (d *Decoder) Seek(offset int64, whence int) (int64, error) {
 	pos, err := d.r.Seek(offset, whence)
@@ -104,22 +215,78 @@ func (d *Decoder) Rewind() error {
 	if err != nil {
 		return fmt.Errorf("failed to seek back to the start %w", err)
 	}
+
+	d.resetParser()
+
+	err = d.FwdToPCM()
+	if err != nil {
+		return fmt.Errorf("failed to seek to the PCM data: %w", err)
+	}
+
+	return nil
+}
+
+// PeekFormat reads the fmt chunk and then seeks the reader back to the start
+// of the file, resetting the parser state so that a subsequent decode starts
+// clean. It requires the underlying reader to be seekable from the start.
+func (d *Decoder) PeekFormat() (*FmtChunk, error) {
+	if d == nil || d.r == nil {
+		return nil, errNilDecoder
+	}
+
+	_, err := d.r.Seek(0, io.SeekStart)
+	if err != nil {
+		return nil, fmt.Errorf("failed to seek back to the start %w", err)
+	}
+
+	d.resetParser()
+
+	headerErr := d.readHeaders()
+	fmtChunk := d.FmtChunk.Clone()
+
+	_, err = d.r.Seek(0, io.SeekStart)
+	if err != nil {
+		return nil, fmt.Errorf("failed to seek back to the start %w", err)
+	}
+
+	d.resetParser()
+
+	if headerErr != nil {
+		return nil, headerErr
+	}
+
+	if fmtChunk == nil {
+		return nil, ErrFmtChunkNotFound
+	}
+
+	return fmtChunk, nil
+}
+
+// resetParser discards the parsed header state. The caller is responsible
+// for positioning the reader at the start of the file beforehand.
+func (d *Decoder) resetParser() {
 	// we have to user a new parser since it's read only and can't be seeked
 	d.parser = riff.New(d.r)
 	d.pcmDataAccessed = false
 	d.PCMChunk = nil
+	d.dataSegments = nil
+	d.leadingSilence = 0
+	d.SilentFrames = 0
+	d.fmtSlack = 0
 	d.err = nil
 	d.NumChans = 0
 	d.CompressedSamples = 0
+	if d.factSamplesOverride != nil {
+		d.CompressedSamples = *d.factSamplesOverride
+	}
+	d.HadFactChunk = false
 	d.FmtChunk = nil
 	d.gsmDec = nil
-
-	err = d.FwdToPCM()
-	if err != nil {
-		return fmt.Errorf("failed to seek to the PCM data: %w", err)
-	}
-
-	return nil
+	d.gsmDecoded = 0
+	d.clipCount = 0
+	d.warnings = nil
+	d.ChunkErrors = nil
+	d.bigEndian = false
 }
 
 // SampleBitDepth returns the bit depth encoding of each sample.
@@ -131,6 +298,29 @@ func (d *Decoder) SampleBitDepth() int32 {
 	return int32(d.BitDepth)
 }
 
+// validBitsPerSample returns the number of significant bits per sample. It
+// honors the extensible ValidBitsPerSample field and falls back to BitDepth.
+func (d *Decoder) validBitsPerSample() int {
+	if d.FmtChunk != nil && d.FmtChunk.Extensible != nil {
+		valid := int(d.FmtChunk.Extensible.ValidBitsPerSample)
+		if valid > 0 && valid < int(d.BitDepth) {
+			return valid
+		}
+	}
+
+	return int(d.BitDepth)
+}
+
+// containerBitDepth returns the number of bits each sample occupies in the
+// data chunk, which is 32 for 24-bit samples in 4-byte containers.
+func (d *Decoder) containerBitDepth() int {
+	if d.FmtChunk != nil && d.FmtChunk.BitsPerSample == d.BitDepth {
+		return d.FmtChunk.containerBits()
+	}
+
+	return int(d.BitDepth)
+}
+
 // PCMLen returns the total number of bytes in the PCM data chunk.
 func (d *Decoder) PCMLen() int64 {
 	if d == nil {
@@ -149,6 +339,92 @@ func (d *Decoder) Err() error {
 	return d.err
 }
 
+// Close releases the codec state and returns the first non-EOF error that was
+// encountered while decoding. It doesn't close the underlying reader and is
+// safe to call on a nil decoder.
+func (d *Decoder) Close() error {
+	if d == nil {
+		return nil
+	}
+
+	d.gsmDec = nil
+
+	return d.Err()
+}
+
+// Warnings returns non-fatal issues found while decoding, such as a GSM
+// stream holding fewer samples than its fact chunk announces.
+func (d *Decoder) Warnings() []error {
+	if d == nil || len(d.warnings) == 0 {
+		return nil
+	}
+
+	return append([]error(nil), d.warnings...)
+}
+
+// SetFactSampleCount overrides the sample count of the fact chunk, which
+// compressed decoders use to trim the padding of the last block. Use it for
+// files with a missing or wrong fact chunk; 0 decodes all blocks without
+// truncation. Negative values are treated as 0. The override survives Rewind.
+func (d *Decoder) SetFactSampleCount(n int) {
+	if d == nil {
+		return
+	}
+
+	count := uint32(max(n, 0))
+	d.factSamplesOverride = &count
+	d.CompressedSamples = count
+
+	if d.gsmDec != nil {
+		d.gsmDec.factSamples = int(count)
+	}
+}
+
+// GSMDecodedSamples returns the number of samples the GSM decoder produced
+// so far. Compare it with CompressedSamples to detect truncated recordings.
+func (d *Decoder) GSMDecodedSamples() int {
+	if d == nil {
+		return 0
+	}
+
+	return d.gsmDecoded
+}
+
+// SetErrorMode selects how typed chunk decode failures are handled. In the
+// default lenient mode the failing chunk is skipped, the error is appended to
+// ChunkErrors and parsing continues. In strict mode the error is stored as the
+// decoder error and parsing stops.
+func (d *Decoder) SetErrorMode(strict bool) {
+	if d == nil {
+		return
+	}
+
+	d.strictChunks = strict
+}
+
+// handleChunkError records a chunk decode failure according to the error
+// mode. It returns true if parsing should stop.
+func (d *Decoder) handleChunkError(chunk *riff.Chunk, err error) bool {
+	if d.strictChunks {
+		d.err = err
+
+		return true
+	}
+
+	d.ChunkErrors = append(d.ChunkErrors, fmt.Errorf("%q chunk: %w", string(chunk.ID[:]), err))
+
+	// skip whatever the failing handler left unread
+	_, _ = io.Copy(io.Discard, chunk.R)
+
+	return false
+}
+
+func (d *Decoder) addWarning(err error) {
+	if err != nil {
+		d.warnings = append(d.warnings, err)
+	}
+}
+
 // EOF returns positively if the underlying reader reached the end of file.
 func (d *Decoder) EOF() bool {
 	if d == nil || errors.Is(d.err, io.EOF) {
@@ -158,7 +434,8 @@ func (d *Decoder) EOF() bool {
 	return false
 }
 
-// IsValidFile verifies that the file is valid/readable.
+// IsValidFile verifies that the file is valid/readable. Files with an empty
+// data chunk are valid, see IsEmpty.
 func (d *Decoder) IsValidFile() bool {
 	d.err = d.readHeaders()
 	if d.err != nil {
@@ -174,13 +451,30 @@ func (d *Decoder) IsValidFile() bool {
 	}
 
 	dur, err := d.Duration()
-	if err != nil || dur <= 0 {
+	if err != nil {
+		return false
+	}
+
+	// a sound file without samples is still valid
+	if dur <= 0 && !d.IsEmpty() {
 		return false
 	}
 
 	return true
 }
 
+// IsEmpty reports whether the file is structurally sound but its data chunk
+// holds no samples. Files without a data chunk aren't empty but invalid. Like
+// Stat, it leaves the decoder reset to the start of the file.
+func (d *Decoder) IsEmpty() bool {
+	stat, err := d.Stat()
+	if err != nil {
+		return false
+	}
+
+	return stat.DataOffset >= 0 && stat.DataSize == 0
+}
+
 // ReadInfo reads the underlying reader until the comm header is parsed.
 // This method is safe to call multiple times.
 func (d *Decoder) ReadInfo() {
@@ -191,6 +485,18 @@ func (d *Decoder) ReadInfo() {
 // The entire file will be read and should be rewinded if more data must be
 // accessed.
 func (d *Decoder) ReadMetadata() {
+	d.readMetadata(false)
+}
+
+// ReadMetadataPartial parses the same metadata as ReadMetadata but seeks past
+// the data chunk payload instead of reading it, so tags that follow the
+// samples are found without reading the PCM data. Readers that can't seek
+// fall back to reading the payload. Rewind before accessing the samples.
+func (d *Decoder) ReadMetadataPartial() {
+	d.readMetadata(true)
+}
+
+func (d *Decoder) readMetadata(skipData bool) {
 	if d.Metadata != nil {
 		return
 	}
@@ -218,17 +524,46 @@ func (d *Decoder) ReadMetadata() {
 
 		d.unknownChunkOrder++
 
+		// the fmt chunk was decoded by ReadInfo, it is only met here when it
+		// follows other chunks such as data.
+		if chunk.ID == riff.FmtID {
+			chunk.Drain()
+
+			continue
+		}
+
 		if chunk.ID == riff.DataFormatID {
 			seenData = true
 
-			chunk.Drain()
+			if d.OnChunk != nil {
+				d.OnChunk(chunk.ID, d.declaredChunkSize(chunk), nil)
+			}
+
+			if skipData {
+				d.skipChunk(chunk)
+			} else {
+				chunk.Drain()
+			}
 
 			continue
 		}
 
+		if d.OnChunk != nil {
+			err = d.notifyChunk(chunk)
+			if err != nil {
+				d.err = err
+
+				return
+			}
+		}
+
 		handled, handleErr := d.decodeChunkViaRegistry(chunk)
 		if handleErr != nil && !errors.Is(handleErr, io.EOF) {
-			d.err = handleErr
+			if d.handleChunkError(chunk, handleErr) {
+				return
+			}
+
+			continue
 		}
 
 		if !handled {
@@ -237,6 +572,36 @@ func (d *Decoder) ReadMetadata() {
 	}
 }
 
+// notifyChunk buffers the chunk payload, passes a copy to OnChunk and lets
+// the chunk be decoded from the buffer afterwards.
+func (d *Decoder) notifyChunk(chunk *riff.Chunk) error {
+	payload, err := io.ReadAll(chunk.R)
+	if err != nil {
+		return fmt.Errorf("failed to read chunk %s: %w", chunk.ID, err)
+	}
+
+	chunk.R = bytes.NewReader(payload)
+
+	size := d.declaredChunkSize(chunk)
+	if size > len(payload) {
+		size = len(payload)
+	}
+
+	d.OnChunk(chunk.ID, size, append([]byte(nil), payload[:size]...))
+
+	return nil
+}
+
+// declaredChunkSize returns the chunk size as stored in the file, without the
+// pad byte NextChunk adds to odd sized chunks.
+func (d *Decoder) declaredChunkSize(chunk *riff.Chunk) int {
+	if d.chunkPadded {
+		return chunk.Size - 1
+	}
+
+	return chunk.Size
+}
+
 // FwdToPCM forwards the underlying reader until the start of the PCM chunk.
 // If the PCM chunk was already read, no data will be found (you need to rewind).
 func (d *Decoder) FwdToPCM() error {
@@ -257,23 +622,48 @@ func (d *Decoder) FwdToPCM() error {
 		}
 
 		if chunk.ID == riff.DataFormatID {
-			d.PCMSize = chunk.Size
-			d.PCMChunk = chunk
+			d.err = d.startPCM(chunk)
+			if d.err != nil {
+				return d.err
+			}
 
 			break
 		}
 
-		handled, err := d.decodeChunkViaRegistry(chunk)
+		if chunk.ID == CIDSlnt {
+			err := d.readLeadingSilence(chunk)
+			if err != nil && d.handleChunkError(chunk, err) {
+				return d.err
+			}
+
+			continue
+		}
+
+		waveList, err := d.startWaveList(chunk)
 		if err != nil {
 			d.err = err
+
 			return d.err
 		}
 
+		if waveList {
+			break
+		}
+
+		handled, err := d.decodeChunkViaRegistry(chunk)
+		if err != nil {
+			if d.handleChunkError(chunk, err) {
+				return d.err
+			}
+
+			continue
+		}
+
 		if handled {
 			continue
 		}
 
-		chunk.Drain()
+		d.skipChunk(chunk)
 	}
 
 	if chunk == nil {
@@ -285,6 +675,136 @@ func (d *Decoder) FwdToPCM() error {
 	return nil
 }
 
+// SkipToDataFast forwards to the start of the PCM chunk like FwdToPCM, but
+// seeks past all other chunks without reading or decoding them, so metadata
+// such as bext or cue chunks isn't parsed. Large chunks like levl cost a seek
+// instead of a read. Readers that fail to seek are drained instead.
+func (d *Decoder) SkipToDataFast() error {
+	if d == nil {
+		return ErrPCMDataNotFound
+	}
+
+	d.err = d.readHeaders()
+	if d.err != nil {
+		return d.err
+	}
+
+	for {
+		chunk, err := d.NextChunk()
+		if err != nil {
+			return err
+		}
+
+		if chunk.ID == riff.DataFormatID {
+			d.err = d.startPCM(chunk)
+			if d.err != nil {
+				return d.err
+			}
+
+			d.pcmDataAccessed = true
+
+			return nil
+		}
+
+		if chunk.ID == CIDSlnt {
+			// silence is part of the samples, not metadata
+			err = d.readLeadingSilence(chunk)
+			if err != nil && d.handleChunkError(chunk, err) {
+				return d.err
+			}
+
+			continue
+		}
+
+		waveList, err := d.startWaveList(chunk)
+		if err != nil {
+			d.err = err
+
+			return d.err
+		}
+
+		if waveList {
+			d.pcmDataAccessed = true
+
+			return nil
+		}
+
+		d.skipChunk(chunk)
+	}
+}
+
+// startPCM sets up decoding of the data chunk the reader is positioned at.
+func (d *Decoder) startPCM(chunk *riff.Chunk) error {
+	// the pad byte of an odd sized data chunk isn't a sample
+	d.PCMSize = d.declaredChunkSize(chunk)
+	d.PCMChunk = chunk
+	d.PCMChunk.R = newDataChunkReader(d.r, int64(d.PCMSize), d.PCMSize%2 == 1)
+	d.pcmStart, _ = d.r.Seek(0, io.SeekCurrent)
+	d.dataSegments = nil
+	d.pcmConsumed = 0
+	d.pcmDone = false
+	d.SilentFrames = 0
+
+	return d.collectDataSegments(d.declaredChunkSize(chunk), chunk.Size)
+}
+
+// skipChunk seeks past the unread payload of chunk, falling back to draining
+// it when the reader can't seek.
+func (d *Decoder) skipChunk(chunk *riff.Chunk) {
+	// NextChunk limits the chunk reader to the payload, its remainder is
+	// what is left to skip.
+	if lr, ok := chunk.R.(*io.LimitedReader); ok {
+		_, err := d.r.Seek(lr.N, io.SeekCurrent)
+		if err == nil {
+			lr.N = 0
+			return
+		}
+	}
+
+	chunk.Drain()
+}
+
+// dataChunkReader reads the remaining samples of the data chunk. The pad byte
+// of an odd sized chunk is consumed once the samples are read, so chunks
+// following the data chunk can still be parsed.
+type dataChunkReader struct {
+	r      io.Reader
+	n      int64
+	padded bool
+}
+
+func newDataChunkReader(r io.Reader, n int64, padded bool) *dataChunkReader {
+	return &dataChunkReader{r: r, n: n, padded: padded}
+}
+
+func (r *dataChunkReader) Read(p []byte) (int, error) {
+	if r.n <= 0 {
+		r.skipPad()
+
+		return 0, io.EOF
+	}
+
+	if int64(len(p)) > r.n {
+		p = p[:r.n]
+	}
+
+	n, err := r.r.Read(p)
+	r.n -= int64(n)
+
+	if r.n <= 0 {
+		r.skipPad()
+	}
+
+	return n, err
+}
+
+func (r *dataChunkReader) skipPad() {
+	if r.padded {
+		r.padded = false
+		io.CopyN(io.Discard, r.r, 1)
+	}
+}
+
 // WasPCMAccessed returns positively if the PCM data was previously accessed.
 func (d *Decoder) WasPCMAccessed() bool {
 	if d == nil {
@@ -298,6 +818,17 @@ func (d *Decoder) WasPCMAccessed() bool {
 // audio container. The entire PCM data is held in memory.
 // Consider using PCMBuffer() instead.
 func (d *Decoder) FullPCMBuffer() (*audio.Float32Buffer, error) {
+	buf, err := d.fullPCMBuffer()
+	if buf != nil && d.SampleOrder == SampleOrderPlanar {
+		deinterleave(buf.Data, int(d.NumChans))
+	}
+
+	return buf, err
+}
+
+// fullPCMBuffer is FullPCMBuffer with interleaved samples regardless of
+// SampleOrder.
+func (d *Decoder) fullPCMBuffer() (*audio.Float32Buffer, error) {
 	if !d.WasPCMAccessed() {
 		err := d.FwdToPCM()
 		if err != nil {
@@ -314,19 +845,70 @@ func (d *Decoder) FullPCMBuffer() (*audio.Float32Buffer, error) {
 		SampleRate:  int(d.SampleRate),
 	}
 
-	if d.WavAudioFormat == wavFormatGSM610 {
-		return d.decodeGSMBuffer(format)
+	codec, err := d.sampleDecoder()
+	if err != nil {
+		return nil, err
 	}
 
-	if isUnsupportedCompressedFormat(d.WavAudioFormat) {
-		return nil, unsupportedCompressedFormatError(d.WavAudioFormat)
+	var buf *audio.Float32Buffer
+
+	if full, ok := codec.(fullSampleDecoder); ok {
+		buf, err = full.decodeAll(d, format)
+	} else {
+		buf, err = d.decodeBufferWith(codec, format)
 	}
 
-	return d.decodePCMBuffer(format)
+	d.pcmDone = err == nil
+
+	return buf, err
+}
+
+// Samples decodes the whole data chunk and returns the samples, laid out
+// according to SampleOrder, along with the sample rate and channel count.
+// It is a convenience over FullPCMBuffer for callers that don't need the
+// audio buffer type.
+func (d *Decoder) Samples() ([]float32, int, int, error) {
+	if d == nil {
+		return nil, 0, 0, ErrPCMDataNotFound
+	}
+
+	buf, err := d.FullPCMBuffer()
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	return buf.Data, int(d.SampleRate), int(d.NumChans), nil
 }
 
 // PCMBuffer populates the passed PCM buffer.
 func (d *Decoder) PCMBuffer(buf *audio.Float32Buffer) (n int, err error) {
+	if buf == nil || d == nil || d.SampleOrder != SampleOrderPlanar {
+		return d.pcmBuffer(buf)
+	}
+
+	// the channel count is only known once the fmt chunk has been read
+	if !d.pcmDataAccessed {
+		err := d.FwdToPCM()
+		if err != nil {
+			return 0, d.err
+		}
+	}
+
+	numChans := max(int(d.NumChans), 1)
+	data := buf.Data
+	buf.Data = data[:len(data)/numChans*numChans]
+
+	n, err = d.pcmBuffer(buf)
+	buf.Data = data
+
+	deinterleave(data[:n/numChans*numChans], numChans)
+
+	return n, err
+}
+
+// pcmBuffer is PCMBuffer with interleaved samples regardless of
+// SampleOrder.
+func (d *Decoder) pcmBuffer(buf *audio.Float32Buffer) (n int, err error) {
 	if buf == nil {
 		return 0, nil
 	}
@@ -347,83 +929,77 @@ func (d *Decoder) PCMBuffer(buf *audio.Float32Buffer) (n int, err error) {
 		SampleRate:  int(d.SampleRate),
 	}
 
-	buf.SourceBitDepth = int(d.BitDepth)
-
-	if d.WavAudioFormat == wavFormatGSM610 {
-		if d.gsmDec == nil {
-			d.gsmDec = newGSMDecoder(int(d.CompressedSamples))
-		}
+	codec, err := d.sampleDecoder()
+	if err != nil {
+		return 0, err
+	}
 
-		buf.SourceBitDepth = 16
-		buf.Format = format
+	buf.Format = format
+	buf.SourceBitDepth = int(d.BitDepth)
 
-		n, err := d.gsmDec.decodeToBuffer(d.PCMChunk.R, buf.Data)
-		if err != nil {
-			return n, err
-		}
+	n, err = codec.DecodeSamples(d, &progressReader{d: d, r: d.PCMChunk.R}, buf)
+	d.finishDecoded(buf.Data[:max(n, 0)])
 
-		return n, nil
+	if n <= 0 && err == nil {
+		d.pcmDone = true
 	}
 
-	if isUnsupportedCompressedFormat(d.WavAudioFormat) {
-		return 0, unsupportedCompressedFormatError(d.WavAudioFormat)
-	}
+	return n, err
+}
 
-	decodeF, err := sampleDecodeFloat32Func(int(d.BitDepth), d.WavAudioFormat)
-	if err != nil {
-		return 0, fmt.Errorf("could not get sample decode func %w", err)
+// CopyPCMTo copies the raw bytes of the data chunk to w without decoding.
+// It forwards to the PCM chunk if needed and works for any format, including
+// compressed ones. At most PCMSize bytes are copied from the current position.
+func (d *Decoder) CopyPCMTo(w io.Writer) (int64, error) {
+	if d == nil {
+		return 0, ErrPCMDataNotFound
 	}
 
-	bPerSample := bytesPerSample(int(d.BitDepth))
-	// populate a file buffer to avoid multiple very small reads
-	// we need to cap the buffer size to not be bigger than the pcm chunk.
-	size := len(buf.Data) * bPerSample
-	tmpBuf := make([]byte, size)
-
-	var tmp int
-
-	tmp, err = d.PCMChunk.R.Read(tmpBuf)
-	if err != nil {
-		if errors.Is(err, io.EOF) {
-			return tmp, nil
+	if !d.pcmDataAccessed {
+		err := d.FwdToPCM()
+		if err != nil {
+			return 0, d.err
 		}
+	}
 
-		return tmp, fmt.Errorf("failed to read PCM data: %w", err)
+	if d.PCMChunk == nil {
+		return 0, ErrPCMChunkNotFound
 	}
 
-	if tmp == 0 {
-		return tmp, nil
+	n, err := io.CopyN(w, d.PCMChunk.R, int64(d.PCMSize))
+	if err != nil && !errors.Is(err, io.EOF) {
+		return n, fmt.Errorf("failed to copy PCM data: %w", err)
 	}
 
-	bufR := bytes.NewReader(tmpBuf[:tmp])
-	sampleBuf := make([]byte, bPerSample)
+	return n, nil
+}
 
-	var misaligned bool
-	if tmp%bPerSample > 0 {
-		misaligned = true
+// Read reads raw bytes of the data chunk payload, forwarding to the PCM
+// chunk first if needed. Together with WriteTo it lets the decoder be used
+// as the source of io.Copy to extract the raw data chunk.
+func (d *Decoder) Read(p []byte) (int, error) {
+	if d == nil {
+		return 0, ErrPCMDataNotFound
 	}
 
-	// Note that we populate the buffer even if the
-	// size of the buffer doesn't fit an even number of frames.
-	for n = 0; n < len(buf.Data); n++ {
-		buf.Data[n], err = decodeF(bufR, sampleBuf)
+	if !d.pcmDataAccessed {
+		err := d.FwdToPCM()
 		if err != nil {
-			// the last sample isn't a full sample but just padding.
-			if misaligned {
-				n--
-			}
-
-			break
+			return 0, d.err
 		}
 	}
 
-	buf.Format = format
-
-	if errors.Is(err, io.EOF) {
-		err = nil
+	if d.PCMChunk == nil {
+		return 0, ErrPCMChunkNotFound
 	}
 
-	return n, err
+	return d.PCMChunk.Read(p)
+}
+
+// WriteTo implements io.WriterTo by copying the raw data chunk bytes to w,
+// see CopyPCMTo.
+func (d *Decoder) WriteTo(w io.Writer) (int64, error) {
+	return d.CopyPCMTo(w)
 }
 
 // Format returns the audio format of the decoded content.
@@ -438,7 +1014,8 @@ func (d *Decoder) Format() *audio.Format {
 	}
 }
 
-// NextChunk returns the next available chunk.
+// NextChunk returns the next available chunk. The caller is responsible for
+// draining it, see ForEachChunk for a managed alternative.
 func (d *Decoder) NextChunk() (*riff.Chunk, error) {
 	if d.err = d.readHeaders(); d.err != nil {
 		d.err = fmt.Errorf("failed to read header - %w", d.err)
@@ -450,12 +1027,17 @@ func (d *Decoder) NextChunk() (*riff.Chunk, error) {
 		size uint32
 	)
 
-	id, size, d.err = d.parser.IDnSize()
+	id, size, d.err = d.readChunkHeader()
 	if d.err != nil {
 		d.err = fmt.Errorf("error reading chunk header - %w", d.err)
 		return nil, d.err
 	}
 
+	// include the bytes an encoder wrote past the declared fmt chunk size
+	if id == riff.FmtID {
+		size += uint32(d.fmtSlack)
+	}
+
 	// TODO: any reason we don't use d.parser.NextChunk (riff.NextChunk) here?
 	// It correctly handles the misaligned chunk.
 
@@ -464,7 +1046,8 @@ func (d *Decoder) NextChunk() (*riff.Chunk, error) {
 	// If the data uses an odd number of bytes, a padding byte with a value of zero
 	// must be placed at the end of the sample data.
 	// The "data" chunk header's size should not include this byte.
-	if size%2 == 1 {
+	d.chunkPadded = size%2 == 1
+	if d.chunkPadded {
 		size++
 	}
 
@@ -499,52 +1082,76 @@ func (d *Decoder) String() string {
 func (d *Decoder) decodeGSMBuffer(format *audio.Format) (*audio.Float32Buffer, error) {
 	dec := newGSMDecoder(int(d.CompressedSamples))
 
-	samples, err := dec.decodeAllBlocks(d.PCMChunk.R, int(d.CompressedSamples))
+	samples, err := dec.decodeAllBlocks(d.PCMChunk.R, int(d.CompressedSamples), d.maxDecodeSamples())
 	if err != nil {
 		return nil, err
 	}
 
+	// the fact chunk check only applies to a completely decoded chunk
+	limitErr := d.decodeLimitErr(len(samples))
+	if limitErr != nil {
+		samples = samples[:d.maxDecodeSamples()]
+	} else {
+		d.addWarning(checkGSMFactSamples(len(samples), int(d.CompressedSamples)))
+	}
+
+	d.gsmDecoded = len(samples)
+	d.finishDecoded(samples)
+
 	return &audio.Float32Buffer{
 		Data:           samples,
 		Format:         format,
 		SourceBitDepth: 16,
-	}, nil
+	}, limitErr
 }
 
 func (d *Decoder) decodePCMBuffer(format *audio.Format) (*audio.Float32Buffer, error) {
 	buf := &audio.Float32Buffer{
-		Data:           make([]float32, 4096),
 		Format:         format,
 		SourceBitDepth: int(d.BitDepth),
 	}
 
-	bPerSample := bytesPerSample(int(d.BitDepth))
+	bPerSample := bytesPerSample(d.containerBitDepth())
 	sampleBufData := make([]byte, bPerSample)
+	// read whole samples in blocks of the configured size
+	block := make([]byte, max(d.readBufferSize()/bPerSample, 1)*bPerSample)
 
-	decodeF, err := sampleDecodeFloat32Func(int(d.BitDepth), d.WavAudioFormat)
+	decodeF, err := sampleDecodeFloat32Func(d.containerBitDepth(), d.validBitsPerSample(), d.WavAudioFormat, d.byteOrder(), d.clampDecodedFloat())
 	if err != nil {
 		return nil, fmt.Errorf("could not get sample decode func %w", err)
 	}
 
-	i := 0
-	for err == nil {
-		buf.Data[i], err = decodeF(d.PCMChunk, sampleBufData)
-		if err != nil {
-			break
+	for {
+		n, readErr := io.ReadFull(d.PCMChunk, block)
+
+		bufR := bytes.NewReader(block[:n-n%bPerSample])
+		for bufR.Len() > 0 {
+			sample, err := decodeF(bufR, sampleBufData)
+			if err != nil {
+				return buf, err
+			}
+
+			buf.Data = append(buf.Data, sample)
 		}
 
-		i++
-		if i == len(buf.Data) {
-			buf.Data = append(buf.Data, make([]float32, 4096)...)
+		if limitErr := d.decodeLimitErr(len(buf.Data)); limitErr != nil {
+			buf.Data = buf.Data[:d.maxDecodeSamples()]
+			err = limitErr
+
+			break
 		}
-	}
 
-	buf.Data = buf.Data[:i]
+		if readErr != nil {
+			if !errors.Is(readErr, io.EOF) && !errors.Is(readErr, io.ErrUnexpectedEOF) {
+				err = fmt.Errorf("failed to read PCM data: %w", readErr)
+			}
 
-	if errors.Is(err, io.EOF) {
-		err = nil
+			break
+		}
 	}
 
+	d.finishDecoded(buf.Data)
+
 	return buf, err
 }
 
@@ -560,7 +1167,14 @@ func (d *Decoder) readHeaders() error {
 	}
 
 	d.parser.ID = id
-	if d.parser.ID != riff.RiffID {
+
+	switch d.parser.ID {
+	case riff.RiffID:
+		d.bigEndian = false
+	case RIFXID:
+		d.bigEndian = true
+		size = bits.ReverseBytes32(size)
+	default:
 		return fmt.Errorf("%s - %w", d.parser.ID, riff.ErrFmtNotSupported)
 	}
 
@@ -577,7 +1191,7 @@ func (d *Decoder) readHeaders() error {
 	)
 
 	for err == nil {
-		chunk, err = d.parser.NextChunk()
+		chunk, err = d.nextHeaderChunk()
 		if err != nil {
 			break
 		}
@@ -598,7 +1212,7 @@ func (d *Decoder) readHeaders() error {
 }
 
 func (d *Decoder) processFmtChunk(chunk *riff.Chunk, rewindBytes int64) error {
-	fmtChunk, err := decodeWavHeaderChunk(chunk, d.parser)
+	fmtChunk, err := decodeWavHeaderChunk(chunk, d.parser, d.byteOrder(), d.addWarning)
 	if err != nil {
 		return fmt.Errorf("failed to decode fmt chunk: %w", err)
 	}
@@ -610,8 +1224,14 @@ func (d *Decoder) processFmtChunk(chunk *riff.Chunk, rewindBytes int64) error {
 	d.WavAudioFormat = d.parser.WavAudioFormat
 	d.AvgBytesPerSec = d.parser.AvgBytesPerSec
 
+	d.fmtSlack = d.fmtChunkSlack()
+	if d.fmtSlack > 0 {
+		d.addWarning(fmt.Errorf("%w: %d bytes declared, next chunk found %d bytes later", ErrUnexpectedFmtChunkSize, chunk.Size, d.fmtSlack))
+		io.CopyN(io.Discard, d.r, d.fmtSlack)
+	}
+
 	if rewindBytes > 0 {
-		d.r.Seek(-(rewindBytes + int64(chunk.Size) + 8), 1)
+		d.r.Seek(-(rewindBytes + int64(chunk.Size) + d.fmtSlack + 8), 1)
 	}
 
 	return nil
@@ -646,48 +1266,53 @@ func (d *Decoder) decodeHeaderChunkViaRegistry(chunk *riff.Chunk) (bool, error)
 	}
 
 	switch chunk.ID {
-	case CIDList, CIDSmpl, CIDBext, CIDCart:
+	case CIDList, CIDSmpl, CIDBext, CIDCart, CIDCset:
 		return d.decodeChunkViaRegistry(chunk)
 	default:
 		return false, nil
 	}
 }
 
-func decodeWavHeaderChunk(chunk *riff.Chunk, parser *riff.Parser) (*FmtChunk, error) {
+// decodeWavHeaderChunk decodes the fmt chunk. Chunk sizes that don't match
+// the fmt layout are tolerated and reported through warn.
+func decodeWavHeaderChunk(chunk *riff.Chunk, parser *riff.Parser, order binary.ByteOrder, warn func(error)) (*FmtChunk, error) {
 	if chunk == nil || parser == nil {
 		return nil, errNilChunkOrParser
 	}
 
 	fmtChunk := &FmtChunk{}
 
-	err := chunk.ReadLE(&fmtChunk.FormatTag)
+	err := readChunk(chunk, order, &fmtChunk.FormatTag)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read wav format: %w", err)
 	}
 
-	err = chunk.ReadLE(&fmtChunk.NumChannels)
+	err = readChunk(chunk, order, &fmtChunk.NumChannels)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read channels: %w", err)
 	}
 
-	err = chunk.ReadLE(&fmtChunk.SampleRate)
+	err = readChunk(chunk, order, &fmtChunk.SampleRate)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read sample rate: %w", err)
 	}
 
-	err = chunk.ReadLE(&fmtChunk.AvgBytesPerSec)
+	err = readChunk(chunk, order, &fmtChunk.AvgBytesPerSec)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read avg bytes/sec: %w", err)
 	}
 
-	err = chunk.ReadLE(&fmtChunk.BlockAlign)
+	err = readChunk(chunk, order, &fmtChunk.BlockAlign)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read block align: %w", err)
 	}
 
-	err = chunk.ReadLE(&fmtChunk.BitsPerSample)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read bit depth: %w", err)
+	// the legacy 14 byte WAVEFORMAT has no bit depth
+	if chunk.Size >= 16 {
+		err = readChunk(chunk, order, &fmtChunk.BitsPerSample)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read bit depth: %w", err)
+		}
 	}
 
 	parser.NumChannels = fmtChunk.NumChannels
@@ -698,33 +1323,51 @@ func decodeWavHeaderChunk(chunk *riff.Chunk, parser *riff.Parser) (*FmtChunk, er
 	parser.WavAudioFormat = fmtChunk.FormatTag
 
 	if chunk.Size <= 16 {
+		if chunk.Size < 16 {
+			warn(fmt.Errorf("%w: %d bytes", ErrUnexpectedFmtChunkSize, chunk.Size))
+		}
+
 		return fmtChunk, nil
 	}
 
 	var extraSize uint16
 
-	err = chunk.ReadLE(&extraSize)
+	err = readChunk(chunk, order, &extraSize)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read fmt extension size: %w", err)
 	}
 
+	// never read past the chunk, an oversized cbSize would consume the next
+	// chunk header.
+	if available := chunk.Size - 18; int(extraSize) > available {
+		warn(fmt.Errorf("%w: cbSize %d exceeds the %d byte chunk", ErrUnexpectedFmtChunkSize, extraSize, chunk.Size))
+		extraSize = uint16(max(available, 0))
+	} else if available > int(extraSize)+1 {
+		// a single trailing byte is the pad byte of an odd sized chunk
+		warn(fmt.Errorf("%w: %d bytes for cbSize %d", ErrUnexpectedFmtChunkSize, chunk.Size, extraSize))
+	}
+
 	fmtChunk.ExtraData = make([]byte, extraSize)
 	if extraSize > 0 {
-		err := chunk.ReadLE(&fmtChunk.ExtraData)
+		err := readChunk(chunk, order, &fmtChunk.ExtraData)
 		if err != nil {
 			return nil, fmt.Errorf("failed to read fmt extension data: %w", err)
 		}
 	}
 
 	if fmtChunk.FormatTag != wavFormatExtensible || extraSize < 22 {
+		if fmtChunk.FormatTag == wavFormatExtensible {
+			warn(fmt.Errorf("%w: extensible format with cbSize %d", ErrUnexpectedFmtChunkSize, extraSize))
+		}
+
 		chunk.Drain()
 
 		return fmtChunk, nil
 	}
 
 	ext := &FmtExtensible{}
-	ext.ValidBitsPerSample = binary.LittleEndian.Uint16(fmtChunk.ExtraData[0:2])
-	ext.ChannelMask = binary.LittleEndian.Uint32(fmtChunk.ExtraData[2:6])
+	ext.ValidBitsPerSample = order.Uint16(fmtChunk.ExtraData[0:2])
+	ext.ChannelMask = order.Uint32(fmtChunk.ExtraData[2:6])
 	copy(ext.SubFormat[:], fmtChunk.ExtraData[6:22])
 
 	if len(fmtChunk.ExtraData) > 22 {
@@ -753,6 +1396,11 @@ func (d *Decoder) captureUnknownChunk(chunk *riff.Chunk, beforeData bool) {
 
 	chunk.Drain()
 
+	// keep the declared size, the encoder writes the pad byte again.
+	if size := d.declaredChunkSize(chunk); size < len(data) {
+		data = data[:size]
+	}
+
 	d.UnknownChunks = append(d.UnknownChunks, RawChunk{
 		ID:         chunk.ID,
 		Size:       uint32(len(data)),
@@ -793,8 +1441,8 @@ func unsupportedCompressedFormatError(wavFormat uint16) error {
 // sampleDecodeFunc returns a function that can be used to convert
 // a byte range into an int value based on the amount of bits used per sample.
 // Note that 8bit samples are unsigned, all other values are signed.
-func sampleDecodeFunc(bitsPerSample int) (func(io.Reader, []byte) (int, error), error) {
-	// NOTE: WAV PCM data is stored using little-endian
+func sampleDecodeFunc(bitsPerSample int, order binary.ByteOrder) (func(io.Reader, []byte) (int, error), error) {
+	// NOTE: WAV PCM data is stored using little-endian, RIFX uses big-endian
 	switch {
 	case bitsPerSample == 8:
 		// 8bit values are unsigned
@@ -805,7 +1453,7 @@ func sampleDecodeFunc(bitsPerSample int) (func(io.Reader, []byte) (int, error),
 	case bitsPerSample > 8 && bitsPerSample <= 16:
 		return func(r io.Reader, buf []byte) (int, error) {
 			_, err := r.Read(buf[:2])
-			return int(int16(binary.LittleEndian.Uint16(buf[:2]))), err
+			return int(int16(order.Uint16(buf[:2]))), err
 		}, nil
 	case bitsPerSample > 16 && bitsPerSample <= 24:
 		// -34,359,738,367 (0x7FFFFF) to 34,359,738,368	(0x800000)
@@ -815,12 +1463,16 @@ func sampleDecodeFunc(bitsPerSample int) (func(io.Reader, []byte) (int, error),
 				return 0, fmt.Errorf("failed to read 24-bit sample: %w", err)
 			}
 
+			if order == binary.BigEndian {
+				return int(int24BETo32(buf[:3])), nil
+			}
+
 			return int(audio.Int24LETo32(buf[:3])), nil
 		}, nil
 	case bitsPerSample > 24 && bitsPerSample <= 32:
 		return func(r io.Reader, buf []byte) (int, error) {
 			_, err := r.Read(buf[:4])
-			return int(int32(binary.LittleEndian.Uint32(buf[:4]))), err
+			return int(int32(order.Uint32(buf[:4]))), err
 		}, nil
 	default:
 		return nil, fmt.Errorf("%w: %d", errUnhandledByteDepth, bitsPerSample)
@@ -828,9 +1480,20 @@ func sampleDecodeFunc(bitsPerSample int) (func(io.Reader, []byte) (int, error),
 }
 
 // sampleDecodeFloat32Func returns a function that can be used to convert
-// a byte range into a normalized float32 value.
-func sampleDecodeFloat32Func(bitsPerSample int, wavFormat uint16) (func(io.Reader, []byte) (float32, error), error) {
+// a byte range into a normalized float32 value. For integer PCM, a validBits
+// value below bitsPerSample (WAVE_FORMAT_EXTENSIBLE ValidBitsPerSample)
+// discards the padding bits and scales by the valid bit count. IEEE float
+// samples are limited to [-1, 1] when clampFloat is set.
+func sampleDecodeFloat32Func(bitsPerSample, validBits int, wavFormat uint16, order binary.ByteOrder, clampFloat bool) (func(io.Reader, []byte) (float32, error), error) {
 	if wavFormat == wavFormatIEEEFloat {
+		limit := func(value float32) float32 {
+			if !clampFloat {
+				return value
+			}
+
+			return clampFloat32(value, -1, 1)
+		}
+
 		switch bitsPerSample {
 		case 32:
 			return func(r io.Reader, buf []byte) (float32, error) {
@@ -839,9 +1502,9 @@ func sampleDecodeFloat32Func(bitsPerSample int, wavFormat uint16) (func(io.Reade
 					return 0, fmt.Errorf("failed to read 32-bit float sample: %w", err)
 				}
 
-				value := math.Float32frombits(binary.LittleEndian.Uint32(buf[:4]))
+				value := math.Float32frombits(order.Uint32(buf[:4]))
 
-				return clampFloat32(value, -1, 1), nil
+				return limit(value), nil
 			}, nil
 		case 64:
 			return func(r io.Reader, buf []byte) (float32, error) {
@@ -850,9 +1513,9 @@ func sampleDecodeFloat32Func(bitsPerSample int, wavFormat uint16) (func(io.Reade
 					return 0, fmt.Errorf("failed to read 64-bit float sample: %w", err)
 				}
 
-				value := math.Float64frombits(binary.LittleEndian.Uint64(buf[:8]))
+				value := math.Float64frombits(order.Uint64(buf[:8]))
 
-				return clampFloat32(float32(value), -1, 1), nil
+				return limit(float32(value)), nil
 			}, nil
 		default:
 			return nil, fmt.Errorf("%w: %d", errUnhandledFloatBitDepth, bitsPerSample)
@@ -897,12 +1560,26 @@ func sampleDecodeFloat32Func(bitsPerSample int, wavFormat uint16) (func(io.Reade
 		return nil, fmt.Errorf("%w: %d", errUnsupportedWavFormat, wavFormat)
 	}
 
-	decodeInt, err := sampleDecodeFunc(bitsPerSample)
+	decodeInt, err := sampleDecodeFunc(bitsPerSample, order)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create int decoder: %w", err)
 	}
 
 	storageBitsPerSample := bytesPerSample(bitsPerSample) * 8
+	if storageBitsPerSample > 8 && validBits > 8 && validBits < bitsPerSample {
+		// the valid bits are left-justified, the padding bits below them are
+		// shifted out so the sample is scaled by its real resolution.
+		shift := storageBitsPerSample - validBits
+
+		return func(r io.Reader, buf []byte) (float32, error) {
+			value, err := decodeInt(r, buf)
+			if err != nil {
+				return 0, fmt.Errorf("failed to decode int sample: %w", err)
+			}
+
+			return normalizePCMIntBits(value>>shift, validBits), nil
+		}, nil
+	}
 
 	return func(r io.Reader, buf []byte) (float32, error) {
 		value, err := decodeInt(r, buf)
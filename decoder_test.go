@@ -1,9 +1,14 @@
 package wav
 
 import (
+	"bytes"
+	"encoding/binary"
 	"errors"
+	"io"
+	"math"
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 	"time"
 
@@ -90,6 +95,178 @@ func TestDecoderRewind(t *testing.T) {
 	assertFloat32SlicesClose(t, buf.Data, newBuf.Data, 1e-6)
 }
 
+func TestDecoder_PeekFormat(t *testing.T) {
+	file, err := os.Open("fixtures/bass.wav")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	decoder := NewDecoder(file)
+
+	fmtChunk, err := decoder.PeekFormat()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if fmtChunk.SampleRate != 44100 || fmtChunk.BitsPerSample != 24 || fmtChunk.NumChannels != 2 {
+		t.Fatalf("unexpected format: %+v", fmtChunk)
+	}
+
+	pos, err := file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if pos != 0 {
+		t.Fatalf("expected reader to be back at 0, got %d", pos)
+	}
+
+	if decoder.NumChans != 0 || decoder.FmtChunk != nil {
+		t.Fatal("expected parser state to be reset")
+	}
+
+	buf, err := decoder.FullPCMBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(buf.Data) == 0 {
+		t.Fatal("expected PCM data after peeking the format")
+	}
+}
+
+func TestDecoder_CopyPCMTo(t *testing.T) {
+	fixtures := []string{
+		"fixtures/kick.wav",
+		"fixtures/M1F1-mulaw-AFsp.wav",
+		"fixtures/addf8-GSM-GW.wav",
+	}
+
+	for _, fixture := range fixtures {
+		t.Run(filepath.Base(fixture), func(t *testing.T) {
+			chunks, err := parseWavChunksFromFile(fixture)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			want, _ := findChunk(chunks, "data")
+			if want == nil {
+				t.Fatal("fixture has no data chunk")
+			}
+
+			file, err := os.Open(fixture)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer file.Close()
+
+			var out bytes.Buffer
+
+			n, err := NewDecoder(file).CopyPCMTo(&out)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if n != int64(out.Len()) {
+				t.Fatalf("copied count mismatch: got %d, buffer holds %d", n, out.Len())
+			}
+
+			if !bytes.Equal(out.Bytes()[:len(want.data)], want.data) {
+				t.Fatal("copied PCM bytes don't match the data chunk payload")
+			}
+		})
+	}
+}
+
+func TestDecoder_IOCopy(t *testing.T) {
+	chunks, err := parseWavChunksFromFile("fixtures/kick.wav")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want, _ := findChunk(chunks, "data")
+	if want == nil {
+		t.Fatal("fixture has no data chunk")
+	}
+
+	file, err := os.Open("fixtures/kick.wav")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	var out bytes.Buffer
+
+	n, err := io.Copy(&out, NewDecoder(file))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n != int64(len(want.data)) || !bytes.Equal(out.Bytes(), want.data) {
+		t.Fatalf("io.Copy mismatch: copied %d bytes, want %d", n, len(want.data))
+	}
+
+	// plain reads must stream the same bytes
+	file.Seek(0, io.SeekStart)
+
+	got, err := io.ReadAll(io.LimitReader(NewDecoder(file), int64(len(want.data))))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(got, want.data) {
+		t.Fatal("read bytes don't match the data chunk payload")
+	}
+}
+
+func TestDecoder_Unwrap(t *testing.T) {
+	enc := NewMemoryEncoder(8000, 16, 1, wavFormatPCM)
+
+	err := enc.WriteSilence(8)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := enc.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	trailer := []byte("custom trailer")
+	r := bytes.NewReader(append(data, trailer...))
+	dec := NewDecoder(r)
+
+	_, err = dec.FullPCMBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	raw := dec.Unwrap()
+	if raw != r {
+		t.Fatal("expected the reader passed to NewDecoder")
+	}
+
+	_, err = raw.Seek(int64(len(data)), io.SeekStart)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := io.ReadAll(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(got, trailer) {
+		t.Fatalf("expected the trailer, got %q", got)
+	}
+
+	var nilDecoder *Decoder
+	if nilDecoder.Unwrap() != nil {
+		t.Fatal("expected a nil reader for a nil decoder")
+	}
+}
+
 func TestDecoder_Duration(t *testing.T) {
 	testCases := []struct {
 		in       string
@@ -155,6 +332,7 @@ func TestDecoder_IsValidFile(t *testing.T) {
 		{"fixtures/addf8-GSM-GW.wav", true},
 		{"fixtures/truspech.wav", true},
 		{"fixtures/voxware.wav", true},
+		{"fixtures/empty-data.wav", true},
 	}
 
 	for _, testCase := range testCases {
@@ -171,6 +349,36 @@ func TestDecoder_IsValidFile(t *testing.T) {
 	}
 }
 
+func TestDecoder_IsEmpty(t *testing.T) {
+	data, err := os.ReadFile("fixtures/empty-data.wav")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dec := NewDecoder(bytes.NewReader(data))
+	if !dec.IsEmpty() {
+		t.Fatal("expected the empty data chunk to be reported")
+	}
+
+	buf, err := dec.FullPCMBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(buf.Data) != 0 || dec.NumChans != 2 {
+		t.Fatalf("expected no samples from a stereo file, got %d samples, %d channels", len(buf.Data), dec.NumChans)
+	}
+
+	kick, err := os.ReadFile("fixtures/kick.wav")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if NewDecoder(bytes.NewReader(kick)).IsEmpty() {
+		t.Fatal("kick.wav isn't empty")
+	}
+}
+
 func TestDecoder_G711FullPCMBuffer(t *testing.T) {
 	testCases := []struct {
 		input        string
@@ -558,6 +766,417 @@ func TestDecoder_Int12MatchesInt16Fixture(t *testing.T) {
 	}
 }
 
+func TestDecoder_Int20In24MatchesInt24Fixture(t *testing.T) {
+	int20File, err := os.Open("fixtures/M1F1-int20in24-ext.wav")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer int20File.Close()
+
+	int24File, err := os.Open("fixtures/M1F1-int24-AFsp.wav")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer int24File.Close()
+
+	int20Dec := NewDecoder(int20File)
+
+	int20Buf, err := int20Dec.FullPCMBuffer()
+	if err != nil {
+		t.Fatalf("failed decoding 20-in-24 fixture: %v", err)
+	}
+
+	if got := int20Dec.validBitsPerSample(); got != 20 {
+		t.Fatalf("expected 20 valid bits, got %d", got)
+	}
+
+	int24Buf, err := NewDecoder(int24File).FullPCMBuffer()
+	if err != nil {
+		t.Fatalf("failed decoding int24 fixture: %v", err)
+	}
+
+	if len(int20Buf.Data) != len(int24Buf.Data) {
+		t.Fatalf("expected matching sample counts, got %d and %d", len(int20Buf.Data), len(int24Buf.Data))
+	}
+
+	// the 20-bit fixture drops the 4 least significant bits of the reference.
+	const tolerance = 1.0 / (1 << 19)
+
+	for i := range int20Buf.Data {
+		if !float32ApproxEqual(int20Buf.Data[i], int24Buf.Data[i], tolerance) {
+			t.Fatalf("sample %d mismatch: int20 %.8f != int24 %.8f", i, int20Buf.Data[i], int24Buf.Data[i])
+		}
+	}
+}
+
+func TestDecoder_Int24In32MatchesPackedInt24(t *testing.T) {
+	data, err := os.ReadFile("fixtures/24in32-stereo.wav")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chunks, err := parseWavChunks(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fmtChunk, _ := findChunk(chunks, "fmt ")
+	dataChunk, _ := findChunk(chunks, "data")
+
+	if fmtChunk == nil || dataChunk == nil {
+		t.Fatal("fixture is missing the fmt or data chunk")
+	}
+
+	// repack the same samples into 3-byte containers, dropping the low
+	// padding byte of each 4-byte container.
+	packedFmt := bytes.Clone(fmtChunk.data)
+	binary.LittleEndian.PutUint32(packedFmt[8:12], 44100*6)
+	binary.LittleEndian.PutUint16(packedFmt[12:14], 6)
+
+	packedData := make([]byte, 0, len(dataChunk.data)/4*3)
+	for i := 0; i+4 <= len(dataChunk.data); i += 4 {
+		packedData = append(packedData, dataChunk.data[i+1:i+4]...)
+	}
+
+	var b bytes.Buffer
+	b.WriteString("RIFF")
+	b.Write(make([]byte, 4))
+	b.WriteString("WAVE")
+	writeTestChunk(t, &b, "fmt ", packedFmt)
+	writeTestChunk(t, &b, "data", packedData)
+
+	packed := b.Bytes()
+	binary.LittleEndian.PutUint32(packed[4:8], uint32(len(packed)-8))
+
+	dec := NewDecoder(bytes.NewReader(data))
+
+	got, err := dec.FullPCMBuffer()
+	if err != nil {
+		t.Fatalf("failed decoding 24-in-32 fixture: %v", err)
+	}
+
+	enc := dec.SampleEncoding()
+	if enc.BitDepth != 32 || enc.ValidBits != 24 {
+		t.Fatalf("expected 24 valid bits in 32-bit containers, got %+v", enc)
+	}
+
+	want, err := NewDecoder(bytes.NewReader(packed)).FullPCMBuffer()
+	if err != nil {
+		t.Fatalf("failed decoding packed 24-bit file: %v", err)
+	}
+
+	if len(got.Data) != 128 {
+		t.Fatalf("expected 128 samples, got %d", len(got.Data))
+	}
+
+	assertFloat32SlicesClose(t, got.Data, want.Data, 0)
+}
+
+func TestSampleDecodeFloat32Func_ValidBitsIgnorePadding(t *testing.T) {
+	decode, err := sampleDecodeFloat32Func(24, 20, wavFormatPCM, binary.LittleEndian, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 0x40000 in 20 bits is half scale; the low nibble holds padding garbage.
+	raw := []byte{0x0F, 0x00, 0x40}
+
+	got, err := decode(bytes.NewReader(raw), make([]byte, 3))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got != 0.5 {
+		t.Fatalf("expected 0.5, got %f", got)
+	}
+}
+
+func TestDecoder_FmtChunkSizes(t *testing.T) {
+	want := []float32{0, 1000.0 / 32768, -1000.0 / 32768, 0.5, -0.5, 32767.0 / 32768, -1, 0}
+
+	for _, fixture := range []string{
+		"fixtures/fmt16-pcm16.wav",
+		"fixtures/fmt18-pcm16.wav",
+		"fixtures/fmt40-pcm16.wav",
+	} {
+		t.Run(filepath.Base(fixture), func(t *testing.T) {
+			file, err := os.Open(fixture)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer file.Close()
+
+			dec := NewDecoder(file)
+
+			buf, err := dec.FullPCMBuffer()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assertFloat32SlicesClose(t, buf.Data, want, 0)
+
+			if warnings := dec.Warnings(); len(warnings) != 0 {
+				t.Fatalf("unexpected warnings: %v", warnings)
+			}
+		})
+	}
+}
+
+func TestDecoder_DataBeforeFmt(t *testing.T) {
+	want := []float32{0, 0.25, 0.5, -0.5, -1, 32767.0 / 32768, 0, -0.25}
+
+	file, err := os.Open("fixtures/data-before-fmt.wav")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	dec := NewDecoder(file)
+	if !dec.IsValidFile() {
+		t.Fatalf("expected a valid file: %v", dec.Err())
+	}
+
+	buf, err := dec.FullPCMBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if dec.NumChans != 1 || dec.SampleRate != 8000 || dec.BitDepth != 16 {
+		t.Fatalf("unexpected format: %d ch, %d Hz, %d bit", dec.NumChans, dec.SampleRate, dec.BitDepth)
+	}
+
+	assertFloat32SlicesClose(t, buf.Data, want, 0)
+
+	_, err = file.Seek(0, io.SeekStart)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dec = NewDecoder(file)
+	dec.ReadMetadata()
+
+	if err := dec.Err(); err != nil {
+		t.Fatalf("read metadata: %v", err)
+	}
+
+	// re-encoding must not write a second fmt chunk
+	for _, chunk := range dec.UnknownChunks {
+		if chunk.ID == [4]byte{'f', 'm', 't', ' '} {
+			t.Fatal("fmt chunk after data captured as unknown chunk")
+		}
+	}
+}
+
+func TestDecoder_ClampFloatOutput(t *testing.T) {
+	var b bytes.Buffer
+	b.WriteString("RIFF")
+	b.Write(make([]byte, 4))
+	b.WriteString("WAVE")
+
+	fmtPayload := make([]byte, 16)
+	binary.LittleEndian.PutUint16(fmtPayload[0:2], wavFormatIEEEFloat)
+	binary.LittleEndian.PutUint16(fmtPayload[2:4], 1)
+	binary.LittleEndian.PutUint32(fmtPayload[4:8], 48000)
+	binary.LittleEndian.PutUint32(fmtPayload[8:12], 192000)
+	binary.LittleEndian.PutUint16(fmtPayload[12:14], 4)
+	binary.LittleEndian.PutUint16(fmtPayload[14:16], 32)
+	writeTestChunk(t, &b, "fmt ", fmtPayload)
+
+	samples := []float32{0.5, 1.25, -2}
+	dataPayload := make([]byte, 4*len(samples))
+
+	for i, v := range samples {
+		binary.LittleEndian.PutUint32(dataPayload[i*4:], math.Float32bits(v))
+	}
+
+	writeTestChunk(t, &b, "data", dataPayload)
+
+	data := b.Bytes()
+	binary.LittleEndian.PutUint32(data[4:8], uint32(len(data)-8))
+
+	dec := NewDecoder(bytes.NewReader(data))
+
+	buf, err := dec.FullPCMBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertFloat32SlicesClose(t, buf.Data, []float32{0.5, 1, -1}, 0)
+
+	dec = NewDecoder(bytes.NewReader(data))
+	dec.ClampFloatOutput = false
+
+	buf, err = dec.FullPCMBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertFloat32SlicesClose(t, buf.Data, samples, 0)
+}
+
+func TestDecoder_SetGainDB(t *testing.T) {
+	data, err := os.ReadFile("fixtures/two-data-chunks.wav")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	halfDB := 20 * math.Log10(0.5)
+
+	testCases := []struct {
+		name  string
+		db    float64
+		clamp bool
+		want  []float32
+	}{
+		{"unity", 0, true, []float32{0, 0.25, 0.5, -0.25}},
+		{"attenuate", halfDB, true, []float32{0, 0.125, 0.25, -0.125}},
+		{"boost clamped", 2 * -halfDB, true, []float32{0, 1, 1, -1}},
+		{"boost unclamped", 2 * -halfDB, false, []float32{0, 1, 2, -1}},
+		{"mute", math.Inf(-1), true, []float32{0, 0, 0, 0}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			dec := NewDecoder(bytes.NewReader(data))
+			dec.ClampFloatOutput = testCase.clamp
+			dec.SetGainDB(testCase.db)
+
+			buf, err := dec.FullPCMBuffer()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assertFloat32SlicesClose(t, buf.Data, testCase.want, 1e-6)
+
+			err = dec.Rewind()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			block := &audio.Float32Buffer{Data: make([]float32, 8)}
+
+			n, err := dec.PCMBuffer(block)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assertFloat32SlicesClose(t, block.Data[:n], testCase.want, 1e-6)
+		})
+	}
+
+	gsm, err := os.ReadFile("fixtures/addf8-GSM-GW.wav")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plain, err := NewDecoder(bytes.NewReader(gsm)).FullPCMBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dec := NewDecoder(bytes.NewReader(gsm))
+	dec.SetGainDB(halfDB)
+
+	halved, err := dec.FullPCMBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := make([]float32, len(plain.Data))
+	for i, val := range plain.Data {
+		want[i] = val / 2
+	}
+
+	assertFloat32SlicesClose(t, halved.Data, want, 1e-6)
+}
+
+func TestDecoder_FmtChunkOversizedCbSize(t *testing.T) {
+	var b bytes.Buffer
+	b.WriteString("RIFF")
+	b.Write(make([]byte, 4))
+	b.WriteString("WAVE")
+
+	// an 18 byte fmt chunk claiming 22 extension bytes
+	fmtPayload := make([]byte, 18)
+	binary.LittleEndian.PutUint16(fmtPayload[0:2], wavFormatPCM)
+	binary.LittleEndian.PutUint16(fmtPayload[2:4], 1)
+	binary.LittleEndian.PutUint32(fmtPayload[4:8], 8000)
+	binary.LittleEndian.PutUint32(fmtPayload[8:12], 16000)
+	binary.LittleEndian.PutUint16(fmtPayload[12:14], 2)
+	binary.LittleEndian.PutUint16(fmtPayload[14:16], 16)
+	binary.LittleEndian.PutUint16(fmtPayload[16:18], 22)
+	writeTestChunk(t, &b, "fmt ", fmtPayload)
+	writeTestChunk(t, &b, "data", []byte{0x00, 0x40, 0x00, 0xc0})
+
+	data := b.Bytes()
+	binary.LittleEndian.PutUint32(data[4:8], uint32(len(data)-8))
+
+	dec := NewDecoder(bytes.NewReader(data))
+
+	buf, err := dec.FullPCMBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertFloat32SlicesClose(t, buf.Data, []float32{0.5, -0.5}, 0)
+
+	warnings := dec.Warnings()
+	if len(warnings) != 1 || !errors.Is(warnings[0], ErrUnexpectedFmtChunkSize) {
+		t.Fatalf("expected an unexpected fmt size warning, got %v", warnings)
+	}
+}
+
+func TestDecoder_FmtChunkSizeLie(t *testing.T) {
+	// the fmt chunk declares 16 bytes but carries an 18 byte WAVEFORMATEX
+	data, err := os.ReadFile("fixtures/fmt-size-lie.wav")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dec := NewDecoder(bytes.NewReader(data))
+	dec.ReadMetadata()
+
+	if err := dec.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if dec.Metadata == nil || dec.Metadata.Title != "size lie" {
+		t.Fatalf("expected the LIST chunk after fmt to be decoded, got %+v", dec.Metadata)
+	}
+
+	warnings := dec.Warnings()
+	if len(warnings) != 1 || !errors.Is(warnings[0], ErrUnexpectedFmtChunkSize) {
+		t.Fatalf("expected an unexpected fmt size warning, got %v", warnings)
+	}
+
+	err = dec.Rewind()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf, err := dec.FullPCMBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertFloat32SlicesClose(t, buf.Data, []float32{0.25, -0.25}, 0)
+
+	var ids []string
+
+	err = dec.ForEachChunk(func(id [4]byte, _ io.Reader) error {
+		ids = append(ids, string(id[:]))
+
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"fmt ", "LIST", "data"}; !reflect.DeepEqual(ids, want) {
+		t.Fatalf("expected chunks %q, got %q", want, ids)
+	}
+}
+
 func TestDecoder_UnsupportedCompressedFormats(t *testing.T) {
 	testCases := []struct {
 		path       string
@@ -930,6 +1549,10 @@ func TestDecoder_NilReceiver(t *testing.T) {
 		t.Fatal("Format on nil decoder should return nil")
 	}
 
+	if err := dec.Close(); err != nil {
+		t.Fatalf("Close on nil decoder should return nil, got %v", err)
+	}
+
 	dur, err := dec.Duration()
 	if err == nil {
 		t.Fatal("Duration on nil decoder should return error")
@@ -1046,6 +1669,58 @@ func TestEncoder_WriteExtensibleFmtChunk(t *testing.T) {
 	}
 }
 
+func TestEncoder_ExtensibleFloatRoundTrip(t *testing.T) {
+	samples := []float32{0, 0.123456789, -0.987654321, 1, -1, 0.5}
+
+	for _, bitDepth := range []int{32, 64} {
+		enc := NewMemoryEncoder(48000, bitDepth, 2, wavFormatExtensible)
+		enc.FmtChunk = &FmtChunk{
+			FormatTag: wavFormatExtensible,
+			Extensible: &FmtExtensible{
+				ValidBitsPerSample: uint16(bitDepth),
+				ChannelMask:        0x3,
+				SubFormat:          makeSubFormatGUID(wavFormatIEEEFloat),
+			},
+		}
+
+		err := enc.Write(&audio.Float32Buffer{
+			Format: &audio.Format{NumChannels: 2, SampleRate: 48000},
+			Data:   samples[:4],
+		})
+		if err != nil {
+			t.Fatalf("%d bit: write failed: %v", bitDepth, err)
+		}
+
+		err = enc.WriteInterleavedFrame(samples[4:])
+		if err != nil {
+			t.Fatalf("%d bit: frame write failed: %v", bitDepth, err)
+		}
+
+		data, err := enc.Close()
+		if err != nil {
+			t.Fatalf("%d bit: close failed: %v", bitDepth, err)
+		}
+
+		dec := NewDecoder(bytes.NewReader(data))
+
+		buf, err := dec.FullPCMBuffer()
+		if err != nil {
+			t.Fatalf("%d bit: decode failed: %v", bitDepth, err)
+		}
+
+		if dec.FmtChunk.FormatTag != wavFormatExtensible || dec.WavAudioFormat != wavFormatIEEEFloat || int(dec.BitDepth) != bitDepth {
+			t.Fatalf("%d bit: unexpected format tag 0x%X, effective %d, %d bit", bitDepth, dec.FmtChunk.FormatTag, dec.WavAudioFormat, dec.BitDepth)
+		}
+
+		if dec.FmtChunk.Extensible.SubFormat != makeSubFormatGUID(wavFormatIEEEFloat) {
+			t.Fatalf("%d bit: unexpected sub format % x", bitDepth, dec.FmtChunk.Extensible.SubFormat)
+		}
+
+		// float samples round trip exactly, integer quantization wouldn't
+		assertFloat32SlicesClose(t, buf.Data, samples, 0)
+	}
+}
+
 func TestFmtChunkExtensibleRoundTripPreservesFields(t *testing.T) {
 	in, err := os.Open("fixtures/M1F1-float32WE-AFsp.wav")
 	if err != nil {
@@ -1356,6 +2031,177 @@ func TestDecoder_Err_ReturnsNilInitially(t *testing.T) {
 	}
 }
 
+func TestDecoder_Samples(t *testing.T) {
+	file, err := os.Open("fixtures/kick.wav")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	samples, sampleRate, numChans, err := NewDecoder(file).Samples()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if sampleRate != 22050 || numChans != 1 {
+		t.Fatalf("unexpected format: %d Hz, %d channels", sampleRate, numChans)
+	}
+
+	_, err = file.Seek(0, io.SeekStart)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf, err := NewDecoder(file).FullPCMBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertFloat32SlicesClose(t, samples, buf.Data, 0)
+
+	var nilDecoder *Decoder
+	if _, _, _, err := nilDecoder.Samples(); err == nil {
+		t.Fatal("expected an error for a nil decoder")
+	}
+}
+
+func TestDecoder_AudioMD5(t *testing.T) {
+	file, err := os.Open("fixtures/kick.wav")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	dec := NewDecoder(file)
+
+	buf, err := dec.FullPCMBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = dec.Rewind()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	original, err := dec.AudioMD5()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the same audio with tags and a different chunk layout
+	enc := NewMemoryEncoder(int(dec.SampleRate), int(dec.BitDepth), int(dec.NumChans), wavFormatPCM)
+	enc.Metadata = &Metadata{Artist: "someone", Title: "retagged"}
+	enc.UnknownChunks = []RawChunk{{ID: [4]byte{'J', 'U', 'N', 'K'}, Data: make([]byte, 10), BeforeData: true}}
+
+	err = enc.Write(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tagged, err := enc.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	retagged, err := NewDecoder(bytes.NewReader(tagged)).AudioMD5()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if retagged != original {
+		t.Fatalf("expected equal digests for identical audio, got %x and %x", original, retagged)
+	}
+
+	// output settings don't change the digest
+	scaled := NewDecoder(bytes.NewReader(tagged))
+	scaled.SetGainDB(-6)
+	scaled.ClampFloatOutput = false
+
+	scaledDigest, err := scaled.AudioMD5()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if scaledDigest != original || !scaled.gainSet || scaled.ClampFloatOutput {
+		t.Fatalf("expected the digest %x without the gain and the settings kept, got %x", original, scaledDigest)
+	}
+
+	buf.Data[100] += 0.01
+
+	enc = NewMemoryEncoder(int(dec.SampleRate), int(dec.BitDepth), int(dec.NumChans), wavFormatPCM)
+
+	err = enc.Write(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	changed, err := enc.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	changedDigest, err := NewDecoder(bytes.NewReader(changed)).AudioMD5()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if changedDigest == original {
+		t.Fatal("expected different digests for different audio")
+	}
+}
+
+func TestHashQuantize(t *testing.T) {
+	testCases := []struct {
+		in   float32
+		want int32
+	}{
+		{0, 0},
+		{0.5, 1 << 30},
+		{-1, math.MinInt32},
+		{1, math.MaxInt32},
+		{2, math.MaxInt32},
+		{-2, math.MinInt32},
+	}
+
+	for _, testCase := range testCases {
+		if got := hashQuantize(testCase.in); got != testCase.want {
+			t.Fatalf("hashQuantize(%f) = %d, want %d", testCase.in, got, testCase.want)
+		}
+	}
+}
+
+func TestDecoder_Close(t *testing.T) {
+	file, err := os.Open("fixtures/addf8-GSM-GW.wav")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	d := NewDecoder(file)
+
+	_, err = d.FullPCMBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = d.Close()
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if d.gsmDec != nil {
+		t.Fatal("expected Close to release the GSM decoder state")
+	}
+
+	bad := NewDecoder(bytes.NewReader([]byte("NOT_RIFF_HEADER_DATA")))
+	bad.ReadInfo()
+
+	if bad.Close() == nil {
+		t.Fatal("expected Close to surface the header error")
+	}
+}
+
 func TestDecoder_G711RoundTrip(t *testing.T) {
 	testCases := []struct {
 		input  string
@@ -1428,3 +2274,204 @@ func TestDecoder_G711RoundTrip(t *testing.T) {
 		})
 	}
 }
+
+// countingReadSeeker counts the bytes read from the wrapped reader.
+type countingReadSeeker struct {
+	io.ReadSeeker
+	read int
+}
+
+func (c *countingReadSeeker) Read(p []byte) (int, error) {
+	n, err := c.ReadSeeker.Read(p)
+	c.read += n
+
+	return n, err
+}
+
+func TestDecoder_FirstNonSilentFrame(t *testing.T) {
+	const (
+		frames = 50000
+		onset  = 3000
+	)
+
+	data := make([]float32, 2*frames)
+	data[2*onset+1] = -0.5
+	data[2*onset+11] = 0.9
+
+	enc := NewMemoryEncoder(44100, 16, 2, wavFormatPCM)
+
+	err := enc.Write(&audio.Float32Buffer{Format: &audio.Format{NumChannels: 2, SampleRate: 44100}, Data: data})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encoded, err := enc.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reader := &countingReadSeeker{ReadSeeker: bytes.NewReader(encoded)}
+	dec := NewDecoder(reader)
+
+	got, err := dec.FirstNonSilentFrame(0.25)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got != onset {
+		t.Fatalf("expected onset frame %d, got %d", onset, got)
+	}
+
+	if reader.read >= len(encoded)/2 {
+		t.Fatalf("expected decoding to stop early, read %d of %d bytes", reader.read, len(encoded))
+	}
+
+	err = dec.Rewind()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err = dec.FirstNonSilentFrame(0.95)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got != -1 {
+		t.Fatalf("expected -1 below threshold, got %d", got)
+	}
+
+	// compressed and non-PCM formats stream through the same path
+	for _, fixture := range []string{"fixtures/addf8-GSM-GW.wav", "fixtures/M1F1-mulaw-AFsp.wav", "fixtures/M1F1-float64-AFsp.wav"} {
+		t.Run(filepath.Base(fixture), func(t *testing.T) {
+			file, err := os.Open(fixture)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer file.Close()
+
+			dec := NewDecoder(file)
+
+			buf, err := dec.FullPCMBuffer()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			want := int64(-1)
+
+			for i, val := range buf.Data {
+				if math.Abs(float64(val)) > 0.1 {
+					want = int64(i / int(dec.NumChans))
+
+					break
+				}
+			}
+
+			err = dec.Rewind()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := dec.FirstNonSilentFrame(0.1)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got != want {
+				t.Fatalf("expected onset frame %d, got %d", want, got)
+			}
+		})
+	}
+}
+
+// relativeSeekFailer rejects relative seeks to exercise the drain fallback.
+type relativeSeekFailer struct {
+	io.ReadSeeker
+}
+
+func (r relativeSeekFailer) Seek(offset int64, whence int) (int64, error) {
+	if whence == io.SeekCurrent && offset != 0 {
+		return 0, errors.New("relative seek not supported")
+	}
+
+	return r.ReadSeeker.Seek(offset, whence)
+}
+
+func TestDecoder_SkipToDataFast(t *testing.T) {
+	const levlSize = 1 << 20
+
+	var b bytes.Buffer
+	b.WriteString("RIFF")
+	b.Write(make([]byte, 4))
+	b.WriteString("WAVE")
+
+	fmtPayload := make([]byte, 16)
+	binary.LittleEndian.PutUint16(fmtPayload[0:2], wavFormatPCM)
+	binary.LittleEndian.PutUint16(fmtPayload[2:4], 1)
+	binary.LittleEndian.PutUint32(fmtPayload[4:8], 8000)
+	binary.LittleEndian.PutUint32(fmtPayload[8:12], 16000)
+	binary.LittleEndian.PutUint16(fmtPayload[12:14], 2)
+	binary.LittleEndian.PutUint16(fmtPayload[14:16], 16)
+	writeTestChunk(t, &b, "fmt ", fmtPayload)
+	writeTestChunk(t, &b, "levl", make([]byte, levlSize))
+	writeTestChunk(t, &b, "bext", encodeBroadcastChunk(&BroadcastExtension{Description: "take"}))
+	writeTestChunk(t, &b, "data", []byte{0x00, 0x20, 0x00, 0xe0})
+
+	data := b.Bytes()
+	binary.LittleEndian.PutUint32(data[4:8], uint32(len(data)-8))
+
+	want := []float32{0.25, -0.25}
+
+	reader := &countingReadSeeker{ReadSeeker: bytes.NewReader(data)}
+	dec := NewDecoder(reader)
+
+	err := dec.SkipToDataFast()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if reader.read > 100 {
+		t.Fatalf("expected the chunks to be skipped, read %d bytes", reader.read)
+	}
+
+	if dec.Metadata != nil {
+		t.Fatalf("expected no decoded metadata, got %+v", dec.Metadata)
+	}
+
+	buf, err := dec.FullPCMBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertFloat32SlicesClose(t, buf.Data, want, 0)
+
+	// FwdToPCM seeks past unknown chunks but still decodes bext
+	reader = &countingReadSeeker{ReadSeeker: bytes.NewReader(data)}
+	dec = NewDecoder(reader)
+
+	err = dec.FwdToPCM()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if reader.read > levlSize/2 {
+		t.Fatalf("expected the levl chunk to be skipped, read %d bytes", reader.read)
+	}
+
+	if dec.Metadata == nil || dec.Metadata.BroadcastExtension == nil || dec.Metadata.BroadcastExtension.Description != "take" {
+		t.Fatalf("expected the bext chunk to be decoded, got %+v", dec.Metadata)
+	}
+
+	dec = NewDecoder(relativeSeekFailer{ReadSeeker: bytes.NewReader(data)})
+
+	err = dec.SkipToDataFast()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf, err = dec.FullPCMBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertFloat32SlicesClose(t, buf.Data, want, 0)
+}
@@ -5,6 +5,8 @@ import (
 	"encoding/binary"
 	"errors"
 	"fmt"
+	"hash"
+	"hash/crc32"
 	"io"
 	"os"
 	"time"
@@ -29,21 +31,102 @@ type Encoder struct {
 	// compression.
 	WavAudioFormat int
 	// FmtChunk optionally controls fmt chunk serialization, including
-	// WAVE_FORMAT_EXTENSIBLE fields.
+	// WAVE_FORMAT_EXTENSIBLE fields. Samples are written in the format named
+	// by its effective format tag, so an extensible fmt chunk with an IEEE
+	// float SubFormat writes 32 or 64-bit float samples.
 	FmtChunk *FmtChunk
 
 	// Metadata contains metadata to inject in the file.
 	Metadata *Metadata
 	// UnknownChunks contains non-core chunks to preserve on write.
 	UnknownChunks []RawChunk
+	// WriteFactChunk emits a fact chunk after the fmt chunk. The sample count
+	// is patched on Close. It is set by NewEncoderFromDecoder when the source
+	// carried a fact chunk so that chunk inventories survive round trips.
+	// A-law and mu-law files always get one, as required for non-PCM formats.
+	WriteFactChunk bool
+	// Normalize applies a gain to each buffer passed to Write before
+	// quantization. Peak and loudness measurements need the whole signal, so
+	// pass the full buffer in a single Write call; WriteFrame is never
+	// normalized. The target level is set with SetNormalizeTarget. Defaults
+	// to NormalizeOff.
+	Normalize NormalizeMode
+	// Signed8 writes 8-bit PCM as signed samples centered at 0 instead of the
+	// standard unsigned samples centered at 128. WAV has no fmt field to
+	// signal this, so readers must be told out of band; standard decoders,
+	// including this package, will treat the data as unsigned.
+	Signed8 bool
+	// DitherIntBuffers adds triangular dither when WriteIntBufferConverting
+	// reduces the bit depth of integer samples.
+	DitherIntBuffers bool
+	// WriteChecksum appends a csum chunk after the data chunk on Close. WAV
+	// has no standard integrity chunk; this package's csum chunk holds a
+	// 4 byte payload with the little-endian CRC-32 (IEEE) of the data chunk
+	// payload, pad byte excluded. See Decoder.VerifyChecksum.
+	WriteChecksum bool
+	// MetadataBeforeData writes the metadata chunks (bext, cart, cue, LIST)
+	// before the data chunk, as broadcast tools such as BWF MetaEdit expect
+	// for BWF files. Metadata must then be set before the first sample is
+	// written. By default metadata is appended after the data chunk on Close.
+	MetadataBeforeData bool
+	// InvertChannels lists channels, counted from 0, whose polarity is
+	// inverted when buffers are written, to fix miswired recordings.
+	InvertChannels []int
+	// SwapChannels lists channel pairs that are exchanged in every frame
+	// written, after InvertChannels is applied. Pairs are applied in order.
+	SwapChannels [][2]int
+	// ExpectedFrames announces how many frames will be written. When set
+	// before the first write, the RIFF size, data chunk size and fact sample
+	// count are written up front, so Close doesn't need to seek back unless
+	// the file turns out differently, e.g. when the metadata changed after
+	// the first write. Close returns ErrFrameCountMismatch when a different
+	// number of frames was written; the sizes are patched to the written
+	// frames in that case.
+	ExpectedFrames int
+	// MaxFrames caps the number of frames written. Once it is reached, the
+	// write methods drop further samples and return ErrMaxFramesReached;
+	// Close writes the sizes of the frames kept. Zero means no limit.
+	MaxFrames int
+	// SampleOrder is the layout of the buffers passed to Write. With
+	// SampleOrderPlanar, the data holds all samples of channel 0, then of
+	// channel 1 and so on. WriteFrame and the other frame based methods
+	// always take interleaved samples. Defaults to SampleOrderInterleaved.
+	SampleOrder SampleOrder
+	// AutoSoftwareTag fills an empty Metadata.Software (ISFT) with
+	// SoftwareTag, creating the metadata if needed, so generated files record
+	// the software that wrote them. Set it before the first write when
+	// MetadataBeforeData or ExpectedFrames is used.
+	AutoSoftwareTag bool
+	// AutoExtensible writes integer PCM with more than two channels or more
+	// than 16 bits as WAVE_FORMAT_EXTENSIBLE, with the PCM sub format and
+	// the default channel mask for the channel count, as the format
+	// specification recommends. By default plain PCM is written.
+	AutoExtensible bool
 
 	WrittenBytes     int
 	frames           int
 	pcmChunkStarted  bool
 	pcmChunkSizePos  int
+	factCountPos     int
 	wroteHeader      bool // true if we've written the header out
 	wroteUnknownPre  bool
 	wroteUnknownPost bool
+	wroteMetadata    bool
+	closed           bool
+	// dataCRC hashes the data chunk payload while it is written, it is only
+	// set when WriteChecksum is enabled.
+	dataCRC hash.Hash32
+	// riffSize is the RIFF size written up front for ExpectedFrames.
+	riffSize int
+	// rawBytes counts the bytes WriteFrame passed through unchanged, the
+	// data chunk size of formats the encoder can't encode itself.
+	rawBytes int
+	// factSamplesOverride is set by SetFactSampleCount and replaces the
+	// frame count in the fact chunk.
+	factSamplesOverride *uint32
+	// normalizeTargetLevel is set by SetNormalizeTarget, nil selects the
+	// default level of the Normalize mode.
+	normalizeTargetLevel *float64
 }
 
 // NewEncoder creates a new encoder to create a new wav file.
@@ -71,6 +154,14 @@ func NewEncoderFromDecoder(w io.WriteSeeker, dec *Decoder) *Encoder {
 		enc.FmtChunk = dec.FmtChunk.Clone()
 	}
 
+	enc.WriteFactChunk = dec.HadFactChunk
+
+	// the frames of a passed through format, e.g. GSM 6.10 blocks, aren't
+	// samples, so the source's sample count is kept
+	if dec.HadFactChunk && !enc.encodesFormat() {
+		enc.SetFactSampleCount(int(dec.CompressedSamples))
+	}
+
 	if len(dec.UnknownChunks) > 0 {
 		enc.UnknownChunks = make([]RawChunk, len(dec.UnknownChunks))
 		for i := range dec.UnknownChunks {
@@ -81,11 +172,128 @@ func NewEncoderFromDecoder(w io.WriteSeeker, dec *Decoder) *Encoder {
 	return enc
 }
 
+// SetFactSampleCount overrides the sample count written to the fact chunk,
+// which defaults to the number of frames written. Use it when passing
+// through compressed data with WriteFrame, where a frame is a coded block
+// rather than a sample. Negative values are treated as 0.
+func (e *Encoder) SetFactSampleCount(n int) {
+	if e == nil {
+		return
+	}
+
+	count := uint32(max(n, 0))
+	e.factSamplesOverride = &count
+}
+
+// factSampleCount returns the fact chunk sample count for the given frames.
+func (e *Encoder) factSampleCount(frames int) uint32 {
+	if e.factSamplesOverride != nil {
+		return *e.factSamplesOverride
+	}
+
+	return uint32(max(frames, 0))
+}
+
+// WriteWAV encodes buf with its format's sample rate and channel count and
+// the given metadata in one call. It writes the header, the samples and the
+// metadata chunks in order and closes the encoder, but not w. Use it instead
+// of driving an Encoder by hand when all samples are available up front.
+func WriteWAV(w io.WriteSeeker, buf *audio.Float32Buffer, bitDepth, audioFormat int, md *Metadata) error {
+	if buf == nil || buf.Format == nil {
+		return errNilBuffer
+	}
+
+	if buf.Format.NumChannels < 1 {
+		return fmt.Errorf("%w: %d", errInvalidChannelCount, buf.Format.NumChannels)
+	}
+
+	enc := NewEncoder(w, buf.Format.SampleRate, bitDepth, buf.Format.NumChannels, audioFormat)
+	enc.Metadata = md
+
+	err := enc.Write(buf)
+	if err != nil {
+		return err
+	}
+
+	return enc.Close()
+}
+
+// EncodeInt16 writes interleaved 16-bit samples of numChans channels as a
+// 16-bit PCM file in one call: header, data and Close, but w isn't closed.
+// The samples are written as is, without a float conversion.
+func EncodeInt16(w io.WriteSeeker, samples []int16, sampleRate, numChans int) error {
+	if numChans < 1 {
+		return fmt.Errorf("%w: %d", errInvalidChannelCount, numChans)
+	}
+
+	if len(samples)%numChans != 0 {
+		return fmt.Errorf("%w: %d samples for %d channels", errFrameChannelMismatch, len(samples), numChans)
+	}
+
+	enc := NewEncoder(w, sampleRate, 16, numChans, wavFormatPCM)
+
+	err := enc.startPCMChunk()
+	if err != nil {
+		return err
+	}
+
+	data := make([]byte, len(samples)*2)
+	for i, sample := range samples {
+		binary.LittleEndian.PutUint16(data[i*2:], uint16(sample))
+	}
+
+	err = enc.writeEncodedFrames(data, len(samples)/numChans)
+	if err != nil {
+		return fmt.Errorf("failed to write samples: %w", err)
+	}
+
+	return enc.Close()
+}
+
+// SetMetadata validates md and sets it as the metadata to write on Close. On
+// validation errors the encoder metadata is left unchanged, see
+// Metadata.Validate.
+func (e *Encoder) SetMetadata(md *Metadata) error {
+	if e == nil {
+		return errNilEncoder
+	}
+
+	err := md.Validate()
+	if err != nil {
+		return err
+	}
+
+	e.Metadata = md
+
+	return nil
+}
+
+// FramesWritten returns the number of frames written so far, e.g. to report
+// the progress of a long encode.
+func (e *Encoder) FramesWritten() int {
+	if e == nil {
+		return 0
+	}
+
+	return e.frames
+}
+
+// DataBytesWritten returns the size of the audio written so far, which is
+// the data chunk size Close writes. Unlike WrittenBytes it excludes the
+// header, the other chunks and the pad byte of an odd sized data chunk.
+func (e *Encoder) DataBytesWritten() int {
+	if e == nil {
+		return 0
+	}
+
+	return int(e.dataChunkSize(e.frames))
+}
+
 // AddLE serializes and adds the passed value using little endian.
 func (e *Encoder) AddLE(src any) error {
 	e.WrittenBytes += binary.Size(src)
 
-	err := binary.Write(e.w, binary.LittleEndian, src)
+	err := binary.Write(e.dataWriter(), binary.LittleEndian, src)
 	if err != nil {
 		return fmt.Errorf("failed to write little endian: %w", err)
 	}
@@ -112,6 +320,15 @@ var (
 	errNilWriter                   = errors.New("can't write to a nil writer")
 	errEncUnsupportedFloatBitDepth = errors.New("unsupported float bit depth")
 	errUnsupportedFrameBitSize     = errors.New("can't add frames of bit size")
+	errFrameChannelMismatch        = errors.New("frame sample count doesn't match channel count")
+	errInvalidChannelCount         = errors.New("invalid channel count")
+
+	// ErrFrameCountMismatch is returned by Close when the number of frames
+	// written differs from Encoder.ExpectedFrames.
+	ErrFrameCountMismatch = errors.New("written frame count doesn't match the expected frames")
+	// ErrEncoderClosed is returned by the write methods once Close was
+	// called, since further samples would land after the finished file.
+	ErrEncoderClosed = errors.New("encoder is closed")
 )
 
 func (e *Encoder) addBuffer(buf *audio.Float32Buffer) error {
@@ -119,94 +336,51 @@ func (e *Encoder) addBuffer(buf *audio.Float32Buffer) error {
 		return errNilBuffer
 	}
 
-	frameCount := buf.NumFrames()
-	audioFormat := e.effectiveAudioFormat()
-	// performance tweak: setup a buffer so we don't do too many writes
-	var err error
-
-	for i := range frameCount {
-		for j := range buf.Format.NumChannels {
-			val := buf.Data[i*buf.Format.NumChannels+j]
-
-			if audioFormat == wavFormatIEEEFloat {
-				switch e.BitDepth {
-				case 32:
-					err = binary.Write(e.buf, binary.LittleEndian, clampFloat32(val, -1, 1))
-					if err != nil {
-						return fmt.Errorf("failed to write float32 sample: %w", err)
-					}
-				case 64:
-					err = binary.Write(e.buf, binary.LittleEndian, clampFloat64(float64(val), -1, 1))
-					if err != nil {
-						return fmt.Errorf("failed to write float64 sample: %w", err)
-					}
-				default:
-					return fmt.Errorf("%w: %d", errEncUnsupportedFloatBitDepth, e.BitDepth)
-				}
-
-				continue
-			}
-
-			if audioFormat == wavFormatALaw {
-				if e.BitDepth != 8 {
-					return fmt.Errorf("%w: %d", errUnsupportedALawBitDepth, e.BitDepth)
-				}
-
-				err := e.buf.WriteByte(encodeALawSample(int16(float32ToPCMInt32(val, 16))))
-				if err != nil {
-					return fmt.Errorf("failed to write A-law sample: %w", err)
-				}
+	return addSamples(e, buf.Data, buf.Format.NumChannels)
+}
 
-				continue
-			}
+// addSamples encodes interleaved samples of numChannels channels to the data
+// chunk. Samples are quantized from float64, so float64 sources don't lose
+// precision before reaching the target bit depth.
+func addSamples[T float32 | float64](e *Encoder, data []T, numChannels int) error {
+	frameCount := 0
+	if numChannels > 0 {
+		frameCount = len(data) / numChannels
+	}
 
-			if audioFormat == wavFormatMuLaw {
-				if e.BitDepth != 8 {
-					return fmt.Errorf("%w: %d", errUnsupportedMuLawBitDepth, e.BitDepth)
-				}
+	return e.addFrames(frameCount, numChannels, func(frame, ch int) float64 {
+		return float64(data[frame*numChannels+ch])
+	})
+}
 
-				err := e.buf.WriteByte(encodeMuLawSample(int16(float32ToPCMInt32(val, 16))))
-				if err != nil {
-					return fmt.Errorf("failed to write mu-law sample: %w", err)
-				}
+// addFrames encodes frameCount frames of numChannels channels, reading the
+// samples through sample, which hides the memory layout of the source.
+func (e *Encoder) addFrames(frameCount, numChannels int, sample func(frame, ch int) float64) error {
+	audioFormat := e.effectiveAudioFormat()
+	frameCount = e.limitFrames(frameCount)
 
-				continue
-			}
+	source, sign, err := e.channelRouting(numChannels)
+	if err != nil {
+		return err
+	}
 
-			if audioFormat != wavFormatPCM {
-				return fmt.Errorf("%w: %d", errUnsupportedWavFormat, audioFormat)
+	for i := range frameCount {
+		for j := range numChannels {
+			val := sample(i, j)
+			if source != nil {
+				val = sample(i, source[j]) * float64(sign[j])
 			}
 
-			switch e.BitDepth {
-			case 8:
-				err = binary.Write(e.buf, binary.LittleEndian, float32ToPCMUint8(val))
-				if err != nil {
-					return fmt.Errorf("failed to write 8-bit sample: %w", err)
-				}
-			case 16:
-				err = binary.Write(e.buf, binary.LittleEndian, int16(float32ToPCMInt32(val, 16)))
-				if err != nil {
-					return fmt.Errorf("failed to write 16-bit sample: %w", err)
-				}
-			case 24:
-				err = binary.Write(e.buf, binary.LittleEndian, audio.Int32toInt24LEBytes(float32ToPCMInt32(val, 24)))
-				if err != nil {
-					return fmt.Errorf("failed to write 24-bit sample: %w", err)
-				}
-			case 32:
-				err = binary.Write(e.buf, binary.LittleEndian, float32ToPCMInt32(val, 32))
-				if err != nil {
-					return fmt.Errorf("failed to write 32-bit frame: %w", err)
-				}
-			default:
-				return fmt.Errorf("%w: %d", errUnsupportedFrameBitSize, e.BitDepth)
+			err = e.encodeSample(audioFormat, val)
+			if err != nil {
+				return err
 			}
 		}
 
 		e.frames++
 	}
 
-	n, err := e.w.Write(e.buf.Bytes())
+	n, err := e.dataWriter().Write(e.buf.Bytes())
 	if err != nil {
 		e.WrittenBytes += n
 		return fmt.Errorf("failed to write buffer: %w", err)
@@ -215,6 +389,84 @@ func (e *Encoder) addBuffer(buf *audio.Float32Buffer) error {
 	e.WrittenBytes += e.buf.Len()
 	e.buf.Reset()
 
+	return e.maxFramesErr()
+}
+
+// encodeSample quantizes a sample to the encoder format and appends it to the
+// pending buffer.
+func (e *Encoder) encodeSample(audioFormat int, val float64) error {
+	var err error
+
+	switch audioFormat {
+	case wavFormatIEEEFloat:
+		switch e.BitDepth {
+		case 32:
+			err = binary.Write(e.buf, binary.LittleEndian, float32(clampFloat64(val, -1, 1)))
+			if err != nil {
+				return fmt.Errorf("failed to write float32 sample: %w", err)
+			}
+		case 64:
+			err = binary.Write(e.buf, binary.LittleEndian, clampFloat64(val, -1, 1))
+			if err != nil {
+				return fmt.Errorf("failed to write float64 sample: %w", err)
+			}
+		default:
+			return fmt.Errorf("%w: %d", errEncUnsupportedFloatBitDepth, e.BitDepth)
+		}
+
+		return nil
+	case wavFormatALaw:
+		if e.BitDepth != 8 {
+			return fmt.Errorf("%w: %d", errUnsupportedALawBitDepth, e.BitDepth)
+		}
+
+		err = e.buf.WriteByte(encodeALawSample(int16(float64ToPCMInt32(val, 16))))
+		if err != nil {
+			return fmt.Errorf("failed to write A-law sample: %w", err)
+		}
+
+		return nil
+	case wavFormatMuLaw:
+		if e.BitDepth != 8 {
+			return fmt.Errorf("%w: %d", errUnsupportedMuLawBitDepth, e.BitDepth)
+		}
+
+		err = e.buf.WriteByte(encodeMuLawSample(int16(float64ToPCMInt32(val, 16))))
+		if err != nil {
+			return fmt.Errorf("failed to write mu-law sample: %w", err)
+		}
+
+		return nil
+	case wavFormatPCM:
+	default:
+		return fmt.Errorf("%w: %d", errUnsupportedWavFormat, audioFormat)
+	}
+
+	switch e.BitDepth {
+	case 8:
+		err = e.buf.WriteByte(e.pcm8Sample(val))
+		if err != nil {
+			return fmt.Errorf("failed to write 8-bit sample: %w", err)
+		}
+	case 16:
+		err = binary.Write(e.buf, binary.LittleEndian, int16(float64ToPCMInt32(val, 16)))
+		if err != nil {
+			return fmt.Errorf("failed to write 16-bit sample: %w", err)
+		}
+	case 24:
+		err = binary.Write(e.buf, binary.LittleEndian, audio.Int32toInt24LEBytes(float64ToPCMInt32(val, 24)))
+		if err != nil {
+			return fmt.Errorf("failed to write 24-bit sample: %w", err)
+		}
+	case 32:
+		err = binary.Write(e.buf, binary.LittleEndian, float64ToPCMInt32(val, 32))
+		if err != nil {
+			return fmt.Errorf("failed to write 32-bit frame: %w", err)
+		}
+	default:
+		return fmt.Errorf("%w: %d", errUnsupportedFrameBitSize, e.BitDepth)
+	}
+
 	return nil
 }
 
@@ -227,6 +479,13 @@ func (e *Encoder) writeHeader() error {
 		return errAlreadyWroteHdr
 	}
 
+	err := e.validateFormat()
+	if err != nil {
+		return err
+	}
+
+	e.applySoftwareTag()
+
 	e.wroteHeader = true
 
 	if e.w == nil {
@@ -237,13 +496,23 @@ func (e *Encoder) writeHeader() error {
 		return nil
 	}
 
+	riffSize := uint32(4294967295)
+	if e.ExpectedFrames > 0 {
+		e.riffSize, err = e.predictRIFFSize()
+		if err != nil {
+			return err
+		}
+
+		riffSize = uint32(e.riffSize)
+	}
+
 	// riff ID
-	err := e.AddLE(riff.RiffID)
+	err = e.AddLE(riff.RiffID)
 	if err != nil {
 		return err
 	}
 	// file size uint32, to update later on.
-	err = e.AddLE(uint32(4294967295))
+	err = e.AddLE(riffSize)
 	if err != nil {
 		return err
 	}
@@ -258,130 +527,400 @@ func (e *Encoder) writeHeader() error {
 		return err
 	}
 
-	return e.writeFmtChunk()
+	err = e.writeFmtChunk()
+	if err != nil {
+		return err
+	}
+
+	return e.writeFactChunk()
+}
+
+func (e *Encoder) writeFactChunk() error {
+	if !e.writesFactChunk() {
+		return nil
+	}
+
+	err := e.AddBE(CIDFact)
+	if err != nil {
+		return fmt.Errorf("failed to write the fact chunk ID: %w", err)
+	}
+
+	err = e.AddLE(uint32(4))
+	if err != nil {
+		return fmt.Errorf("failed to write the fact chunk size: %w", err)
+	}
+
+	// sample count, to update later on if not known up front.
+	e.factCountPos = e.WrittenBytes
+
+	err = e.AddLE(e.factSampleCount(e.ExpectedFrames))
+	if err != nil {
+		return fmt.Errorf("failed to write the fact sample count: %w", err)
+	}
+
+	return nil
 }
 
 // Write encodes and writes the passed buffer to the underlying writer.
-// Don't forget to Close() the encoder or the file won't be valid.
+// Don't forget to Close() the encoder or the file won't be valid. With
+// SampleOrder set to SampleOrderPlanar, the buffer data holds the channels
+// one after another, see WritePlanar.
 func (e *Encoder) Write(buf *audio.Float32Buffer) error {
-	if !e.wroteHeader {
-		err := e.writeHeader()
+	if e.SampleOrder == SampleOrderPlanar && buf != nil {
+		channels, err := planarChannels(buf)
 		if err != nil {
 			return err
 		}
+
+		return e.WritePlanar(channels)
 	}
 
-	if !e.pcmChunkStarted {
-		if !e.wroteUnknownPre {
-			err := e.writeUnknownChunks(true)
-			if err != nil {
-				return fmt.Errorf("error encoding pre-data unknown chunks %w", err)
-			}
+	err := e.startPCMChunk()
+	if err != nil {
+		return err
+	}
 
-			e.wroteUnknownPre = true
-		}
+	return e.addBuffer(e.normalizedCopy(buf))
+}
 
-		// sound header
-		err := e.AddLE(riff.DataFormatID)
-		if err != nil {
-			return fmt.Errorf("error encoding sound header %w", err)
+// WriteInterleavedFrame writes one frame holding exactly NumChans samples
+// and counts it as a single frame. Unlike WriteFrame, which takes one sample
+// per call, this is the preferred streaming API for multi-channel output.
+func (e *Encoder) WriteInterleavedFrame(frame []float32) error {
+	if len(frame) != e.NumChans {
+		return fmt.Errorf("%w: got %d samples for %d channels", errFrameChannelMismatch, len(frame), e.NumChans)
+	}
+
+	err := e.startPCMChunk()
+	if err != nil {
+		return err
+	}
+
+	return e.addBuffer(&audio.Float32Buffer{
+		Data:   frame,
+		Format: &audio.Format{NumChannels: e.NumChans, SampleRate: e.SampleRate},
+	})
+}
+
+// WriteComplexFrame writes an I/Q sample of an analytic signal as one frame of
+// a 2-channel file, the real part to channel 0 and the imaginary part to
+// channel 1.
+func (e *Encoder) WriteComplexFrame(c complex64) error {
+	if e.NumChans != 2 {
+		return fmt.Errorf("%w: complex frames need 2 channels, got %d", errFrameChannelMismatch, e.NumChans)
+	}
+
+	return e.WriteInterleavedFrame([]float32{real(c), imag(c)})
+}
+
+// WriteFrames writes a batch of frames, each holding exactly NumChans
+// samples, in a single buffered write. Nothing is written when a frame has
+// the wrong length. Like WriteInterleavedFrame, the frames aren't normalized.
+func (e *Encoder) WriteFrames(frames [][]float32) error {
+	for i, frame := range frames {
+		if len(frame) != e.NumChans {
+			return fmt.Errorf("%w: frame %d has %d samples for %d channels", errFrameChannelMismatch, i, len(frame), e.NumChans)
 		}
+	}
 
-		e.pcmChunkStarted = true
+	if len(frames) == 0 {
+		return nil
+	}
 
-		// write a temporary chunksize
-		e.pcmChunkSizePos = e.WrittenBytes
+	err := e.startPCMChunk()
+	if err != nil {
+		return err
+	}
 
-		err = e.AddLE(uint32(4294967295))
-		if err != nil {
-			return fmt.Errorf("%w when writing wav data chunk size header", err)
+	data := make([]float32, 0, len(frames)*e.NumChans)
+	for _, frame := range frames {
+		data = append(data, frame...)
+	}
+
+	return e.addBuffer(&audio.Float32Buffer{
+		Data:   data,
+		Format: &audio.Format{NumChannels: e.NumChans, SampleRate: e.SampleRate},
+	})
+}
+
+// WriteFloat64Buffer writes interleaved float64 samples of numChans channels.
+// Unlike Write, the samples are quantized to the target bit depth without a
+// float32 intermediate, and 64-bit float files receive them unchanged apart
+// from clamping to [-1, 1]. Normalize and the channel routing apply as for
+// Write.
+func (e *Encoder) WriteFloat64Buffer(data []float64, numChans int) error {
+	if numChans < 1 {
+		return fmt.Errorf("%w: %d", errInvalidChannelCount, numChans)
+	}
+
+	err := e.startPCMChunk()
+	if err != nil {
+		return err
+	}
+
+	if e.Normalize != NormalizeOff {
+		data = e.normalizedFloat64Copy(data, numChans)
+	}
+
+	return addSamples(e, data, numChans)
+}
+
+// WriteSilence writes frames*NumChans silent samples in a single write. The
+// encoded silence depends on the format: unsigned 8-bit PCM and G.711 don't
+// encode silence as a zero byte.
+func (e *Encoder) WriteSilence(frames int) error {
+	if frames <= 0 {
+		return nil
+	}
+
+	err := e.startPCMChunk()
+	if err != nil {
+		return err
+	}
+
+	sample, err := e.silenceSample()
+	if err != nil {
+		return err
+	}
+
+	frames = e.limitFrames(frames)
+
+	err = e.writeEncodedFrames(bytes.Repeat(sample, frames*e.NumChans), frames)
+	if err != nil {
+		return fmt.Errorf("failed to write silence: %w", err)
+	}
+
+	return e.maxFramesErr()
+}
+
+// writeEncodedFrames writes already encoded sample data holding the given
+// number of frames to the open data chunk.
+func (e *Encoder) writeEncodedFrames(data []byte, frames int) error {
+	n, err := e.dataWriter().Write(data)
+	e.WrittenBytes += n
+
+	if err != nil {
+		return err
+	}
+
+	e.frames += frames
+
+	return nil
+}
+
+// silenceSample returns the encoded bytes of a single silent sample.
+func (e *Encoder) silenceSample() ([]byte, error) {
+	err := e.validateFormat()
+	if err != nil {
+		return nil, err
+	}
+
+	if !e.encodesFormat() {
+		return nil, fmt.Errorf("%w: %d", errUnsupportedWavFormat, e.effectiveAudioFormat())
+	}
+
+	switch e.effectiveAudioFormat() {
+	case wavFormatALaw:
+		return []byte{encodeALawSample(0)}, nil
+	case wavFormatMuLaw:
+		return []byte{encodeMuLawSample(0)}, nil
+	case wavFormatPCM:
+		if e.BitDepth == 8 {
+			return []byte{e.pcm8Sample(0)}, nil
 		}
 	}
 
-	return e.addBuffer(buf)
+	return make([]byte, e.BitDepth/8), nil
 }
 
-// WriteFrame writes a single frame of data to the underlying writer.
-func (e *Encoder) WriteFrame(value any) error {
-	if !e.wroteHeader {
-		e.writeHeader()
+// pcm8Sample quantizes a sample to an 8-bit PCM byte honoring Signed8.
+func (e *Encoder) pcm8Sample(val float64) uint8 {
+	scaled := float64ToPCMUint8(val)
+	if e.Signed8 {
+		return uint8(int8(int(scaled) - 128))
 	}
 
-	if !e.pcmChunkStarted {
-		if !e.wroteUnknownPre {
-			err := e.writeUnknownChunks(true)
-			if err != nil {
-				return fmt.Errorf("error encoding pre-data unknown chunks %w", err)
-			}
+	return scaled
+}
 
-			e.wroteUnknownPre = true
+// validateFormat checks the channel count and, for the formats the encoder
+// encodes, the bit depth, so that no malformed header gets written. Other
+// format tags, like GSM 6.10 passed through by WriteFrame, are left alone.
+func (e *Encoder) validateFormat() error {
+	if e.NumChans < 1 {
+		return fmt.Errorf("%w: %d", errInvalidChannelCount, e.NumChans)
+	}
+
+	switch e.effectiveAudioFormat() {
+	case wavFormatIEEEFloat:
+		if e.BitDepth != 32 && e.BitDepth != 64 {
+			return fmt.Errorf("%w: %d", errEncUnsupportedFloatBitDepth, e.BitDepth)
+		}
+	case wavFormatALaw:
+		if e.BitDepth != 8 {
+			return fmt.Errorf("%w: %d", errUnsupportedALawBitDepth, e.BitDepth)
+		}
+	case wavFormatMuLaw:
+		if e.BitDepth != 8 {
+			return fmt.Errorf("%w: %d", errUnsupportedMuLawBitDepth, e.BitDepth)
 		}
+	case wavFormatPCM:
+		switch e.BitDepth {
+		case 8, 16, 24, 32:
+		default:
+			return fmt.Errorf("%w: %d", errUnsupportedFrameBitSize, e.BitDepth)
+		}
+	}
 
-		// sound header
-		err := e.AddLE(riff.DataFormatID)
+	return nil
+}
+
+// encodesFormat reports whether the encoder converts samples to the audio
+// format, as opposed to passing data through WriteFrame unchanged.
+func (e *Encoder) encodesFormat() bool {
+	switch e.effectiveAudioFormat() {
+	case wavFormatPCM, wavFormatIEEEFloat, wavFormatALaw, wavFormatMuLaw:
+		return true
+	default:
+		return false
+	}
+}
+
+// startPCMChunk writes the header, the pre-data chunks and the data chunk
+// header once. Every write method calls it before writing anything, so it
+// also rejects writes after Close.
+func (e *Encoder) startPCMChunk() error {
+	if e.closed {
+		return ErrEncoderClosed
+	}
+
+	if e.pcmChunkStarted {
+		return nil
+	}
+
+	if !e.wroteHeader {
+		err := e.writeHeader()
 		if err != nil {
-			return fmt.Errorf("error encoding sound header %w", err)
+			return err
 		}
+	}
 
-		e.pcmChunkStarted = true
+	if !e.wroteUnknownPre {
+		err := e.writeUnknownChunks(true)
+		if err != nil {
+			return fmt.Errorf("error encoding pre-data unknown chunks %w", err)
+		}
 
-		// write a temporary chunksize
-		e.pcmChunkSizePos = e.WrittenBytes
+		e.wroteUnknownPre = true
+	}
 
-		err = e.AddLE(uint32(4294967295))
+	if e.MetadataBeforeData && e.Metadata != nil {
+		err := e.writeMetadata()
 		if err != nil {
-			return fmt.Errorf("%w when writing wav data chunk size header", err)
+			return fmt.Errorf("error encoding metadata %w", err)
 		}
+
+		e.wroteMetadata = true
 	}
 
-	e.frames++
+	// sound header
+	err := e.AddLE(riff.DataFormatID)
+	if err != nil {
+		return fmt.Errorf("error encoding sound header %w", err)
+	}
 
-	switch val := value.(type) {
-	case float32:
-		audioFormat := e.effectiveAudioFormat()
-		if audioFormat == wavFormatIEEEFloat {
-			switch e.BitDepth {
-			case 32:
-				return e.AddLE(clampFloat32(val, -1, 1))
-			case 64:
-				return e.AddLE(clampFloat64(float64(val), -1, 1))
-			default:
-				return fmt.Errorf("%w: %d", errEncUnsupportedFloatBitDepth, e.BitDepth)
-			}
-		}
+	e.pcmChunkStarted = true
 
-		if audioFormat == wavFormatALaw {
-			if e.BitDepth != 8 {
-				return fmt.Errorf("%w: %d", errUnsupportedALawBitDepth, e.BitDepth)
-			}
+	// write a temporary chunksize
+	e.pcmChunkSizePos = e.WrittenBytes
 
-			return e.AddLE(encodeALawSample(int16(float32ToPCMInt32(val, 16))))
-		}
+	chunkSize := uint32(4294967295)
+	if e.ExpectedFrames > 0 {
+		chunkSize = e.dataChunkSize(e.ExpectedFrames)
+	}
 
-		if audioFormat == wavFormatMuLaw {
-			if e.BitDepth != 8 {
-				return fmt.Errorf("%w: %d", errUnsupportedMuLawBitDepth, e.BitDepth)
-			}
+	err = e.AddLE(chunkSize)
+	if err != nil {
+		return fmt.Errorf("%w when writing wav data chunk size header", err)
+	}
 
-			return e.AddLE(encodeMuLawSample(int16(float32ToPCMInt32(val, 16))))
-		}
+	if e.WriteChecksum {
+		e.dataCRC = crc32.NewIEEE()
+	}
 
-		if audioFormat != wavFormatPCM {
-			return fmt.Errorf("%w: %d", errUnsupportedWavFormat, audioFormat)
-		}
+	return nil
+}
 
-		switch e.BitDepth {
-		case 8:
-			return e.AddLE(float32ToPCMUint8(val))
-		case 16:
-			return e.AddLE(int16(float32ToPCMInt32(val, 16)))
-		case 24:
-			return e.AddLE(audio.Int32toInt24LEBytes(float32ToPCMInt32(val, 24)))
-		case 32:
-			return e.AddLE(float32ToPCMInt32(val, 32))
-		default:
-			return fmt.Errorf("%w: %d", errUnsupportedFrameBitSize, e.BitDepth)
-		}
+// dataWriter returns the writer for sample data, which also feeds the data
+// checksum while the data chunk is open.
+func (e *Encoder) dataWriter() io.Writer {
+	if e.dataCRC == nil {
+		return e.w
+	}
+
+	return io.MultiWriter(e.w, e.dataCRC)
+}
+
+// writeDataPad ends an odd sized data chunk, e.g. 8-bit mono with an odd
+// frame count, with the pad byte RIFF requires. The pad byte isn't part of
+// the declared chunk size nor of the data checksum.
+func (e *Encoder) writeDataPad() error {
+	if e.dataChunkSize(e.frames)%2 == 0 {
+		return nil
+	}
+
+	n, err := e.w.Write([]byte{0})
+	e.WrittenBytes += n
+
+	if err != nil {
+		return fmt.Errorf("failed to write data chunk padding: %w", err)
+	}
+
+	return nil
+}
+
+// writeChecksumChunk closes the data checksum and writes the csum chunk.
+func (e *Encoder) writeChecksumChunk() error {
+	if e.dataCRC == nil {
+		return nil
+	}
+
+	sum := make([]byte, 4)
+	binary.LittleEndian.PutUint32(sum, e.dataCRC.Sum32())
+	e.dataCRC = nil
+
+	return e.writeRawChunk(RawChunk{ID: CIDCsum, Data: sum})
+}
+
+// WriteFrame writes a single frame of data to the underlying writer.
+func (e *Encoder) WriteFrame(value any) error {
+	if e.closed {
+		return ErrEncoderClosed
+	}
+
+	if e.limitFrames(1) == 0 {
+		return ErrMaxFramesReached
+	}
+
+	err := e.writeFrame(value)
+	if err != nil {
+		return err
+	}
+
+	return e.maxFramesErr()
+}
+
+func (e *Encoder) writeFrame(value any) error {
+	err := e.startPCMChunk()
+	if err != nil {
+		return err
+	}
+
+	e.frames++
+
+	switch val := value.(type) {
+	case float32:
+		return e.writeFloat32Sample(val)
 	case float64:
 		if e.effectiveAudioFormat() == wavFormatIEEEFloat {
 			switch e.BitDepth {
@@ -394,12 +933,63 @@ func (e *Encoder) WriteFrame(value any) error {
 			}
 		}
 
-		return e.WriteFrame(float32(val))
+		return e.writeFloat32Sample(float32(val))
 	default:
+		e.rawBytes += binary.Size(value)
+
 		return e.AddLE(value)
 	}
 }
 
+// writeFloat32Sample encodes one sample in the encoder's format. Unlike
+// WriteFrame, it leaves the frame count and MaxFrames alone.
+func (e *Encoder) writeFloat32Sample(val float32) error {
+	audioFormat := e.effectiveAudioFormat()
+	if audioFormat == wavFormatIEEEFloat {
+		switch e.BitDepth {
+		case 32:
+			return e.AddLE(clampFloat32(val, -1, 1))
+		case 64:
+			return e.AddLE(clampFloat64(float64(val), -1, 1))
+		default:
+			return fmt.Errorf("%w: %d", errEncUnsupportedFloatBitDepth, e.BitDepth)
+		}
+	}
+
+	if audioFormat == wavFormatALaw {
+		if e.BitDepth != 8 {
+			return fmt.Errorf("%w: %d", errUnsupportedALawBitDepth, e.BitDepth)
+		}
+
+		return e.AddLE(encodeALawSample(int16(float32ToPCMInt32(val, 16))))
+	}
+
+	if audioFormat == wavFormatMuLaw {
+		if e.BitDepth != 8 {
+			return fmt.Errorf("%w: %d", errUnsupportedMuLawBitDepth, e.BitDepth)
+		}
+
+		return e.AddLE(encodeMuLawSample(int16(float32ToPCMInt32(val, 16))))
+	}
+
+	if audioFormat != wavFormatPCM {
+		return fmt.Errorf("%w: %d", errUnsupportedWavFormat, audioFormat)
+	}
+
+	switch e.BitDepth {
+	case 8:
+		return e.AddLE(e.pcm8Sample(float64(val)))
+	case 16:
+		return e.AddLE(int16(float32ToPCMInt32(val, 16)))
+	case 24:
+		return e.AddLE(audio.Int32toInt24LEBytes(float32ToPCMInt32(val, 24)))
+	case 32:
+		return e.AddLE(float32ToPCMInt32(val, 32))
+	default:
+		return fmt.Errorf("%w: %d", errUnsupportedFrameBitSize, e.BitDepth)
+	}
+}
+
 func (e *Encoder) effectiveAudioFormat() int {
 	if e.FmtChunk != nil {
 		return int(e.FmtChunk.EffectiveFormatTag())
@@ -408,10 +998,57 @@ func (e *Encoder) effectiveAudioFormat() int {
 	return e.WavAudioFormat
 }
 
+// writesFactChunk reports whether a fact chunk follows the fmt chunk.
+func (e *Encoder) writesFactChunk() bool {
+	return e.WriteFactChunk || isG711Format(e.effectiveAudioFormat())
+}
+
 func (e *Encoder) effectiveBlockAlign() int {
+	// G.711 codes every sample in one byte
+	if isG711Format(e.effectiveAudioFormat()) {
+		return e.NumChans
+	}
+
 	return e.NumChans * bytesPerSample(e.BitDepth)
 }
 
+// isG711Format reports whether audioFormat is A-law or mu-law.
+func isG711Format(audioFormat int) bool {
+	return audioFormat == wavFormatALaw || audioFormat == wavFormatMuLaw
+}
+
+// defaultAvgBytesPerSec returns the data rate written to the fmt chunk when
+// none is provided. Compressed formats use their coded rate.
+func (e *Encoder) defaultAvgBytesPerSec(blockAlign int) uint32 {
+	if e.effectiveAudioFormat() == wavFormatGSM610 {
+		// 65 byte blocks per 320 samples, 1625 bytes/sec at 8 kHz.
+		return uint32(e.SampleRate * e.NumChans * gsmBlockSize / gsmSamplesPerBlock)
+	}
+
+	return uint32(e.SampleRate * blockAlign)
+}
+
+// keepsFmtAvgBytesPerSec reports whether a non-zero FmtChunk.AvgBytesPerSec
+// should be written as is. It is ignored when the FmtChunk describes a
+// different rate, channel count or bit depth than the encoder, so that a
+// chunk copied from a decoder doesn't carry a stale value.
+func (e *Encoder) keepsFmtAvgBytesPerSec() bool {
+	fmtChunk := e.FmtChunk
+	if fmtChunk == nil || fmtChunk.AvgBytesPerSec == 0 {
+		return false
+	}
+
+	if fmtChunk.SampleRate != 0 && int(fmtChunk.SampleRate) != e.SampleRate {
+		return false
+	}
+
+	if fmtChunk.NumChannels != 0 && int(fmtChunk.NumChannels) != e.NumChans {
+		return false
+	}
+
+	return fmtChunk.BitsPerSample == 0 || int(fmtChunk.BitsPerSample) == e.BitDepth
+}
+
 func (e *Encoder) buildFmtChunkForWrite() *FmtChunk {
 	blockAlign := e.effectiveBlockAlign()
 
@@ -419,7 +1056,7 @@ func (e *Encoder) buildFmtChunkForWrite() *FmtChunk {
 		FormatTag:      uint16(e.WavAudioFormat),
 		NumChannels:    uint16(e.NumChans),
 		SampleRate:     uint32(e.SampleRate),
-		AvgBytesPerSec: uint32(e.SampleRate * blockAlign),
+		AvgBytesPerSec: e.defaultAvgBytesPerSec(blockAlign),
 		BlockAlign:     uint16(blockAlign),
 		BitsPerSample:  uint16(e.BitDepth),
 	}
@@ -429,7 +1066,19 @@ func (e *Encoder) buildFmtChunkForWrite() *FmtChunk {
 		chunk.SampleRate = uint32(e.SampleRate)
 		chunk.BlockAlign = uint16(blockAlign)
 		chunk.BitsPerSample = uint16(e.BitDepth)
-		chunk.AvgBytesPerSec = uint32(e.SampleRate * blockAlign)
+
+		if !e.keepsFmtAvgBytesPerSec() {
+			chunk.AvgBytesPerSec = e.defaultAvgBytesPerSec(blockAlign)
+		}
+	}
+
+	if e.AutoExtensible && chunk.FormatTag == wavFormatPCM && (e.NumChans > 2 || e.BitDepth > 16) {
+		chunk.FormatTag = wavFormatExtensible
+		chunk.Extensible = &FmtExtensible{
+			ValidBitsPerSample: uint16(e.BitDepth),
+			ChannelMask:        defaultChannelMask(e.NumChans),
+			SubFormat:          makeSubFormatGUID(wavFormatPCM),
+		}
 	}
 
 	if chunk.FormatTag == wavFormatExtensible && chunk.Extensible == nil {
@@ -545,17 +1194,9 @@ func (e *Encoder) writeMetadata() error {
 		return nil
 	}
 
-	err = e.AddBE(CIDList)
-	if err != nil {
-		return fmt.Errorf("failed to write the LIST chunk ID: %w", err)
-	}
-
-	err = e.AddLE(uint32(len(chunkData)))
-	if err != nil {
-		return fmt.Errorf("failed to write the LIST chunk size: %w", err)
-	}
-
-	return e.AddBE(chunkData)
+	// writeRawChunk adds the pad byte for odd sized lists, which matters
+	// once more chunks follow, see MetadataBeforeData.
+	return e.writeRawChunk(RawChunk{ID: CIDList, Data: chunkData})
 }
 
 func (e *Encoder) encodeMetadataViaRegistry() error {
@@ -613,6 +1254,11 @@ func (e *Encoder) writeUnknownChunks(beforeData bool) error {
 			continue
 		}
 
+		// a preserved checksum would be stale next to the fresh one
+		if e.WriteChecksum && chunk.ID == CIDCsum {
+			continue
+		}
+
 		err := e.writeRawChunk(chunk)
 		if err != nil {
 			return err
@@ -622,13 +1268,106 @@ func (e *Encoder) writeUnknownChunks(beforeData bool) error {
 	return nil
 }
 
+// Checkpoint rewrites the RIFF, data and fact sizes to match what was
+// written so far and seeks back to the end, so that a crash before Close still
+// leaves a playable file holding the audio up to the last checkpoint. Chunks
+// written on Close, such as metadata, are not part of a checkpoint.
+//
+// Each call costs three seeks and a few small writes, plus an fsync when
+// writing to an *os.File, so call it periodically (e.g. every few seconds of
+// audio) rather than after every buffer.
+func (e *Encoder) Checkpoint() error {
+	if e == nil {
+		return errNilEncoder
+	}
+
+	if e.w == nil {
+		return errNilWriter
+	}
+
+	if e.closed {
+		return ErrEncoderClosed
+	}
+
+	if !e.wroteHeader {
+		return nil
+	}
+
+	err := e.patchSizes()
+	if err != nil {
+		return err
+	}
+
+	if f, ok := e.w.(*os.File); ok {
+		err := f.Sync()
+		if err != nil {
+			return fmt.Errorf("failed to sync file: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// patchSizes seeks back to write the current RIFF size, data chunk size and
+// fact sample count, then seeks to the end. Unlike AddLE it doesn't count the
+// rewritten bytes in WrittenBytes.
+func (e *Encoder) patchSizes() error {
+	// go back and write total size in header
+	err := e.writeLEAt(4, uint32(e.WrittenBytes)-8)
+	if err != nil {
+		return fmt.Errorf("%w when writing the total written bytes", err)
+	}
+
+	// rewrite the audio chunk length header
+	if e.pcmChunkSizePos > 0 {
+		err = e.writeLEAt(e.pcmChunkSizePos, e.dataChunkSize(e.frames))
+		if err != nil {
+			return fmt.Errorf("%w when writing wav data chunk size header", err)
+		}
+	}
+
+	// rewrite the fact sample count
+	if e.factCountPos > 0 {
+		err = e.writeLEAt(e.factCountPos, e.factSampleCount(e.frames))
+		if err != nil {
+			return fmt.Errorf("%w when writing the fact sample count", err)
+		}
+	}
+
+	// jump back to the end of the file.
+	_, err = e.w.Seek(0, io.SeekEnd)
+	if err != nil {
+		return fmt.Errorf("failed to seek to end of file: %w", err)
+	}
+
+	return nil
+}
+
+// writeLEAt writes value at the absolute position pos.
+func (e *Encoder) writeLEAt(pos int, value uint32) error {
+	_, err := e.w.Seek(int64(pos), io.SeekStart)
+	if err != nil {
+		return fmt.Errorf("failed to seek to position %d: %w", pos, err)
+	}
+
+	err = binary.Write(e.w, binary.LittleEndian, value)
+	if err != nil {
+		return fmt.Errorf("failed to write little endian: %w", err)
+	}
+
+	return nil
+}
+
 // Close flushes the content to disk, make sure the headers are up to date
-// Note that the underlying writer is NOT being closed.
+// Note that the underlying writer is NOT being closed. Writes after Close
+// return ErrEncoderClosed and calling Close again has no effect.
 func (e *Encoder) Close() error {
-	if e == nil || e.w == nil {
+	if e == nil || e.w == nil || e.closed {
 		return nil
 	}
 
+	defer func() { e.closed = true }()
+
 	if !e.wroteHeader && (e.Metadata != nil || len(e.UnknownChunks) > 0) {
 		err := e.writeHeader()
 		if err != nil {
@@ -636,6 +1375,11 @@ func (e *Encoder) Close() error {
 		}
 	}
 
+	// an encoder that never wrote anything leaves the writer untouched
+	if !e.wroteHeader {
+		return nil
+	}
+
 	if !e.wroteUnknownPre {
 		err := e.writeUnknownChunks(true)
 		if err != nil {
@@ -645,6 +1389,16 @@ func (e *Encoder) Close() error {
 		e.wroteUnknownPre = true
 	}
 
+	err := e.writeDataPad()
+	if err != nil {
+		return err
+	}
+
+	err = e.writeChecksumChunk()
+	if err != nil {
+		return fmt.Errorf("failed to write checksum chunk: %w", err)
+	}
+
 	if !e.wroteUnknownPost {
 		err := e.writeUnknownChunks(false)
 		if err != nil {
@@ -656,45 +1410,26 @@ func (e *Encoder) Close() error {
 
 	// inject metadata at the end to not trip implementation not supporting
 	// metadata chunks
-	if e.Metadata != nil {
+	if !e.wroteMetadata {
+		e.applySoftwareTag()
+	}
+
+	if e.Metadata != nil && !e.wroteMetadata {
 		err := e.writeMetadata()
 		if err != nil {
 			return fmt.Errorf("failed to write metadata - %w", err)
 		}
 	}
 
-	// go back and write total size in header
-	_, err := e.w.Seek(4, 0)
-	if err != nil {
-		return fmt.Errorf("failed to seek to file size position: %w", err)
-	}
-
-	err = e.AddLE(uint32(e.WrittenBytes) - 8)
-	if err != nil {
-		return fmt.Errorf("%w when writing the total written bytes", err)
-	}
-
-	// rewrite the audio chunk length header
-	if e.pcmChunkSizePos > 0 {
-		_, err = e.w.Seek(int64(e.pcmChunkSizePos), 0)
-		if err != nil {
-			return fmt.Errorf("failed to seek to PCM chunk size position: %w", err)
-		}
-
-		chunksize := uint32((e.BitDepth / 8) * e.NumChans * e.frames)
-
-		err = e.AddLE(chunksize)
+	// sizes written up front for ExpectedFrames only need patching when the
+	// file turned out differently
+	if e.ExpectedFrames <= 0 || e.frames != e.ExpectedFrames || e.WrittenBytes-8 != e.riffSize {
+		err = e.patchSizes()
 		if err != nil {
-			return fmt.Errorf("%w when writing wav data chunk size header", err)
+			return err
 		}
 	}
 
-	// jump back to the end of the file.
-	_, err = e.w.Seek(0, 2)
-	if err != nil {
-		return fmt.Errorf("failed to seek to end of file: %w", err)
-	}
-
 	if f, ok := e.w.(*os.File); ok {
 		err := f.Sync()
 		if err != nil {
@@ -702,5 +1437,9 @@ func (e *Encoder) Close() error {
 		}
 	}
 
+	if e.ExpectedFrames > 0 && e.frames != e.ExpectedFrames {
+		return fmt.Errorf("%w: wrote %d of %d frames", ErrFrameCountMismatch, e.frames, e.ExpectedFrames)
+	}
+
 	return nil
 }
@@ -1,8 +1,13 @@
 package wav
 
 import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 
@@ -149,6 +154,423 @@ func TestEncoderWriteFrameErrors(t *testing.T) {
 	})
 }
 
+func TestEncoderWriteInterleavedFrame(t *testing.T) {
+	outPath := filepath.Join(t.TempDir(), "interleaved_frame.wav")
+
+	out, err := os.Create(outPath)
+	if err != nil {
+		t.Fatalf("create output: %v", err)
+	}
+
+	enc := NewEncoder(out, 48000, 16, 2, wavFormatPCM)
+
+	err = enc.WriteInterleavedFrame([]float32{0.5})
+	if err == nil || !strings.Contains(err.Error(), "doesn't match channel count") {
+		t.Fatalf("expected channel mismatch error, got %v", err)
+	}
+
+	frames := [][]float32{{0.5, -0.5}, {0.25, -0.25}, {0, 0}}
+	for _, frame := range frames {
+		err := enc.WriteInterleavedFrame(frame)
+		if err != nil {
+			t.Fatalf("WriteInterleavedFrame failed: %v", err)
+		}
+	}
+
+	if enc.frames != len(frames) {
+		t.Fatalf("frame count mismatch: got %d want %d", enc.frames, len(frames))
+	}
+
+	if err := enc.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	if err := out.Close(); err != nil {
+		t.Fatalf("close file: %v", err)
+	}
+
+	in, err := os.Open(outPath)
+	if err != nil {
+		t.Fatalf("open encoded file: %v", err)
+	}
+	defer in.Close()
+
+	dec := NewDecoder(in)
+
+	buf, err := dec.FullPCMBuffer()
+	if err != nil {
+		t.Fatalf("decode PCM buffer: %v", err)
+	}
+
+	if dec.PCMLen() != int64(len(frames)*2*2) {
+		t.Fatalf("data chunk size mismatch: got %d want %d", dec.PCMLen(), len(frames)*2*2)
+	}
+
+	assertFloat32SlicesClose(t, buf.Data, []float32{0.5, -0.5, 0.25, -0.25, 0, 0}, 1e-4)
+}
+
+func TestEncoderWriteComplexFrame(t *testing.T) {
+	err := NewMemoryEncoder(48000, 32, 1, wavFormatIEEEFloat).WriteComplexFrame(complex(0.5, -0.5))
+	if !errors.Is(err, errFrameChannelMismatch) {
+		t.Fatalf("expected a channel mismatch error for mono, got %v", err)
+	}
+
+	enc := NewMemoryEncoder(48000, 32, 2, wavFormatIEEEFloat)
+
+	for _, c := range []complex64{complex(0.5, -0.25), complex(-1, 0.125)} {
+		err := enc.WriteComplexFrame(c)
+		if err != nil {
+			t.Fatalf("WriteComplexFrame failed: %v", err)
+		}
+	}
+
+	data, err := enc.Close()
+	if err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	buf, err := NewDecoder(bytes.NewReader(data)).FullPCMBuffer()
+	if err != nil {
+		t.Fatalf("decode PCM buffer: %v", err)
+	}
+
+	assertFloat32SlicesClose(t, buf.Data, []float32{0.5, -0.25, -1, 0.125}, 0)
+}
+
+func TestEncoderWriteFrames(t *testing.T) {
+	enc := NewMemoryEncoder(48000, 16, 2, wavFormatPCM)
+
+	err := enc.WriteFrames([][]float32{{0.5, -0.5}, {0.25}})
+	if !errors.Is(err, errFrameChannelMismatch) {
+		t.Fatalf("expected channel mismatch error, got %v", err)
+	}
+
+	if enc.frames != 0 {
+		t.Fatalf("expected nothing to be written, got %d frames", enc.frames)
+	}
+
+	frames := [][]float32{{0.5, -0.5}, {0.25, -0.25}, {0, 0}}
+
+	err = enc.WriteFrames(frames)
+	if err != nil {
+		t.Fatalf("WriteFrames failed: %v", err)
+	}
+
+	err = enc.WriteFrames(frames[:1])
+	if err != nil {
+		t.Fatalf("WriteFrames failed: %v", err)
+	}
+
+	if enc.frames != len(frames)+1 {
+		t.Fatalf("frame count mismatch: got %d want %d", enc.frames, len(frames)+1)
+	}
+
+	data, err := enc.Close()
+	if err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	buf, err := NewDecoder(bytes.NewReader(data)).FullPCMBuffer()
+	if err != nil {
+		t.Fatalf("decode PCM buffer: %v", err)
+	}
+
+	assertFloat32SlicesClose(t, buf.Data, []float32{0.5, -0.5, 0.25, -0.25, 0, 0, 0.5, -0.5}, 1e-4)
+}
+
+// appendOnlyWriter fails all seeks, like a pipe.
+type appendOnlyWriter struct {
+	bytes.Buffer
+}
+
+func (w *appendOnlyWriter) Seek(int64, int) (int64, error) {
+	return 0, errors.New("seek not supported")
+}
+
+func TestEncoderExpectedFrames(t *testing.T) {
+	frames := [][]float32{{0.5, -0.5}, {0.25, -0.25}, {0, 0}}
+
+	out := &appendOnlyWriter{}
+	enc := NewEncoder(out, 48000, 16, 2, wavFormatPCM)
+	enc.ExpectedFrames = len(frames)
+	enc.WriteFactChunk = true
+	enc.WriteChecksum = true
+	enc.Metadata = &Metadata{Title: "expected", CuePoints: []*CuePoint{{ID: [4]byte{1}, Position: 1, Label: "one"}}}
+
+	err := enc.WriteFrames(frames)
+	if err != nil {
+		t.Fatalf("WriteFrames failed: %v", err)
+	}
+
+	err = enc.Close()
+	if err != nil {
+		t.Fatalf("Close failed without seeking: %v", err)
+	}
+
+	dec := NewDecoder(bytes.NewReader(out.Bytes()))
+
+	ok, err := dec.VerifyChecksum()
+	if err != nil || !ok {
+		t.Fatalf("expected a matching checksum, got %v, %v", ok, err)
+	}
+
+	dec.ReadMetadata()
+
+	if dec.Metadata == nil || dec.Metadata.Title != "expected" || dec.CompressedSamples != uint32(len(frames)) {
+		t.Fatalf("unexpected metadata %+v, fact samples %d", dec.Metadata, dec.CompressedSamples)
+	}
+
+	if riffSize := binary.LittleEndian.Uint32(out.Bytes()[4:8]); int(riffSize) != out.Len()-8 {
+		t.Fatalf("RIFF size %d doesn't match the %d byte file", riffSize, out.Len())
+	}
+
+	err = dec.Rewind()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf, err := dec.FullPCMBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertFloat32SlicesClose(t, buf.Data, []float32{0.5, -0.5, 0.25, -0.25, 0, 0}, 1e-4)
+
+	// fewer frames than expected are patched and reported
+	mem := NewMemoryEncoder(48000, 16, 2, wavFormatPCM)
+	mem.ExpectedFrames = 5
+
+	err = mem.WriteFrames(frames)
+	if err != nil {
+		t.Fatalf("WriteFrames failed: %v", err)
+	}
+
+	_, err = mem.Close()
+	if !errors.Is(err, ErrFrameCountMismatch) {
+		t.Fatalf("expected ErrFrameCountMismatch, got %v", err)
+	}
+
+	buf, err = NewDecoder(bytes.NewReader(mem.Bytes())).FullPCMBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if buf.NumFrames() != len(frames) {
+		t.Fatalf("expected the data size to be patched to %d frames, got %d", len(frames), buf.NumFrames())
+	}
+}
+
+func TestEncoderOddDataChunkPad(t *testing.T) {
+	frames := [][]float32{{0.5}, {-0.5}, {0.25}}
+
+	// 8-bit mono with an odd frame count makes an odd sized data chunk,
+	// with ExpectedFrames set the sizes are written up front and the append
+	// only writer rules out patching
+	testCases := []struct {
+		name       string
+		newEncoder func() (*Encoder, func() []byte)
+	}{
+		{
+			name: "patched sizes",
+			newEncoder: func() (*Encoder, func() []byte) {
+				mem := NewMemoryEncoder(8000, 8, 1, wavFormatPCM)
+
+				return mem.Encoder, mem.Bytes
+			},
+		},
+		{
+			name: "expected frames",
+			newEncoder: func() (*Encoder, func() []byte) {
+				out := &appendOnlyWriter{}
+				enc := NewEncoder(out, 8000, 8, 1, wavFormatPCM)
+				enc.ExpectedFrames = len(frames)
+
+				return enc, out.Bytes
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			enc, written := testCase.newEncoder()
+			enc.WriteChecksum = true
+			enc.Metadata = &Metadata{Title: "odd"}
+
+			err := enc.WriteFrames(frames)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = enc.Close()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			data := written()
+
+			if riffSize := binary.LittleEndian.Uint32(data[4:8]); int(riffSize) != len(data)-8 {
+				t.Fatalf("RIFF size %d doesn't match the %d byte file", riffSize, len(data))
+			}
+
+			pos := bytes.Index(data, []byte("data"))
+			if pos < 0 {
+				t.Fatal("data chunk not found")
+			}
+
+			if size := binary.LittleEndian.Uint32(data[pos+4:]); size != uint32(len(frames)) {
+				t.Fatalf("expected a declared data size of %d bytes without the pad byte, got %d", len(frames), size)
+			}
+
+			end := pos + 8 + len(frames)
+			if data[end] != 0 || !bytes.Equal(data[end+1:end+5], CIDCsum[:]) {
+				t.Fatalf("expected a zero pad byte before the next chunk, got % x", data[end:end+5])
+			}
+
+			dec := NewDecoder(bytes.NewReader(data))
+
+			ok, err := dec.VerifyChecksum()
+			if err != nil || !ok {
+				t.Fatalf("expected a matching checksum, got %v, %v", ok, err)
+			}
+
+			err = dec.Rewind()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			buf, metadata, err := dec.ReadAll()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assertFloat32SlicesClose(t, buf.Data, []float32{0.5, -0.5, 0.25}, 1.0/64)
+
+			if metadata == nil || metadata.Title != "odd" {
+				t.Fatalf("expected the metadata after the pad byte, got %+v", metadata)
+			}
+		})
+	}
+}
+
+func TestEncoderWriteSilence(t *testing.T) {
+	testCases := []struct {
+		name        string
+		bitDepth    int
+		audioFormat int
+		wantByte    byte
+	}{
+		{name: "pcm8", bitDepth: 8, audioFormat: wavFormatPCM, wantByte: 0x80},
+		{name: "pcm16", bitDepth: 16, audioFormat: wavFormatPCM, wantByte: 0x00},
+		{name: "pcm24", bitDepth: 24, audioFormat: wavFormatPCM, wantByte: 0x00},
+		{name: "float32", bitDepth: 32, audioFormat: wavFormatIEEEFloat, wantByte: 0x00},
+		{name: "alaw", bitDepth: 8, audioFormat: wavFormatALaw, wantByte: 0xD5},
+		{name: "mulaw", bitDepth: 8, audioFormat: wavFormatMuLaw, wantByte: 0xFF},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			outPath := filepath.Join(t.TempDir(), "silence.wav")
+
+			out, err := os.Create(outPath)
+			if err != nil {
+				t.Fatalf("create output: %v", err)
+			}
+
+			enc := NewEncoder(out, 8000, testCase.bitDepth, 2, testCase.audioFormat)
+
+			err = enc.WriteSilence(10)
+			if err != nil {
+				t.Fatalf("WriteSilence failed: %v", err)
+			}
+
+			if err := enc.Close(); err != nil {
+				t.Fatalf("Close failed: %v", err)
+			}
+
+			if err := out.Close(); err != nil {
+				t.Fatalf("close file: %v", err)
+			}
+
+			chunks, err := parseWavChunksFromFile(outPath)
+			if err != nil {
+				t.Fatalf("parse output: %v", err)
+			}
+
+			data, _ := findChunk(chunks, "data")
+			if data == nil {
+				t.Fatal("missing data chunk")
+			}
+
+			wantLen := 10 * 2 * testCase.bitDepth / 8
+			if len(data.data) != wantLen {
+				t.Fatalf("data size mismatch: got %d want %d", len(data.data), wantLen)
+			}
+
+			for i, b := range data.data {
+				if b != testCase.wantByte {
+					t.Fatalf("byte %d mismatch: got 0x%X want 0x%X", i, b, testCase.wantByte)
+				}
+			}
+
+			in, err := os.Open(outPath)
+			if err != nil {
+				t.Fatalf("open encoded file: %v", err)
+			}
+			defer in.Close()
+
+			buf, err := NewDecoder(in).FullPCMBuffer()
+			if err != nil {
+				t.Fatalf("decode PCM buffer: %v", err)
+			}
+
+			for i, v := range buf.Data {
+				if !float32ApproxEqual(v, 0, 1e-2) {
+					t.Fatalf("sample %d not silent: %f", i, v)
+				}
+			}
+		})
+	}
+}
+
+func TestEncoderRejectsInvalidFormatBeforeHeader(t *testing.T) {
+	testCases := []struct {
+		name        string
+		numChans    int
+		bitDepth    int
+		audioFormat int
+		wantErr     error
+	}{
+		{name: "zero channels", numChans: 0, bitDepth: 16, audioFormat: wavFormatPCM, wantErr: errInvalidChannelCount},
+		{name: "pcm 12-bit", numChans: 1, bitDepth: 12, audioFormat: wavFormatPCM, wantErr: errUnsupportedFrameBitSize},
+		{name: "float 16-bit", numChans: 1, bitDepth: 16, audioFormat: wavFormatIEEEFloat, wantErr: errEncUnsupportedFloatBitDepth},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			enc := NewEncoder(nopWriteSeeker{&buf}, 44100, testCase.bitDepth, testCase.numChans, testCase.audioFormat)
+
+			err := enc.Write(&audio.Float32Buffer{
+				Data:   []float32{0},
+				Format: &audio.Format{NumChannels: 1, SampleRate: 44100},
+			})
+			if !errors.Is(err, testCase.wantErr) {
+				t.Fatalf("expected %v, got %v", testCase.wantErr, err)
+			}
+
+			err = enc.WriteFrame(float32(0))
+			if !errors.Is(err, testCase.wantErr) {
+				t.Fatalf("WriteFrame: expected %v, got %v", testCase.wantErr, err)
+			}
+
+			if buf.Len() != 0 {
+				t.Fatalf("expected nothing written, got %d bytes", buf.Len())
+			}
+		})
+	}
+}
+
 func TestEncoderWriteNilBuffer(t *testing.T) {
 	out, err := os.Create(filepath.Join(t.TempDir(), "nil.wav"))
 	if err != nil {
@@ -354,3 +776,618 @@ func TestEncoderWriteG711RoundTrip(t *testing.T) {
 		})
 	}
 }
+
+func TestEncoderFmtAvgBytesPerSec(t *testing.T) {
+	gsmBlock := make([]byte, gsmBlockSize)
+
+	testCases := []struct {
+		name        string
+		sampleRate  int
+		bitDepth    int
+		audioFormat int
+		fmtChunk    *FmtChunk
+		// frame is written with WriteFrame, GSM blocks are passed through
+		frame    any
+		expected uint32
+	}{
+		{
+			name:       "pcm computed",
+			sampleRate: 44100, bitDepth: 16, audioFormat: wavFormatPCM,
+			frame:    float32(0),
+			expected: 44100 * 2,
+		},
+		{
+			name:       "gsm default",
+			sampleRate: 8000, audioFormat: wavFormatGSM610,
+			frame:    gsmBlock,
+			expected: 1625,
+		},
+		{
+			name:       "explicit override",
+			sampleRate: 8000, audioFormat: wavFormatGSM610,
+			fmtChunk: &FmtChunk{FormatTag: wavFormatGSM610, AvgBytesPerSec: 1650},
+			frame:    gsmBlock,
+			expected: 1650,
+		},
+		{
+			name:       "stale value for another rate",
+			sampleRate: 48000, bitDepth: 16, audioFormat: wavFormatPCM,
+			fmtChunk: &FmtChunk{FormatTag: wavFormatPCM, SampleRate: 44100, AvgBytesPerSec: 88200},
+			frame:    float32(0),
+			expected: 96000,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			enc := NewMemoryEncoder(testCase.sampleRate, testCase.bitDepth, 1, testCase.audioFormat)
+			enc.FmtChunk = testCase.fmtChunk
+
+			err := enc.WriteFrame(testCase.frame)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			data, err := enc.Close()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			fmtChunk, err := NewDecoder(bytes.NewReader(data)).PeekFormat()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if fmtChunk.FormatTag != uint16(testCase.audioFormat) || fmtChunk.AvgBytesPerSec != testCase.expected {
+				t.Fatalf("format %d at %d bytes/sec, want format %d at %d bytes/sec",
+					fmtChunk.FormatTag, fmtChunk.AvgBytesPerSec, testCase.audioFormat, testCase.expected)
+			}
+		})
+	}
+}
+
+func TestConvertPCMIntBitDepth(t *testing.T) {
+	testCases := []struct {
+		name    string
+		value   int
+		srcBits int
+		dstBits int
+		want    int
+	}{
+		{"16 to 24", 1000, 16, 24, 256000},
+		{"16 to 24 negative", -32768, 16, 24, -8388608},
+		{"8 to 16", 127, 8, 16, 32512},
+		{"24 to 16", 256000 + 255, 24, 16, 1000},
+		{"24 to 16 negative", -8388608, 24, 16, -32768},
+		{"32 to 16", 1 << 30, 32, 16, 1 << 14},
+		{"same depth", 12345, 16, 16, 12345},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			got := convertPCMIntBitDepth(testCase.value, testCase.srcBits, testCase.dstBits, false)
+			if got != testCase.want {
+				t.Fatalf("got %d, want %d", got, testCase.want)
+			}
+		})
+	}
+
+	// dither must stay within one output LSB and the output range
+	for range 1000 {
+		got := convertPCMIntBitDepth(256000, 24, 16, true)
+		if got < 999 || got > 1001 {
+			t.Fatalf("dithered value out of range: %d", got)
+		}
+
+		got = convertPCMIntBitDepth(8388607, 24, 16, true)
+		if got > 32767 {
+			t.Fatalf("dithered value not clamped: %d", got)
+		}
+	}
+}
+
+func TestEncoderWriteIntBufferConverting(t *testing.T) {
+	testCases := []struct {
+		name     string
+		srcBits  int
+		dstBits  int
+		data     []int
+		expected []float32
+	}{
+		{
+			name:     "upscale 16 to 24",
+			srcBits:  16,
+			dstBits:  24,
+			data:     []int{0, 16384, -16384, -32768},
+			expected: []float32{0, 0.5, -0.5, -1},
+		},
+		{
+			name:     "downscale 24 to 16",
+			srcBits:  24,
+			dstBits:  16,
+			data:     []int{0, 4194304 + 100, -4194304, 8388607},
+			expected: []float32{0, 0.5, -0.5, 32767.0 / 32768},
+		},
+		{
+			name:     "unsigned 8-bit source",
+			srcBits:  8,
+			dstBits:  16,
+			data:     []int{128, 192, 64, 0},
+			expected: []float32{0, 0.5, -0.5, -1},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			enc := NewMemoryEncoder(8000, testCase.dstBits, 1, wavFormatPCM)
+
+			err := enc.WriteIntBufferConverting(&audio.IntBuffer{
+				Data:           testCase.data,
+				Format:         &audio.Format{NumChannels: 1, SampleRate: 8000},
+				SourceBitDepth: testCase.srcBits,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			data, err := enc.Close()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			dec := NewDecoder(bytes.NewReader(data))
+
+			buf, err := dec.FullPCMBuffer()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if int(dec.BitDepth) != testCase.dstBits {
+				t.Fatalf("expected %d-bit output, got %d", testCase.dstBits, dec.BitDepth)
+			}
+
+			assertFloat32SlicesClose(t, buf.Data, testCase.expected, 1e-6)
+		})
+	}
+}
+
+func TestDecoderPCMIntBuffer(t *testing.T) {
+	testCases := []struct {
+		name string
+		bits int
+		data []int
+	}{
+		{name: "unsigned 8-bit", bits: 8, data: []int{128, 255, 0, 129}},
+		{name: "24-bit", bits: 24, data: []int{8388607, -8388608, 1, -1}},
+		{name: "32-bit", bits: 32, data: []int{0x7FFFFF01, 0x7FFFFF02, -0x7FFFFF03, -0x80000000}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			enc := NewMemoryEncoder(8000, testCase.bits, 2, wavFormatPCM)
+
+			err := enc.WriteIntBufferConverting(&audio.IntBuffer{
+				Data:           testCase.data,
+				Format:         &audio.Format{NumChannels: 2, SampleRate: 8000},
+				SourceBitDepth: testCase.bits,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			data, err := enc.Close()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			dec := NewDecoder(bytes.NewReader(data))
+
+			var got []int
+
+			buf := &audio.IntBuffer{Data: make([]int, 3)}
+			for {
+				n, err := dec.PCMIntBuffer(buf)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if n == 0 {
+					break
+				}
+
+				got = append(got, buf.Data[:n]...)
+			}
+
+			if buf.SourceBitDepth != testCase.bits || buf.Format.NumChannels != 2 {
+				t.Fatalf("unexpected buffer format: %d bits, %d channels", buf.SourceBitDepth, buf.Format.NumChannels)
+			}
+
+			if !slices.Equal(got, testCase.data) {
+				t.Fatalf("expected %v, got %v", testCase.data, got)
+			}
+		})
+	}
+
+	enc := NewMemoryEncoder(8000, 32, 1, wavFormatIEEEFloat)
+
+	err := enc.WriteSilence(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := enc.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = NewDecoder(bytes.NewReader(data)).PCMIntBuffer(&audio.IntBuffer{Data: make([]int, 2)})
+	if !errors.Is(err, errIntBufferFormat) {
+		t.Fatalf("expected errIntBufferFormat, got %v", err)
+	}
+}
+
+func TestDecoderPCMIntBufferInt24In32(t *testing.T) {
+	data, err := os.ReadFile("fixtures/24in32-stereo.wav")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chunks, err := parseWavChunks(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dataChunk, _ := findChunk(chunks, "data")
+	if dataChunk == nil {
+		t.Fatal("fixture has no data chunk")
+	}
+
+	dec := NewDecoder(bytes.NewReader(data))
+	buf := &audio.IntBuffer{Data: make([]int, 64)}
+
+	n, err := dec.PCMIntBuffer(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n != min(len(buf.Data), len(dataChunk.data)/4) || buf.SourceBitDepth != 24 {
+		t.Fatalf("unexpected result: %d samples at %d bits", n, buf.SourceBitDepth)
+	}
+
+	// the 24 valid bits sit above one padding byte
+	for i := range n {
+		want := int(int32(binary.LittleEndian.Uint32(dataChunk.data[4*i:])) >> 8)
+		if buf.Data[i] != want {
+			t.Fatalf("sample[%d]=%d, want %d", i, buf.Data[i], want)
+		}
+	}
+}
+
+func TestFloat32BufferToIntBuffer(t *testing.T) {
+	format := &audio.Format{NumChannels: 1, SampleRate: 48000}
+	in := &audio.Float32Buffer{Format: format, Data: []float32{-1.5, 0, 0.5, 1.5}}
+
+	got := Float32BufferToIntBuffer(in, 16)
+	if got.SourceBitDepth != 16 || got.Format != format {
+		t.Fatalf("unexpected buffer: %d bits, format %v", got.SourceBitDepth, got.Format)
+	}
+
+	if want := []int{-32768, 0, 16384, 32767}; !slices.Equal(got.Data, want) {
+		t.Fatalf("expected %v, got %v", want, got.Data)
+	}
+
+	if got := Float32BufferToIntBuffer(in, 8).Data; !slices.Equal(got, []int{0, 128, 191, 255}) {
+		t.Fatalf("expected unsigned 8-bit samples, got %v", got)
+	}
+
+	// 24-bit samples survive the float round trip
+	samples := []int{8388607, -8388608, 1, -1, 1234567}
+	floats := &audio.Float32Buffer{Data: make([]float32, len(samples))}
+
+	for i, v := range samples {
+		floats.Data[i] = normalizePCMInt(v, 24)
+	}
+
+	if got := Float32BufferToIntBuffer(floats, 24).Data; !slices.Equal(got, samples) {
+		t.Fatalf("expected %v, got %v", samples, got)
+	}
+
+	if Float32BufferToIntBuffer(in, 64) != nil {
+		t.Fatal("expected nil for an unsupported bit depth")
+	}
+}
+
+func TestEncoderWriteInt24Buffer(t *testing.T) {
+	enc := NewMemoryEncoder(48000, 24, 2, wavFormatPCM)
+
+	err := enc.WriteInt24Buffer([]int32{0, 1, -1, 0x123456, 8388607, -8388608, 9000000, -9000000, 42})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := enc.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chunks, err := parseWavChunks(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dataChunk, _ := findChunk(chunks, "data")
+	if dataChunk == nil {
+		t.Fatal("missing data chunk")
+	}
+
+	// the trailing partial frame is dropped, out of range values are clamped.
+	want := []byte{
+		0x00, 0x00, 0x00, 0x01, 0x00, 0x00,
+		0xff, 0xff, 0xff, 0x56, 0x34, 0x12,
+		0xff, 0xff, 0x7f, 0x00, 0x00, 0x80,
+		0xff, 0xff, 0x7f, 0x00, 0x00, 0x80,
+	}
+	if !bytes.Equal(dataChunk.data, want) {
+		t.Fatalf("data mismatch:\n got % x\nwant % x", dataChunk.data, want)
+	}
+
+	dec := NewDecoder(bytes.NewReader(data))
+
+	buf, err := dec.FullPCMBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if buf.NumFrames() != 4 {
+		t.Fatalf("expected 4 frames, got %d", buf.NumFrames())
+	}
+
+	err = NewMemoryEncoder(48000, 16, 1, wavFormatPCM).WriteInt24Buffer([]int32{0})
+	if !errors.Is(err, errInt24BufferFormat) {
+		t.Fatalf("expected errInt24BufferFormat for 16-bit output, got %v", err)
+	}
+}
+
+func TestEncoderCheckpoint(t *testing.T) {
+	format := &audio.Format{NumChannels: 1, SampleRate: 8000}
+	first := &audio.Float32Buffer{Data: []float32{0.1, 0.2, 0.3, 0.4}, Format: format}
+	second := &audio.Float32Buffer{Data: []float32{-0.1, -0.2}, Format: format}
+
+	enc := NewMemoryEncoder(8000, 16, 1, wavFormatPCM)
+	enc.WriteFactChunk = true
+
+	err := enc.Write(first)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = enc.Checkpoint()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// simulate a crash: the bytes written so far must form a valid file
+	snapshot := append([]byte(nil), enc.Bytes()...)
+
+	dec := NewDecoder(bytes.NewReader(snapshot))
+
+	buf, err := dec.FullPCMBuffer()
+	if err != nil {
+		t.Fatalf("decode checkpointed file: %v", err)
+	}
+
+	if len(buf.Data) != len(first.Data) {
+		t.Fatalf("expected %d samples after checkpoint, got %d", len(first.Data), len(buf.Data))
+	}
+
+	if dec.CompressedSamples != uint32(len(first.Data)) {
+		t.Fatalf("expected fact count %d, got %d", len(first.Data), dec.CompressedSamples)
+	}
+
+	err = enc.Write(second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	withCheckpoint, err := enc.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plain := NewMemoryEncoder(8000, 16, 1, wavFormatPCM)
+	plain.WriteFactChunk = true
+
+	for _, b := range []*audio.Float32Buffer{first, second} {
+		err = plain.Write(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	withoutCheckpoint, err := plain.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(withCheckpoint, withoutCheckpoint) {
+		t.Fatal("checkpointing changed the final file")
+	}
+}
+
+func TestEncoderInvertAndSwapChannels(t *testing.T) {
+	encode := func(invert []int, swap [][2]int) ([]float32, error) {
+		enc := NewMemoryEncoder(8000, 16, 3, wavFormatPCM)
+		enc.InvertChannels = invert
+		enc.SwapChannels = swap
+
+		err := enc.Write(&audio.Float32Buffer{
+			Format: &audio.Format{NumChannels: 3, SampleRate: 8000},
+			Data:   []float32{0.5, 0.25, -0.125, 0.5, 0.25, -0.125},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		err = enc.WriteInterleavedFrame([]float32{0.5, 0.25, -0.125})
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := enc.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		buf, err := NewDecoder(bytes.NewReader(data)).FullPCMBuffer()
+		if err != nil {
+			return nil, err
+		}
+
+		return buf.Data, nil
+	}
+
+	testCases := []struct {
+		name   string
+		invert []int
+		swap   [][2]int
+		frame  []float32
+	}{
+		{"none", nil, nil, []float32{0.5, 0.25, -0.125}},
+		{"invert", []int{0, 2}, nil, []float32{-0.5, 0.25, 0.125}},
+		{"swap", nil, [][2]int{{0, 1}}, []float32{0.25, 0.5, -0.125}},
+		{"invert then swap", []int{0}, [][2]int{{0, 2}}, []float32{-0.125, 0.25, -0.5}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			got, err := encode(testCase.invert, testCase.swap)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			want := append(append(append([]float32(nil), testCase.frame...), testCase.frame...), testCase.frame...)
+			assertFloat32SlicesClose(t, got, want, 0)
+		})
+	}
+
+	_, err := encode([]int{3}, nil)
+	if !errors.Is(err, errInvalidChannelIndex) {
+		t.Fatalf("expected errInvalidChannelIndex for inversion, got %v", err)
+	}
+
+	_, err = encode(nil, [][2]int{{-1, 0}})
+	if !errors.Is(err, errInvalidChannelIndex) {
+		t.Fatalf("expected errInvalidChannelIndex for swapping, got %v", err)
+	}
+}
+
+func TestEncoderWriteFloat64Buffer(t *testing.T) {
+	samples := []float64{0.1234567890123, -0.987654321, 1.5, -0.5}
+
+	encode := func(bitDepth, format int) []byte {
+		t.Helper()
+
+		enc := NewMemoryEncoder(48000, bitDepth, 2, format)
+
+		err := enc.WriteFloat64Buffer(samples, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		data, err := enc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		chunks, err := parseWavChunks(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		payload, _ := findChunk(chunks, "data")
+		if payload == nil {
+			t.Fatal("no data chunk written")
+		}
+
+		return payload.data
+	}
+
+	// 64-bit float passes samples through, clamped to [-1, 1]
+	payload := encode(64, wavFormatIEEEFloat)
+	for i, want := range []float64{0.1234567890123, -0.987654321, 1, -0.5} {
+		got := math.Float64frombits(binary.LittleEndian.Uint64(payload[i*8:]))
+		if got != want {
+			t.Fatalf("sample %d: got %v, want %v", i, got, want)
+		}
+	}
+
+	// 32-bit PCM is quantized from the float64 value
+	payload = encode(32, wavFormatPCM)
+
+	got := int32(binary.LittleEndian.Uint32(payload))
+	if want := int32(math.Round(samples[0] * scalePCMInt32)); got != want {
+		t.Fatalf("expected %d, got %d", want, got)
+	}
+
+	if narrowed := float32ToPCMInt32(float32(samples[0]), 32); got == narrowed {
+		t.Fatal("expected the float64 quantization to differ from the float32 one")
+	}
+
+	err := NewMemoryEncoder(48000, 16, 2, wavFormatPCM).WriteFloat64Buffer(samples, 0)
+	if !errors.Is(err, errInvalidChannelCount) {
+		t.Fatalf("expected errInvalidChannelCount, got %v", err)
+	}
+}
+
+func TestEncoder_WriteAfterClose(t *testing.T) {
+	for _, used := range []bool{true, false} {
+		enc := NewMemoryEncoder(8000, 16, 1, wavFormatPCM)
+
+		if used {
+			err := enc.WriteSilence(4)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		data, err := enc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		closed := bytes.Clone(data)
+		buf := &audio.Float32Buffer{
+			Format: &audio.Format{NumChannels: 1, SampleRate: 8000},
+			Data:   []float32{0.5},
+		}
+
+		writes := map[string]func() error{
+			"Write":                 func() error { return enc.Write(buf) },
+			"WriteFrame":            func() error { return enc.WriteFrame(float32(0.5)) },
+			"WriteInterleavedFrame": func() error { return enc.WriteInterleavedFrame([]float32{0.5}) },
+			"WriteFrames":           func() error { return enc.WriteFrames([][]float32{{0.5}}) },
+			"WritePlanar":           func() error { return enc.WritePlanar([][]float32{{0.5}}) },
+			"WriteSilence":          func() error { return enc.WriteSilence(1) },
+			"WriteFloat64Buffer":    func() error { return enc.WriteFloat64Buffer([]float64{0.5}, 1) },
+			"WriteIntBuffer": func() error {
+				return enc.WriteIntBufferConverting(&audio.IntBuffer{Format: buf.Format, Data: []int{1}, SourceBitDepth: 16})
+			},
+			"Checkpoint": enc.Checkpoint,
+		}
+
+		for name, write := range writes {
+			if err := write(); !errors.Is(err, ErrEncoderClosed) {
+				t.Fatalf("used=%v %s: expected ErrEncoderClosed, got %v", used, name, err)
+			}
+		}
+
+		again, err := enc.Close()
+		if err != nil {
+			t.Fatalf("expected a second Close to be a no-op, got %v", err)
+		}
+
+		if !bytes.Equal(again, closed) {
+			t.Fatalf("used=%v: expected the writer to stay unchanged, got %d bytes instead of %d", used, len(again), len(closed))
+		}
+	}
+}
@@ -2,6 +2,8 @@ package wav
 
 import (
 	"bytes"
+	"encoding/binary"
+	"errors"
 	"fmt"
 	"os"
 	"path"
@@ -39,6 +41,7 @@ func TestEncoderRoundTrip(t *testing.T) {
 				Product: "go-audio", Subject: "wav codec",
 				Software: "go-audio codec", Source: "Audacity generator",
 				Location: "Los Angeles", TrackNbr: "42",
+				SMPTETimecode: "01:02:03:04", DigitizationTime: "Wed Jan 02 02:03:55 1990",
 			}, "1 ch,  44100 Hz, 8-bit unsigned integer",
 		},
 		{"fixtures/32bit.wav", "testOutput/32bit.wav", nil, "1 ch, 44100 Hz, 32-bit little-endian signed integer"},
@@ -174,6 +177,14 @@ func TestEncoderRoundTrip(t *testing.T) {
 				if testCase.metadata.TrackNbr != decoder.Metadata.TrackNbr {
 					t.Errorf("expected TrackNbr to be %s, but was %s", testCase.metadata.TrackNbr, decoder.Metadata.TrackNbr)
 				}
+
+				if testCase.metadata.SMPTETimecode != decoder.Metadata.SMPTETimecode {
+					t.Errorf("expected SMPTETimecode to be %s, but was %s", testCase.metadata.SMPTETimecode, decoder.Metadata.SMPTETimecode)
+				}
+
+				if testCase.metadata.DigitizationTime != decoder.Metadata.DigitizationTime {
+					t.Errorf("expected DigitizationTime to be %s, but was %s", testCase.metadata.DigitizationTime, decoder.Metadata.DigitizationTime)
+				}
 			}
 
 			newFile.Close()
@@ -408,6 +419,19 @@ func TestEncoder_Close_NilWriter(t *testing.T) {
 	}
 }
 
+func TestEncoder_Close_Unused(t *testing.T) {
+	for _, bitDepth := range []int{16, 13} {
+		encoded, err := NewMemoryEncoder(8000, bitDepth, 1, wavFormatPCM).Close()
+		if err != nil {
+			t.Fatalf("%d bit: Close on an unused encoder should return nil, got %v", bitDepth, err)
+		}
+
+		if len(encoded) != 0 {
+			t.Fatalf("%d bit: expected nothing to be written, got %d bytes", bitDepth, len(encoded))
+		}
+	}
+}
+
 func TestEncoder_AddBuffer_Nil(t *testing.T) {
 	var buf bytes.Buffer
 
@@ -488,3 +512,227 @@ func (n nopWriteSeeker) Write(p []byte) (int, error) {
 func (n nopWriteSeeker) Seek(offset int64, whence int) (int64, error) {
 	return 0, nil
 }
+
+func TestMemoryEncoder(t *testing.T) {
+	enc := NewMemoryEncoder(44100, 16, 2, wavFormatPCM)
+
+	buf := &audio.Float32Buffer{
+		Data:           []float32{0.5, -0.5, 0.25, -0.25, 0, 0},
+		Format:         &audio.Format{NumChannels: 2, SampleRate: 44100},
+		SourceBitDepth: 16,
+	}
+
+	err := enc.Write(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := enc.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dec := NewDecoder(bytes.NewReader(data))
+
+	pcm, err := dec.FullPCMBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if dec.SampleRate != 44100 || dec.NumChans != 2 || dec.BitDepth != 16 {
+		t.Fatalf("unexpected format: %d Hz, %d channels, %d bits", dec.SampleRate, dec.NumChans, dec.BitDepth)
+	}
+
+	if len(pcm.Data) != len(buf.Data) {
+		t.Fatalf("expected %d samples, got %d", len(buf.Data), len(pcm.Data))
+	}
+
+	if dec.PCMSize != len(buf.Data)*2 {
+		t.Fatalf("expected data chunk size %d, got %d", len(buf.Data)*2, dec.PCMSize)
+	}
+}
+
+func TestWriteWAV(t *testing.T) {
+	out := &seekBuffer{}
+	buf := &audio.Float32Buffer{
+		Data:   []float32{0.5, -0.5, 0.25, -0.25},
+		Format: &audio.Format{NumChannels: 2, SampleRate: 22050},
+	}
+
+	err := WriteWAV(out, buf, 24, wavFormatPCM, &Metadata{Artist: "artist", Title: "title"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dec := NewDecoder(bytes.NewReader(out.data))
+
+	pcm, err := dec.FullPCMBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if dec.SampleRate != 22050 || dec.NumChans != 2 || dec.BitDepth != 24 {
+		t.Fatalf("unexpected format: %d Hz, %d channels, %d bits", dec.SampleRate, dec.NumChans, dec.BitDepth)
+	}
+
+	assertFloat32SlicesClose(t, pcm.Data, buf.Data, 1e-6)
+
+	dec.ReadMetadata()
+
+	if dec.Metadata == nil || dec.Metadata.Artist != "artist" || dec.Metadata.Title != "title" {
+		t.Fatalf("metadata not written: %+v", dec.Metadata)
+	}
+
+	err = WriteWAV(&seekBuffer{}, nil, 16, wavFormatPCM, nil)
+	if !errors.Is(err, errNilBuffer) {
+		t.Fatalf("expected errNilBuffer, got %v", err)
+	}
+}
+
+func TestEncodeInt16(t *testing.T) {
+	out := &seekBuffer{}
+	samples := []int16{0, 1000, -1000, 32767, -32768, 16384}
+
+	err := EncodeInt16(out, samples, 44100, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dec := NewDecoder(bytes.NewReader(out.data))
+	dec.ReadInfo()
+
+	if dec.SampleRate != 44100 || dec.NumChans != 2 || dec.BitDepth != 16 {
+		t.Fatalf("unexpected format: %d Hz, %d channels, %d bits", dec.SampleRate, dec.NumChans, dec.BitDepth)
+	}
+
+	chunks, err := parseWavChunks(out.data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dataChunk, _ := findChunk(chunks, "data")
+	if dataChunk == nil || len(dataChunk.data) != len(samples)*2 {
+		t.Fatalf("unexpected data chunk %+v", dataChunk)
+	}
+
+	for i, want := range samples {
+		if got := int16(binary.LittleEndian.Uint16(dataChunk.data[i*2:])); got != want {
+			t.Fatalf("sample %d: got %d, want %d", i, got, want)
+		}
+	}
+
+	err = EncodeInt16(&seekBuffer{}, samples[:5], 44100, 2)
+	if !errors.Is(err, errFrameChannelMismatch) {
+		t.Fatalf("expected errFrameChannelMismatch, got %v", err)
+	}
+
+	err = EncodeInt16(&seekBuffer{}, samples, 44100, 0)
+	if !errors.Is(err, errInvalidChannelCount) {
+		t.Fatalf("expected errInvalidChannelCount, got %v", err)
+	}
+}
+
+func TestEncoder_Signed8(t *testing.T) {
+	samples := []float32{0, 1, -1, 0.5}
+
+	for _, signed := range []bool{false, true} {
+		enc := NewMemoryEncoder(8000, 8, 1, wavFormatPCM)
+		enc.Signed8 = signed
+
+		err := enc.Write(&audio.Float32Buffer{
+			Data:   samples,
+			Format: &audio.Format{NumChannels: 1, SampleRate: 8000},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		data, err := enc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		chunks, err := parseWavChunks(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		dataChunk, _ := findChunk(chunks, "data")
+		if dataChunk == nil {
+			t.Fatal("missing data chunk")
+		}
+
+		for i, val := range samples {
+			want := float32ToPCMUint8(val)
+			if signed {
+				want = uint8(float32ToPCMInt8(val))
+			}
+
+			if dataChunk.data[i] != want {
+				t.Fatalf("signed=%t sample %d: got %#x, want %#x", signed, i, dataChunk.data[i], want)
+			}
+		}
+
+		if signed && dataChunk.data[0] != 0 {
+			t.Fatalf("expected signed silence to be 0, got %#x", dataChunk.data[0])
+		}
+	}
+}
+
+func TestEncoder_FramesWritten(t *testing.T) {
+	var nilEncoder *Encoder
+	if nilEncoder.FramesWritten() != 0 || nilEncoder.DataBytesWritten() != 0 {
+		t.Fatal("expected nothing written by a nil encoder")
+	}
+
+	enc := NewMemoryEncoder(8000, 24, 1, wavFormatPCM)
+	enc.Metadata = &Metadata{Title: "progress"}
+
+	if enc.FramesWritten() != 0 || enc.DataBytesWritten() != 0 {
+		t.Fatalf("expected nothing written, got %d frames and %d bytes", enc.FramesWritten(), enc.DataBytesWritten())
+	}
+
+	for i := 1; i <= 3; i++ {
+		err := enc.WriteSilence(5)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if enc.FramesWritten() != 5*i || enc.DataBytesWritten() != 15*i {
+			t.Fatalf("expected %d frames and %d bytes, got %d frames and %d bytes", 5*i, 15*i, enc.FramesWritten(), enc.DataBytesWritten())
+		}
+	}
+
+	data, err := enc.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the header, the pad byte and the metadata are container overhead
+	if enc.WrittenBytes != len(data) || enc.DataBytesWritten() != 45 {
+		t.Fatalf("expected 45 audio bytes of %d written, got %d of %d", len(data), enc.DataBytesWritten(), enc.WrittenBytes)
+	}
+
+	dec := NewDecoder(bytes.NewReader(data))
+
+	err = dec.FwdToPCM()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if dec.PCMLen() != int64(enc.DataBytesWritten()) {
+		t.Fatalf("expected a %d byte data chunk, got %d", enc.DataBytesWritten(), dec.PCMLen())
+	}
+
+	// a float64 frame quantized to integer PCM counts once
+	enc = NewMemoryEncoder(8000, 16, 1, wavFormatPCM)
+
+	err = enc.WriteFrame(0.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if enc.FramesWritten() != 1 || enc.DataBytesWritten() != 2 {
+		t.Fatalf("expected 1 frame and 2 bytes, got %d frames and %d bytes", enc.FramesWritten(), enc.DataBytesWritten())
+	}
+}
@@ -0,0 +1,48 @@
+package wav
+
+// dataChunkSize returns the data chunk size holding the given frames. For
+// formats the encoder doesn't encode it is the number of bytes passed
+// through.
+func (e *Encoder) dataChunkSize(frames int) uint32 {
+	if !e.encodesFormat() {
+		return uint32(e.rawBytes)
+	}
+
+	return uint32((e.BitDepth / 8) * e.NumChans * frames)
+}
+
+// predictRIFFSize returns the RIFF size of the finished file for
+// ExpectedFrames. The chunks surrounding the samples are laid out on an
+// in-memory copy of the encoder in the order Close writes them.
+func (e *Encoder) predictRIFFSize() (int, error) {
+	dry := *e
+	dry.w = &seekBuffer{}
+	dry.wroteHeader = false
+	dry.ExpectedFrames = 0
+
+	err := dry.startPCMChunk()
+	if err != nil {
+		return 0, err
+	}
+
+	err = dry.writeChecksumChunk()
+	if err != nil {
+		return 0, err
+	}
+
+	err = dry.writeUnknownChunks(false)
+	if err != nil {
+		return 0, err
+	}
+
+	if !dry.wroteMetadata {
+		err = dry.writeMetadata()
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	dataSize := int(e.dataChunkSize(e.ExpectedFrames))
+
+	return dry.WrittenBytes + dataSize + dataSize%2 - 8, nil
+}
@@ -66,6 +66,22 @@ func (f *FmtChunk) EffectiveFormatTag() uint16 {
 	return f.FormatTag
 }
 
+// containerBits returns the number of bits each sample occupies in the data
+// chunk. 24-bit PCM is sometimes stored left-justified in 4-byte containers
+// with BitsPerSample still saying 24; BlockAlign gives those away.
+func (f *FmtChunk) containerBits() int {
+	if f == nil {
+		return 0
+	}
+
+	if f.BitsPerSample == 24 && f.NumChannels > 0 && f.EffectiveFormatTag() == wavFormatPCM &&
+		int(f.BlockAlign) == 4*int(f.NumChannels) {
+		return 32
+	}
+
+	return int(f.BitsPerSample)
+}
+
 func makeSubFormatGUID(formatTag uint16) [16]byte {
 	var guid [16]byte
 	binary.LittleEndian.PutUint32(guid[:4], uint32(formatTag))
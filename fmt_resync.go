@@ -0,0 +1,53 @@
+package wav
+
+import "io"
+
+// maxFmtSlack is the largest number of undeclared bytes searched for after
+// the fmt chunk.
+const maxFmtSlack = 8
+
+// fmtChunkSlack detects fmt chunks whose declared size is smaller than the
+// payload actually written, e.g. a size of 16 followed by a two byte cbSize.
+// Called at the declared end of the fmt chunk, it peeks at the following
+// bytes and returns how far the next plausible chunk header is ahead of the
+// current position. It returns 0 when the next chunk header is in place or
+// can't be found. The read position is left unchanged.
+func (d *Decoder) fmtChunkSlack() int64 {
+	pos, err := d.r.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return 0
+	}
+
+	var peek [4 + maxFmtSlack]byte
+
+	n, _ := io.ReadFull(d.r, peek[:])
+
+	_, err = d.r.Seek(pos, io.SeekStart)
+	if err != nil {
+		return 0
+	}
+
+	if n < 4 || isPlausibleChunkID(peek[:4]) {
+		return 0
+	}
+
+	for offset := 1; offset+4 <= n; offset++ {
+		if isPlausibleChunkID(peek[offset : offset+4]) {
+			return int64(offset)
+		}
+	}
+
+	return 0
+}
+
+// isPlausibleChunkID reports whether id consists of printable ASCII, as
+// all registered chunk IDs do.
+func isPlausibleChunkID(id []byte) bool {
+	for _, c := range id {
+		if c < 0x20 || c > 0x7e {
+			return false
+		}
+	}
+
+	return true
+}
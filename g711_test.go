@@ -1,6 +1,12 @@
 package wav
 
-import "testing"
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/go-audio/audio"
+)
 
 func TestSearchSegment(t *testing.T) {
 	tests := []struct {
@@ -102,3 +108,91 @@ func TestEncodeALawClip(t *testing.T) {
 		t.Fatal("max and min should produce different encoded values")
 	}
 }
+
+func TestEncoder_G711Layout(t *testing.T) {
+	testCases := []struct {
+		name        string
+		audioFormat int
+		numChans    int
+		encode      func(int16) byte
+	}{
+		{"alaw mono", wavFormatALaw, 1, encodeALawSample},
+		{"alaw stereo", wavFormatALaw, 2, encodeALawSample},
+		{"mulaw mono", wavFormatMuLaw, 1, encodeMuLawSample},
+		{"mulaw stereo", wavFormatMuLaw, 2, encodeMuLawSample},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			const frames = 6
+
+			// channel 1 is the negated channel 0, so swapped channels show
+			data := make([]float32, 0, frames*tc.numChans)
+			for i := range frames {
+				val := float32(i+1) / 8
+				data = append(data, val)
+
+				if tc.numChans == 2 {
+					data = append(data, -val)
+				}
+			}
+
+			enc := NewMemoryEncoder(8000, 8, tc.numChans, tc.audioFormat)
+
+			err := enc.Write(&audio.Float32Buffer{
+				Format: &audio.Format{NumChannels: tc.numChans, SampleRate: 8000},
+				Data:   data,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			out, err := enc.Close()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			chunks, err := parseWavChunks(out)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			fmtChunk, _ := findChunk(chunks, "fmt ")
+			factChunk, _ := findChunk(chunks, "fact")
+			dataChunk, _ := findChunk(chunks, "data")
+
+			if fmtChunk == nil || factChunk == nil || dataChunk == nil {
+				t.Fatal("expected fmt, fact and data chunks")
+			}
+
+			if got := binary.LittleEndian.Uint32(fmtChunk.data[8:12]); got != uint32(8000*tc.numChans) {
+				t.Fatalf("expected AvgBytesPerSec %d, got %d", 8000*tc.numChans, got)
+			}
+
+			if got := binary.LittleEndian.Uint16(fmtChunk.data[12:14]); got != uint16(tc.numChans) {
+				t.Fatalf("expected BlockAlign %d, got %d", tc.numChans, got)
+			}
+
+			if got := binary.LittleEndian.Uint32(factChunk.data); got != frames {
+				t.Fatalf("expected a fact sample count of %d, got %d", frames, got)
+			}
+
+			want := make([]byte, len(data))
+			for i, val := range data {
+				want[i] = tc.encode(int16(float32ToPCMInt32(val, 16)))
+			}
+
+			if !bytes.Equal(dataChunk.data, want) {
+				t.Fatalf("unexpected interleaved data % x, want % x", dataChunk.data, want)
+			}
+
+			buf, err := NewDecoder(bytes.NewReader(out)).FullPCMBuffer()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// G.711 keeps about 12 bits near these levels
+			assertFloat32SlicesClose(t, buf.Data, data, 1.0/64)
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package wav
+
+import "math"
+
+// SetGainDB sets a gain in dB that PCMBuffer and FullPCMBuffer apply to the
+// decoded samples, uniformly for all formats. The gain is applied before
+// ClampFloatOutput, which then limits samples of every format to [-1, 1].
+// Negative infinity mutes the output and 0 disables the gain.
+func (d *Decoder) SetGainDB(db float64) {
+	if d == nil {
+		return
+	}
+
+	d.gainSet = db != 0
+	d.gain = float32(math.Pow(10, db/decibelScale))
+
+	if math.IsInf(db, -1) {
+		d.gain = 0
+	}
+}
+
+// clampDecodedFloat reports whether the sample decoders should clamp float
+// samples themselves, which they don't when the clamp follows the gain.
+func (d *Decoder) clampDecodedFloat() bool {
+	return d.ClampFloatOutput && !d.gainSet
+}
+
+// finishDecoded runs the steps every decode path applies to freshly decoded
+// samples: clip counting and the gain.
+func (d *Decoder) finishDecoded(samples []float32) {
+	d.countClips(samples)
+	d.applyGain(samples)
+}
+
+// applyGain scales the decoded samples by the gain set with SetGainDB and
+// clamps them if ClampFloatOutput is set.
+func (d *Decoder) applyGain(samples []float32) {
+	if !d.gainSet {
+		return
+	}
+
+	for i, val := range samples {
+		val *= d.gain
+
+		if d.ClampFloatOutput {
+			val = clampFloat32(val, -1, 1)
+		}
+
+		samples[i] = val
+	}
+}
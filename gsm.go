@@ -17,6 +17,10 @@ const (
 )
 
 var (
+	// ErrTruncatedGSMData is reported as a decoder warning when the GSM data
+	// holds fewer samples than the fact chunk announces.
+	ErrTruncatedGSMData = errors.New("GSM data shorter than fact sample count")
+
 	errGSMBlockTooShort  = errors.New("GSM block too short")
 	errShortGSMBlockRead = errors.New("short GSM block read")
 )
@@ -148,6 +152,8 @@ type gsmDecoder struct {
 	leftoverPos int
 	delivered   int
 	factSamples int
+
+	checkedFactSamples bool
 }
 
 func newGSMDecoder(factSamples int) *gsmDecoder {
@@ -583,8 +589,9 @@ func (g *gsmDecoder) decodeBlock(block []byte) ([gsmSamplesPerBlock]int16, error
 	return out, nil
 }
 
-// decodeAllBlocks reads all GSM blocks and returns float32 samples.
-func (g *gsmDecoder) decodeAllBlocks(reader io.Reader, factSamples int) ([]float32, error) {
+// decodeAllBlocks reads all GSM blocks and returns float32 samples. It stops
+// after the block that passes maxSamples, unless that is negative.
+func (g *gsmDecoder) decodeAllBlocks(reader io.Reader, factSamples, maxSamples int) ([]float32, error) {
 	var allSamples []float32
 
 	block := make([]byte, gsmBlockSize)
@@ -603,6 +610,12 @@ func (g *gsmDecoder) decodeAllBlocks(reader io.Reader, factSamples int) ([]float
 			return nil, validationErr
 		}
 
+		// a trailing partial block, such as the pad byte of an odd sized
+		// data chunk, can't be decoded. PCMBuffer skips it as well.
+		if bytesRead < gsmBlockSize {
+			break
+		}
+
 		samples, decErr := g.decodeBlock(block)
 		if decErr != nil {
 			return nil, decErr
@@ -610,6 +623,10 @@ func (g *gsmDecoder) decodeAllBlocks(reader io.Reader, factSamples int) ([]float
 
 		g.appendNormalizedSamples(&allSamples, samples)
 
+		if maxSamples >= 0 && len(allSamples) > maxSamples {
+			break
+		}
+
 		if err != nil {
 			lastErr = err
 			break
@@ -645,6 +662,16 @@ func (g *gsmDecoder) appendNormalizedSamples(allSamples *[]float32, samples [gsm
 	}
 }
 
+// checkGSMFactSamples returns a warning when fewer samples were decoded than
+// the fact chunk announced, which indicates a damaged recording.
+func checkGSMFactSamples(decoded, factSamples int) error {
+	if factSamples > 0 && decoded < factSamples {
+		return fmt.Errorf("%w: decoded %d of %d samples", ErrTruncatedGSMData, decoded, factSamples)
+	}
+
+	return nil
+}
+
 func (g *gsmDecoder) trimToFactSamples(samples []float32, factSamples int) []float32 {
 	if factSamples > 0 && len(samples) > factSamples {
 		return samples[:factSamples]
@@ -1,9 +1,12 @@
 package wav
 
 import (
+	"bytes"
+	"encoding/binary"
 	"errors"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"testing"
 
 	"github.com/go-audio/audio"
@@ -182,6 +185,79 @@ func TestGSMFullPCMBuffer(t *testing.T) {
 	}
 }
 
+func TestGSMEncoderRoundTrip(t *testing.T) {
+	data, err := os.ReadFile("fixtures/addf8-GSM-GW.wav")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dec := NewDecoder(bytes.NewReader(data))
+
+	err = dec.FwdToPCM()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var blocks bytes.Buffer
+
+	_, err = dec.CopyPCMTo(&blocks)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	outPath := filepath.Join(t.TempDir(), "gsm.wav")
+
+	file, err := os.Create(outPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	// the GSM blocks are passed through unchanged, one per frame
+	enc := NewEncoderFromDecoder(file, dec)
+
+	raw := blocks.Bytes()
+	for len(raw) > 0 {
+		n := min(gsmBlockSize, len(raw))
+
+		err = enc.WriteFrame(raw[:n])
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		raw = raw[n:]
+	}
+
+	err = enc.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want, err := NewDecoder(bytes.NewReader(data)).FullPCMBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := NewDecoder(bytes.NewReader(out))
+
+	buf, err := got.FullPCMBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got.WavAudioFormat != wavFormatGSM610 || got.CompressedSamples != dec.CompressedSamples || got.PCMLen() != int64(blocks.Len()) {
+		t.Fatalf("expected GSM with %d samples in %d bytes, got format %d with %d samples in %d bytes",
+			dec.CompressedSamples, blocks.Len(), got.WavAudioFormat, got.CompressedSamples, got.PCMLen())
+	}
+
+	assertFloat32SlicesClose(t, buf.Data, want.Data, 0)
+}
+
 func TestGSMPCMBuffer(t *testing.T) {
 	file, err := os.Open("fixtures/addf8-GSM-GW.wav")
 	if err != nil {
@@ -225,6 +301,99 @@ func TestGSMPCMBuffer(t *testing.T) {
 	}
 }
 
+func TestGSMTruncatedDataWarning(t *testing.T) {
+	chunks, err := parseWavChunksFromFile("fixtures/addf8-GSM-GW.wav")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var b bytes.Buffer
+	b.WriteString("RIFF")
+	b.Write(make([]byte, 4))
+	b.WriteString("WAVE")
+
+	for _, ch := range chunks {
+		payload := ch.data
+		if ch.id == "data" {
+			payload = payload[:10*gsmBlockSize]
+		}
+
+		writeTestChunk(t, &b, ch.id, payload)
+	}
+
+	truncated := b.Bytes()
+	binary.LittleEndian.PutUint32(truncated[4:8], uint32(len(truncated)-8))
+
+	t.Run("full", func(t *testing.T) {
+		dec := NewDecoder(bytes.NewReader(truncated))
+
+		_, err := dec.FullPCMBuffer()
+		if err != nil {
+			t.Fatalf("FullPCMBuffer failed: %v", err)
+		}
+
+		assertGSMTruncationWarning(t, dec)
+	})
+
+	t.Run("streaming", func(t *testing.T) {
+		dec := NewDecoder(bytes.NewReader(truncated))
+		buf := &audio.Float32Buffer{Data: make([]float32, 255)}
+
+		for {
+			numRead, err := dec.PCMBuffer(buf)
+			if err != nil {
+				t.Fatalf("PCMBuffer failed: %v", err)
+			}
+
+			if numRead == 0 {
+				break
+			}
+		}
+
+		assertGSMTruncationWarning(t, dec)
+	})
+
+	t.Run("intact", func(t *testing.T) {
+		file, err := os.Open("fixtures/addf8-GSM-GW.wav")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer file.Close()
+
+		dec := NewDecoder(file)
+
+		_, err = dec.FullPCMBuffer()
+		if err != nil {
+			t.Fatalf("FullPCMBuffer failed: %v", err)
+		}
+
+		if warnings := dec.Warnings(); len(warnings) != 0 {
+			t.Fatalf("expected no warnings, got %v", warnings)
+		}
+
+		if dec.GSMDecodedSamples() != int(dec.CompressedSamples) {
+			t.Fatalf("decoded %d samples, fact says %d", dec.GSMDecodedSamples(), dec.CompressedSamples)
+		}
+	})
+}
+
+func assertGSMTruncationWarning(t *testing.T, dec *Decoder) {
+	t.Helper()
+
+	if dec.GSMDecodedSamples() != 10*gsmSamplesPerBlock {
+		t.Fatalf("decoded sample count mismatch: got %d want %d", dec.GSMDecodedSamples(), 10*gsmSamplesPerBlock)
+	}
+
+	warnings := dec.Warnings()
+	if len(warnings) != 1 || !errors.Is(warnings[0], ErrTruncatedGSMData) {
+		t.Fatalf("expected a single truncation warning, got %v", warnings)
+	}
+
+	if dec.Err() != nil {
+		t.Fatalf("truncation should not be fatal, got %v", dec.Err())
+	}
+}
+
 func TestGSMFullPCMBuffer_PCMBuffer_Parity(t *testing.T) {
 	// Decode with FullPCMBuffer.
 	file1, err := os.Open("fixtures/addf8-GSM-GW.wav")
@@ -618,3 +787,44 @@ func findFFmpegReference(t *testing.T) string {
 
 	return outPath
 }
+
+func TestGSMSetFactSampleCount(t *testing.T) {
+	file, err := os.Open("fixtures/addf8-GSM-GW.wav")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	dec := NewDecoder(file)
+	dec.SetFactSampleCount(0)
+
+	buf, err := dec.FullPCMBuffer()
+	if err != nil {
+		t.Fatalf("FullPCMBuffer failed: %v", err)
+	}
+
+	allBlocks := dec.PCMSize / gsmBlockSize * gsmSamplesPerBlock
+	if len(buf.Data) != allBlocks {
+		t.Fatalf("expected all %d block samples without a fact count, got %d", allBlocks, len(buf.Data))
+	}
+
+	if !dec.HadFactChunk || dec.CompressedSamples != 0 {
+		t.Fatalf("expected the fact chunk to be overridden, got %d", dec.CompressedSamples)
+	}
+
+	dec.SetFactSampleCount(1000)
+
+	err = dec.Rewind()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf, err = dec.FullPCMBuffer()
+	if err != nil {
+		t.Fatalf("FullPCMBuffer failed: %v", err)
+	}
+
+	if len(buf.Data) != 1000 {
+		t.Fatalf("expected the override to trim to 1000 samples, got %d", len(buf.Data))
+	}
+}
@@ -0,0 +1,281 @@
+package wav
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+
+	"github.com/go-audio/audio"
+)
+
+var (
+	errInvalidSourceBitDepth = errors.New("invalid source bit depth")
+	errInt24BufferFormat     = errors.New("24-bit integer buffers need a 24-bit PCM encoder")
+	errIntBufferFormat       = errors.New("integer buffers can only be decoded from integer PCM")
+)
+
+// WriteIntBufferConverting encodes an integer buffer whose samples are
+// stored at buf.SourceBitDepth. For integer PCM output the samples are
+// rescaled to the encoder bit depth by shifting: upscaling is lossless,
+// downscaling drops the low bits, optionally with dither (see
+// DitherIntBuffers). 8-bit sources are unsigned, as stored in WAV files.
+// Other output formats go through the float path.
+func (e *Encoder) WriteIntBufferConverting(buf *audio.IntBuffer) error {
+	if buf == nil {
+		return errNilBuffer
+	}
+
+	srcBits := buf.SourceBitDepth
+	if srcBits < 8 || srcBits > 32 {
+		return fmt.Errorf("%w: %d", errInvalidSourceBitDepth, srcBits)
+	}
+
+	numChans := e.NumChans
+	if buf.Format != nil && buf.Format.NumChannels > 0 {
+		numChans = buf.Format.NumChannels
+	}
+
+	if numChans != e.NumChans {
+		return fmt.Errorf("%w: got %d channels for %d", errFrameChannelMismatch, numChans, e.NumChans)
+	}
+
+	if e.effectiveAudioFormat() != wavFormatPCM {
+		floatBuf := &audio.Float32Buffer{
+			Data:           make([]float32, len(buf.Data)),
+			Format:         &audio.Format{NumChannels: numChans, SampleRate: e.SampleRate},
+			SourceBitDepth: srcBits,
+		}
+		for i, v := range buf.Data {
+			floatBuf.Data[i] = normalizePCMIntBits(signedPCMInt(v, srcBits), srcBits)
+		}
+
+		return e.Write(floatBuf)
+	}
+
+	err := e.startPCMChunk()
+	if err != nil {
+		return err
+	}
+
+	frameCount := e.limitFrames(len(buf.Data) / numChans)
+	for i := range frameCount * numChans {
+		value := convertPCMIntBitDepth(signedPCMInt(buf.Data[i], srcBits), srcBits, e.BitDepth, e.DitherIntBuffers)
+
+		switch e.BitDepth {
+		case 8:
+			if e.Signed8 {
+				err = e.buf.WriteByte(uint8(int8(value)))
+			} else {
+				err = e.buf.WriteByte(uint8(value + 128))
+			}
+		case 16:
+			err = binary.Write(e.buf, binary.LittleEndian, int16(value))
+		case 24:
+			_, err = e.buf.Write(audio.Int32toInt24LEBytes(int32(value)))
+		case 32:
+			err = binary.Write(e.buf, binary.LittleEndian, int32(value))
+		default:
+			return fmt.Errorf("%w: %d", errUnsupportedFrameBitSize, e.BitDepth)
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to write %d-bit sample: %w", e.BitDepth, err)
+		}
+	}
+
+	e.frames += frameCount
+
+	n, err := e.dataWriter().Write(e.buf.Bytes())
+	if err != nil {
+		e.WrittenBytes += n
+		return fmt.Errorf("failed to write buffer: %w", err)
+	}
+
+	e.WrittenBytes += e.buf.Len()
+	e.buf.Reset()
+
+	return e.maxFramesErr()
+}
+
+// WriteInt24Buffer encodes interleaved signed 24-bit samples held in int32
+// values straight to 3-byte little-endian PCM, skipping the float conversion
+// of Write. Values outside the 24-bit range are clamped. The encoder must be
+// set up for 24-bit integer PCM. A trailing partial frame is ignored.
+func (e *Encoder) WriteInt24Buffer(samples []int32) error {
+	if samples == nil {
+		return errNilBuffer
+	}
+
+	if e.BitDepth != 24 || e.effectiveAudioFormat() != wavFormatPCM {
+		return fmt.Errorf("%w: %d-bit format %d", errInt24BufferFormat, e.BitDepth, e.effectiveAudioFormat())
+	}
+
+	err := e.startPCMChunk()
+	if err != nil {
+		return err
+	}
+
+	frameCount := e.limitFrames(len(samples) / e.NumChans)
+
+	e.buf.Grow(frameCount * e.NumChans * 3)
+
+	var packed [3]byte
+
+	for _, value := range samples[:frameCount*e.NumChans] {
+		value = max(min(value, maxPCMInt24), -maxPCMInt24-1)
+		packed[0], packed[1], packed[2] = byte(value), byte(value>>8), byte(value>>16)
+		e.buf.Write(packed[:])
+	}
+
+	e.frames += frameCount
+
+	n, err := e.dataWriter().Write(e.buf.Bytes())
+	if err != nil {
+		e.WrittenBytes += n
+		return fmt.Errorf("failed to write buffer: %w", err)
+	}
+
+	e.WrittenBytes += e.buf.Len()
+	e.buf.Reset()
+
+	return e.maxFramesErr()
+}
+
+// Float32BufferToIntBuffer quantizes buf to integer samples at bitDepth like
+// the encoder does: values are clamped to [-1, 1], 8-bit samples are unsigned
+// as stored in WAV files and the others signed. Samples decoded from integer
+// PCM of up to 24 bits convert back to their original values; 32-bit samples
+// don't fit a float32, use Decoder.PCMIntBuffer to read them losslessly. Bit
+// depths outside 8 to 32 return nil.
+func Float32BufferToIntBuffer(buf *audio.Float32Buffer, bitDepth int) *audio.IntBuffer {
+	if buf == nil || bitDepth < 8 || bitDepth > 32 {
+		return nil
+	}
+
+	enc := SampleEncoding{BitDepth: bitDepth, ValidBits: bitDepth, Signed: bitDepth > 8}
+	out := &audio.IntBuffer{
+		Format:         buf.Format,
+		Data:           make([]int, len(buf.Data)),
+		SourceBitDepth: bitDepth,
+	}
+
+	for i, v := range buf.Data {
+		out.Data[i] = enc.Quantize(v)
+	}
+
+	return out
+}
+
+// PCMIntBuffer decodes up to len(buf.Data) samples from the current position
+// like PCMBuffer, but keeps the integer values stored in the data chunk, so
+// no precision is lost to a float conversion. 8-bit samples are unsigned,
+// the others signed; 24-bit samples in 4-byte containers are shifted down to
+// 24 bits. buf.SourceBitDepth is set to the bit depth of the values. Gain and
+// clip counting don't apply. Only integer PCM data can be decoded this way.
+func (d *Decoder) PCMIntBuffer(buf *audio.IntBuffer) (n int, err error) {
+	if buf == nil {
+		return 0, nil
+	}
+
+	if !d.pcmDataAccessed {
+		err := d.FwdToPCM()
+		if err != nil {
+			return 0, d.err
+		}
+	}
+
+	if d.PCMChunk == nil {
+		return 0, ErrPCMChunkNotFound
+	}
+
+	if d.WavAudioFormat != wavFormatPCM {
+		return 0, fmt.Errorf("%w: format %d", errIntBufferFormat, d.WavAudioFormat)
+	}
+
+	container := d.containerBitDepth()
+
+	decodeInt, err := sampleDecodeFunc(container, d.byteOrder())
+	if err != nil {
+		return 0, fmt.Errorf("could not get sample decode func %w", err)
+	}
+
+	bPerSample := bytesPerSample(container)
+	bits, shift := bPerSample*8, 0
+
+	if container > int(d.BitDepth) {
+		bits = int(d.BitDepth)
+		shift = bPerSample*8 - bits
+	}
+
+	buf.Format = &audio.Format{
+		NumChannels: int(d.NumChans),
+		SampleRate:  int(d.SampleRate),
+	}
+	buf.SourceBitDepth = bits
+
+	raw := make([]byte, len(buf.Data)*bPerSample)
+
+	read, err := io.ReadFull(&progressReader{d: d, r: d.PCMChunk.R}, raw)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return 0, fmt.Errorf("failed to read PCM data: %w", err)
+	}
+
+	bufR := bytes.NewReader(raw[:read-read%bPerSample])
+	sampleBuf := make([]byte, bPerSample)
+
+	for n = 0; bufR.Len() > 0; n++ {
+		value, err := decodeInt(bufR, sampleBuf)
+		if err != nil {
+			return n, fmt.Errorf("failed to decode int sample: %w", err)
+		}
+
+		buf.Data[n] = value >> shift
+	}
+
+	if n == 0 {
+		d.pcmDone = true
+	}
+
+	return n, nil
+}
+
+// signedPCMInt converts an integer sample to a signed value, 8-bit samples
+// are stored unsigned.
+func signedPCMInt(value, bitDepth int) int {
+	if bitDepth == 8 {
+		return value - 128
+	}
+
+	return value
+}
+
+// convertPCMIntBitDepth rescales a signed sample from srcBits to dstBits. When
+// reducing the bit depth with dither, triangular noise of one output LSB is
+// added and the result is rounded, otherwise the low bits are dropped.
+func convertPCMIntBitDepth(value, srcBits, dstBits int, dither bool) int {
+	if srcBits == dstBits {
+		return value
+	}
+
+	if dstBits > srcBits {
+		return value << (dstBits - srcBits)
+	}
+
+	shift := srcBits - dstBits
+
+	if dither {
+		step := int64(1) << shift
+		noise := rand.Int64N(step) + rand.Int64N(step) - step
+		value += int(noise + step/2)
+	}
+
+	value >>= shift
+
+	maxVal := 1<<(dstBits-1) - 1
+	minVal := -(1 << (dstBits - 1))
+
+	return max(min(value, maxVal), minVal)
+}
@@ -29,6 +29,8 @@ var (
 	markerITCH    = [4]byte{'I', 'T', 'C', 'H'}
 	markerIKEY    = [4]byte{'I', 'K', 'E', 'Y'}
 	markerIMED    = [4]byte{'I', 'M', 'E', 'D'}
+	markerISMP    = [4]byte{'I', 'S', 'M', 'P'}
+	markerIDIT    = [4]byte{'I', 'D', 'I', 'T'}
 
 	errListNilChunk   = errors.New("can't decode a nil chunk")
 	errListNilDecoder = errors.New("nil decoder")
@@ -64,9 +66,13 @@ func DecodeListChunk(d *Decoder, ch *riff.Chunk) error {
 			return fmt.Errorf("failed to read the INFO subchunk - %w", err)
 		}
 
+		if bytes.Equal(scratch, []byte{'a', 'd', 't', 'l'}) {
+			ch.Drain()
+			return decodeAdtlList(d, reader)
+		}
+
 		if !bytes.Equal(scratch, CIDInfo) {
 			// "expected an INFO subchunk but got %s", string(scratch)
-			// TODO: support adtl subchunks
 			ch.Drain()
 			return nil
 		}
@@ -127,37 +133,41 @@ func DecodeListChunk(d *Decoder, ch *riff.Chunk) error {
 
 			switch id {
 			case markerIARL:
-				d.Metadata.Location = nullTermStr(scratch)
+				d.Metadata.Location = d.decodeText(scratch)
 			case markerIART:
-				d.Metadata.Artist = nullTermStr(scratch)
+				d.Metadata.Artist = d.decodeText(scratch)
 			case markerISFT:
-				d.Metadata.Software = nullTermStr(scratch)
+				d.Metadata.Software = d.decodeText(scratch)
 			case markerICRD:
-				d.Metadata.CreationDate = nullTermStr(scratch)
+				d.Metadata.CreationDate = d.decodeText(scratch)
 			case markerICOP:
-				d.Metadata.Copyright = nullTermStr(scratch)
+				d.Metadata.Copyright = d.decodeText(scratch)
 			case markerINAM:
-				d.Metadata.Title = nullTermStr(scratch)
+				d.Metadata.Title = d.decodeText(scratch)
 			case markerIENG:
-				d.Metadata.Engineer = nullTermStr(scratch)
+				d.Metadata.Engineer = d.decodeText(scratch)
 			case markerIGNR:
-				d.Metadata.Genre = nullTermStr(scratch)
+				d.Metadata.Genre = d.decodeText(scratch)
 			case markerIPRD:
-				d.Metadata.Product = nullTermStr(scratch)
+				d.Metadata.Product = d.decodeText(scratch)
 			case markerISRC:
-				d.Metadata.Source = nullTermStr(scratch)
+				d.Metadata.Source = d.decodeText(scratch)
 			case markerISBJ:
-				d.Metadata.Subject = nullTermStr(scratch)
+				d.Metadata.Subject = d.decodeText(scratch)
 			case markerICMT:
-				d.Metadata.Comments = nullTermStr(scratch)
+				d.Metadata.Comments = d.decodeText(scratch)
 			case markerITRK, markerITRKBug:
-				d.Metadata.TrackNbr = nullTermStr(scratch)
+				d.Metadata.TrackNbr = d.decodeText(scratch)
 			case markerITCH:
-				d.Metadata.Technician = nullTermStr(scratch)
+				d.Metadata.Technician = d.decodeText(scratch)
 			case markerIKEY:
-				d.Metadata.Keywords = nullTermStr(scratch)
+				d.Metadata.Keywords = d.decodeText(scratch)
 			case markerIMED:
-				d.Metadata.Medium = nullTermStr(scratch)
+				d.Metadata.Medium = d.decodeText(scratch)
+			case markerISMP:
+				d.Metadata.SMPTETimecode = d.decodeText(scratch)
+			case markerIDIT:
+				d.Metadata.DigitizationTime = d.decodeText(scratch)
 			}
 		}
 	}
@@ -205,6 +215,8 @@ func encodeInfoChunk(enc *Encoder) []byte {
 		{markerISRC, enc.Metadata.Source},
 		{markerIARL, enc.Metadata.Location},
 		{markerITRK, enc.Metadata.TrackNbr},
+		{markerISMP, enc.Metadata.SMPTETimecode},
+		{markerIDIT, enc.Metadata.DigitizationTime},
 	}
 
 	for _, field := range fields {
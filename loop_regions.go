@@ -0,0 +1,93 @@
+package wav
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrLoopOutOfRange is reported by LoopRegions for sampler loops that don't
+// fit in the data chunk.
+var ErrLoopOutOfRange = errors.New("loop out of range")
+
+// LoopRegion is a sampler loop resolved to frames of the data chunk.
+type LoopRegion struct {
+	// CuePointID links the loop to a cue point.
+	CuePointID [4]byte
+	// Label is the label of the linked cue point, if any.
+	Label string
+	// StartFrame is the first frame of the loop.
+	StartFrame int64
+	// EndFrame is the last frame played in the loop, inclusive as in the
+	// smpl chunk.
+	EndFrame int64
+	// Type is the smpl loop type: 0 forward, 1 alternating, 2 backward.
+	Type uint32
+	// PlayCount is the number of times the loop is played, 0 for an
+	// infinite sustain loop.
+	PlayCount uint32
+}
+
+// LoopRegions returns the sampler loops of the smpl chunk with their cue
+// labels, reading the metadata if needed. Loops reaching past the last frame
+// or ending before they start are left out and reported in the returned
+// error, one ErrLoopOutOfRange per loop. Like Stat, it leaves the decoder
+// reset to the start of the file.
+func (d *Decoder) LoopRegions() ([]LoopRegion, error) {
+	if d == nil || d.r == nil {
+		return nil, errNilDecoder
+	}
+
+	if d.Metadata == nil {
+		d.ReadMetadata()
+
+		err := d.Err()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	stat, err := d.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	if d.Metadata == nil || d.Metadata.SamplerInfo == nil {
+		return nil, nil
+	}
+
+	labels := make(map[[4]byte]string, len(d.Metadata.CuePoints))
+	for _, point := range d.Metadata.CuePoints {
+		if point != nil {
+			labels[point.ID] = point.Label
+		}
+	}
+
+	var (
+		regions []LoopRegion
+		errs    []error
+	)
+
+	for i, loop := range d.Metadata.SamplerInfo.Loops {
+		if loop == nil {
+			continue
+		}
+
+		start, end := int64(loop.Start), int64(loop.End)
+		if start > end || end >= stat.Frames {
+			errs = append(errs, fmt.Errorf("%w: loop %d spans frames %d to %d of %d", ErrLoopOutOfRange, i, start, end, stat.Frames))
+
+			continue
+		}
+
+		regions = append(regions, LoopRegion{
+			CuePointID: loop.CuePointID,
+			Label:      labels[loop.CuePointID],
+			StartFrame: start,
+			EndFrame:   end,
+			Type:       loop.Type,
+			PlayCount:  loop.PlayCount,
+		})
+	}
+
+	return regions, errors.Join(errs...)
+}
@@ -0,0 +1,99 @@
+package wav
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func encodeTestSmplChunk(loops ...SampleLoop) []byte {
+	payload := make([]byte, 36, 36+24*len(loops))
+	binary.LittleEndian.PutUint32(payload[28:32], uint32(len(loops)))
+
+	for _, loop := range loops {
+		entry := make([]byte, 24)
+		copy(entry[0:4], loop.CuePointID[:])
+		binary.LittleEndian.PutUint32(entry[4:8], loop.Type)
+		binary.LittleEndian.PutUint32(entry[8:12], loop.Start)
+		binary.LittleEndian.PutUint32(entry[12:16], loop.End)
+		binary.LittleEndian.PutUint32(entry[16:20], loop.Fraction)
+		binary.LittleEndian.PutUint32(entry[20:24], loop.PlayCount)
+		payload = append(payload, entry...)
+	}
+
+	return payload
+}
+
+func TestDecoder_LoopRegions(t *testing.T) {
+	enc := NewMemoryEncoder(8000, 16, 1, wavFormatPCM)
+	enc.Metadata = NewMetadataBuilder().Cue(2, "sustain").Cue(8, "tail").Build()
+	enc.UnknownChunks = []RawChunk{{
+		ID: CIDSmpl,
+		Data: encodeTestSmplChunk(
+			SampleLoop{CuePointID: [4]byte{1}, Start: 2, End: 5, PlayCount: 3},
+			SampleLoop{CuePointID: [4]byte{2}, Type: 1, Start: 8, End: 12},
+			SampleLoop{CuePointID: [4]byte{3}, Start: 6, End: 9},
+		),
+	}}
+
+	err := enc.WriteSilence(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := enc.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dec := NewDecoder(bytes.NewReader(data))
+
+	regions, err := dec.LoopRegions()
+	if !errors.Is(err, ErrLoopOutOfRange) {
+		t.Fatalf("expected an out of range loop error, got %v", err)
+	}
+
+	want := []LoopRegion{
+		{CuePointID: [4]byte{1}, Label: "sustain", StartFrame: 2, EndFrame: 5, PlayCount: 3},
+		{CuePointID: [4]byte{3}, StartFrame: 6, EndFrame: 9},
+	}
+	if !reflect.DeepEqual(regions, want) {
+		t.Fatalf("expected regions %+v, got %+v", want, regions)
+	}
+
+	// the decoder is left at the start of the file
+	buf, err := dec.FullPCMBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if buf.NumFrames() != 10 {
+		t.Fatalf("expected 10 frames, got %d", buf.NumFrames())
+	}
+}
+
+func TestDecoder_LoopRegionsFixture(t *testing.T) {
+	file, err := os.Open("fixtures/flloop.wav")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	regions, err := NewDecoder(file).LoopRegions()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []LoopRegion{{CuePointID: [4]byte{0, 0, 2, 0}, Type: 1024, StartFrame: 0, EndFrame: 107999}}
+	if !reflect.DeepEqual(regions, want) {
+		t.Fatalf("expected regions %+v, got %+v", want, regions)
+	}
+
+	_, err = NewDecoder(bytes.NewReader(makeWavWithUnknownChunks(t))).LoopRegions()
+	if err != nil {
+		t.Fatalf("expected no error without a smpl chunk, got %v", err)
+	}
+}
@@ -0,0 +1,28 @@
+package wav
+
+import "errors"
+
+// ErrMaxFramesReached is returned by the write methods once
+// Encoder.MaxFrames frames have been written, including by the write that
+// reaches the limit. Samples beyond the limit are dropped; the caller can
+// stop feeding the encoder and Close it as usual.
+var ErrMaxFramesReached = errors.New("maximum frame count reached")
+
+// limitFrames returns how many of frames still fit under MaxFrames.
+func (e *Encoder) limitFrames(frames int) int {
+	if e.MaxFrames <= 0 {
+		return frames
+	}
+
+	return max(min(frames, e.MaxFrames-e.frames), 0)
+}
+
+// maxFramesErr returns ErrMaxFramesReached once MaxFrames frames were
+// written.
+func (e *Encoder) maxFramesErr() error {
+	if e.MaxFrames > 0 && e.frames >= e.MaxFrames {
+		return ErrMaxFramesReached
+	}
+
+	return nil
+}
@@ -0,0 +1,106 @@
+package wav
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/go-audio/audio"
+)
+
+func TestEncoder_MaxFrames(t *testing.T) {
+	enc := NewMemoryEncoder(8000, 16, 2, wavFormatPCM)
+	enc.MaxFrames = 5
+
+	write := func(data ...float32) error {
+		return enc.Write(&audio.Float32Buffer{
+			Format: &audio.Format{NumChannels: 2, SampleRate: 8000},
+			Data:   data,
+		})
+	}
+
+	err := write(0.5, -0.5, 0.25, -0.25, 0.125, -0.125)
+	if err != nil {
+		t.Fatalf("expected the first write to fit, got %v", err)
+	}
+
+	err = write(0.75, -0.75, 1, -1, 0.5, 0.5)
+	if !errors.Is(err, ErrMaxFramesReached) {
+		t.Fatalf("expected ErrMaxFramesReached, got %v", err)
+	}
+
+	if err := write(0.5, 0.5); !errors.Is(err, ErrMaxFramesReached) {
+		t.Fatalf("expected ErrMaxFramesReached after the limit, got %v", err)
+	}
+
+	if err := enc.WriteSilence(3); !errors.Is(err, ErrMaxFramesReached) {
+		t.Fatalf("expected WriteSilence to report the limit, got %v", err)
+	}
+
+	data, err := enc.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf, err := NewDecoder(bytes.NewReader(data)).FullPCMBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []float32{0.5, -0.5, 0.25, -0.25, 0.125, -0.125, 0.75, -0.75, 1, -1}
+	if len(buf.Data) != len(want) {
+		t.Fatalf("expected %d samples, got %d", len(want), len(buf.Data))
+	}
+
+	assertFloat32SlicesClose(t, buf.Data, want, 1.0/32768)
+}
+
+func TestEncoder_MaxFramesWriteFrame(t *testing.T) {
+	testCases := []struct {
+		name  string
+		frame func(val float32) any
+	}{
+		{name: "float32", frame: func(val float32) any { return val }},
+		{name: "float64", frame: func(val float32) any { return float64(val) }},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			enc := NewMemoryEncoder(8000, 16, 1, wavFormatPCM)
+			enc.MaxFrames = 2
+
+			if err := enc.WriteFrame(testCase.frame(0.5)); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := enc.WriteFrame(testCase.frame(0.25)); !errors.Is(err, ErrMaxFramesReached) {
+				t.Fatalf("expected the frame reaching the limit to report it, got %v", err)
+			}
+
+			if err := enc.WriteFrame(testCase.frame(0.125)); !errors.Is(err, ErrMaxFramesReached) {
+				t.Fatalf("expected further frames to be dropped, got %v", err)
+			}
+
+			data, err := enc.Close()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			chunks, err := parseWavChunks(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if dataChunk, _ := findChunk(chunks, "data"); dataChunk == nil || dataChunk.size != 4 || len(dataChunk.data) != 4 {
+				t.Fatalf("expected a 4 byte data chunk, got %+v", dataChunk)
+			}
+
+			buf, err := NewDecoder(bytes.NewReader(data)).FullPCMBuffer()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assertFloat32SlicesClose(t, buf.Data, []float32{0.5, 0.25}, 0)
+		})
+	}
+}
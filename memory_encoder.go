@@ -0,0 +1,100 @@
+package wav
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+var errNegativeSeek = errors.New("negative seek position")
+
+// MemoryEncoder encodes a wav file into memory instead of an io.WriteSeeker.
+// All Encoder methods and fields are available; Close returns the complete
+// file.
+type MemoryEncoder struct {
+	*Encoder
+
+	buf *seekBuffer
+}
+
+// NewMemoryEncoder creates an encoder writing to an internal seekable buffer.
+func NewMemoryEncoder(sampleRate, bitDepth, numChans, audioFormat int) *MemoryEncoder {
+	buf := &seekBuffer{}
+
+	return &MemoryEncoder{
+		Encoder: NewEncoder(buf, sampleRate, bitDepth, numChans, audioFormat),
+		buf:     buf,
+	}
+}
+
+// Close finalizes the file and returns its bytes.
+func (e *MemoryEncoder) Close() ([]byte, error) {
+	if e == nil || e.Encoder == nil {
+		return nil, errNilEncoder
+	}
+
+	err := e.Encoder.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return e.Bytes(), nil
+}
+
+// Bytes returns the bytes written so far. The headers only hold the final
+// sizes once Close was called.
+func (e *MemoryEncoder) Bytes() []byte {
+	if e == nil || e.buf == nil {
+		return nil
+	}
+
+	return e.buf.data
+}
+
+// seekBuffer is an in-memory io.WriteSeeker. Writes past the end grow the
+// buffer, seeking back overwrites existing bytes.
+type seekBuffer struct {
+	data []byte
+	pos  int64
+}
+
+func (b *seekBuffer) Write(p []byte) (int, error) {
+	end := b.pos + int64(len(p))
+	if end > int64(len(b.data)) {
+		if end > int64(cap(b.data)) {
+			grown := make([]byte, end, 2*end)
+			copy(grown, b.data)
+			b.data = grown
+		} else {
+			b.data = b.data[:end]
+		}
+	}
+
+	copy(b.data[b.pos:], p)
+	b.pos = end
+
+	return len(p), nil
+}
+
+func (b *seekBuffer) Seek(offset int64, whence int) (int64, error) {
+	var abs int64
+
+	switch whence {
+	case io.SeekStart:
+		abs = offset
+	case io.SeekCurrent:
+		abs = b.pos + offset
+	case io.SeekEnd:
+		abs = int64(len(b.data)) + offset
+	default:
+		return 0, fmt.Errorf("invalid whence: %d", whence)
+	}
+
+	if abs < 0 {
+		return 0, errNegativeSeek
+	}
+
+	b.pos = abs
+
+	return abs, nil
+}
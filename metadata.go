@@ -52,6 +52,12 @@ type Metadata struct {
 	Location string
 	// TrackNbr is the track number
 	TrackNbr string
+	// SMPTETimecode is the SMPTE time code of the start of the recording, as
+	// written by cameras and field recorders. For example: 01:02:03:04.
+	SMPTETimecode string
+	// DigitizationTime is the date and time the file was digitized, usually
+	// in ctime format. For example: Wed Jan 02 02:03:55 1990.
+	DigitizationTime string
 	// CuePoints is a list of cue points in the wav file.
 	CuePoints []*CuePoint
 }
@@ -68,6 +74,11 @@ type BroadcastExtension struct {
 	UMID                [64]byte
 	Reserved            []byte
 	CodingHistory       string
+	// CodingHistorySize is the size in bytes of a null-padded coding history
+	// field as read; some writers pad it to a fixed size. The encoder pads
+	// the UTF-8 bytes of CodingHistory back to this size, a history longer
+	// than that is written whole. Zero writes CodingHistory as is.
+	CodingHistorySize int
 }
 
 // Cart represents practical fields from the cart chunk.
@@ -148,6 +159,9 @@ type SamplerInfo struct {
 	// because each sample loop associated cue point position is used to
 	// determine the play order.
 	Loops []*SampleLoop
+	// SamplerData holds the sampler specific data following the loops. It is
+	// kept as is so it can be written back unchanged.
+	SamplerData []byte
 }
 
 // SampleLoop indicates a loop and its properties within the audio file.
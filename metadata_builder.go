@@ -0,0 +1,183 @@
+package wav
+
+import "encoding/binary"
+
+// MetadataBuilder assembles a Metadata value with chainable setters, for
+// example:
+//
+//	md := wav.NewMetadataBuilder().
+//		Artist("Matt").
+//		Title("Intro").
+//		Cue(0, "start").
+//		Build()
+//
+// Use Metadata.Validate or Encoder.SetMetadata to check the result.
+type MetadataBuilder struct {
+	md Metadata
+}
+
+// NewMetadataBuilder returns an empty metadata builder.
+func NewMetadataBuilder() *MetadataBuilder {
+	return &MetadataBuilder{}
+}
+
+// Build returns the assembled metadata. The cue points are copied, so the
+// builder can be reused to derive further metadata.
+func (b *MetadataBuilder) Build() *Metadata {
+	md := b.md
+
+	if len(b.md.CuePoints) > 0 {
+		md.CuePoints = make([]*CuePoint, len(b.md.CuePoints))
+
+		for i, cue := range b.md.CuePoints {
+			cp := *cue
+			md.CuePoints[i] = &cp
+		}
+	}
+
+	return &md
+}
+
+// Artist sets the IART entry.
+func (b *MetadataBuilder) Artist(s string) *MetadataBuilder {
+	b.md.Artist = s
+	return b
+}
+
+// Comments sets the ICMT entry.
+func (b *MetadataBuilder) Comments(s string) *MetadataBuilder {
+	b.md.Comments = s
+	return b
+}
+
+// Copyright sets the ICOP entry.
+func (b *MetadataBuilder) Copyright(s string) *MetadataBuilder {
+	b.md.Copyright = s
+	return b
+}
+
+// CreationDate sets the ICRD entry.
+func (b *MetadataBuilder) CreationDate(s string) *MetadataBuilder {
+	b.md.CreationDate = s
+	return b
+}
+
+// Engineer sets the IENG entry.
+func (b *MetadataBuilder) Engineer(s string) *MetadataBuilder {
+	b.md.Engineer = s
+	return b
+}
+
+// Technician sets the ITCH entry.
+func (b *MetadataBuilder) Technician(s string) *MetadataBuilder {
+	b.md.Technician = s
+	return b
+}
+
+// Genre sets the IGNR entry.
+func (b *MetadataBuilder) Genre(s string) *MetadataBuilder {
+	b.md.Genre = s
+	return b
+}
+
+// Keywords sets the IKEY entry.
+func (b *MetadataBuilder) Keywords(s string) *MetadataBuilder {
+	b.md.Keywords = s
+	return b
+}
+
+// Medium sets the IMED entry.
+func (b *MetadataBuilder) Medium(s string) *MetadataBuilder {
+	b.md.Medium = s
+	return b
+}
+
+// Title sets the INAM entry.
+func (b *MetadataBuilder) Title(s string) *MetadataBuilder {
+	b.md.Title = s
+	return b
+}
+
+// Product sets the IPRD entry.
+func (b *MetadataBuilder) Product(s string) *MetadataBuilder {
+	b.md.Product = s
+	return b
+}
+
+// Subject sets the ISBJ entry.
+func (b *MetadataBuilder) Subject(s string) *MetadataBuilder {
+	b.md.Subject = s
+	return b
+}
+
+// Software sets the ISFT entry.
+func (b *MetadataBuilder) Software(s string) *MetadataBuilder {
+	b.md.Software = s
+	return b
+}
+
+// Source sets the ISRC entry.
+func (b *MetadataBuilder) Source(s string) *MetadataBuilder {
+	b.md.Source = s
+	return b
+}
+
+// Location sets the IARL entry.
+func (b *MetadataBuilder) Location(s string) *MetadataBuilder {
+	b.md.Location = s
+	return b
+}
+
+// TrackNbr sets the ITRK entry.
+func (b *MetadataBuilder) TrackNbr(s string) *MetadataBuilder {
+	b.md.TrackNbr = s
+	return b
+}
+
+// SMPTETimecode sets the ISMP entry.
+func (b *MetadataBuilder) SMPTETimecode(s string) *MetadataBuilder {
+	b.md.SMPTETimecode = s
+	return b
+}
+
+// DigitizationTime sets the IDIT entry.
+func (b *MetadataBuilder) DigitizationTime(s string) *MetadataBuilder {
+	b.md.DigitizationTime = s
+	return b
+}
+
+// BroadcastExtension sets the bext chunk.
+func (b *MetadataBuilder) BroadcastExtension(bext *BroadcastExtension) *MetadataBuilder {
+	b.md.BroadcastExtension = bext
+	return b
+}
+
+// Cart sets the cart chunk.
+func (b *MetadataBuilder) Cart(cart *Cart) *MetadataBuilder {
+	b.md.Cart = cart
+	return b
+}
+
+// SamplerInfo sets the smpl chunk.
+func (b *MetadataBuilder) SamplerInfo(info *SamplerInfo) *MetadataBuilder {
+	b.md.SamplerInfo = info
+	return b
+}
+
+// Cue adds a cue point at the given sample frame of the data chunk. Cue
+// points are numbered from 1 in the order they are added. A non-empty label
+// is written as a labl entry of the associated data list.
+func (b *MetadataBuilder) Cue(position uint32, label string) *MetadataBuilder {
+	cue := &CuePoint{
+		Position:     position,
+		DataChunkID:  [4]byte{'d', 'a', 't', 'a'},
+		SampleOffset: position,
+		Label:        label,
+	}
+
+	binary.LittleEndian.PutUint32(cue.ID[:], uint32(len(b.md.CuePoints)+1))
+
+	b.md.CuePoints = append(b.md.CuePoints, cue)
+
+	return b
+}
@@ -0,0 +1,150 @@
+package wav
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/go-audio/audio"
+)
+
+func TestMetadataBuilder(t *testing.T) {
+	builder := NewMetadataBuilder().
+		Artist("Matt").
+		Title("Intro").
+		Genre("test").
+		Cue(0, "start").
+		Cue(4, "")
+
+	md := builder.Build()
+
+	if md.Artist != "Matt" || md.Title != "Intro" || md.Genre != "test" {
+		t.Fatalf("unexpected INFO fields: %+v", md)
+	}
+
+	if len(md.CuePoints) != 2 {
+		t.Fatalf("expected 2 cue points, got %d", len(md.CuePoints))
+	}
+
+	second := md.CuePoints[1]
+	if second.ID != [4]byte{2, 0, 0, 0} || second.Position != 4 || second.SampleOffset != 4 || second.DataChunkID != [4]byte{'d', 'a', 't', 'a'} {
+		t.Fatalf("unexpected cue point: %+v", second)
+	}
+
+	// building again must not share cue points with earlier results
+	builder.Cue(6, "end")
+	md.CuePoints[0].Label = "changed"
+
+	again := builder.Build()
+	if len(md.CuePoints) != 2 || len(again.CuePoints) != 3 || again.CuePoints[0].Label != "start" {
+		t.Fatalf("builder results share state: %+v, %+v", md.CuePoints, again.CuePoints)
+	}
+
+	enc := NewMemoryEncoder(8000, 16, 1, wavFormatPCM)
+
+	err := enc.SetMetadata(again)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = enc.Write(&audio.Float32Buffer{
+		Format: &audio.Format{NumChannels: 1, SampleRate: 8000},
+		Data:   make([]float32, 8),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := enc.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dec := NewDecoder(bytes.NewReader(data))
+	dec.ReadMetadata()
+
+	if err = dec.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if dec.Metadata == nil || dec.Metadata.Artist != "Matt" || len(dec.Metadata.CuePoints) != 3 {
+		t.Fatalf("unexpected decoded metadata: %+v", dec.Metadata)
+	}
+
+	for i, want := range again.CuePoints {
+		if got := dec.Metadata.CuePoints[i]; *got != *want {
+			t.Fatalf("cue point %d: expected %+v, got %+v", i, want, got)
+		}
+	}
+}
+
+func TestMetadata_Validate(t *testing.T) {
+	valid := &Metadata{
+		BroadcastExtension: &BroadcastExtension{
+			Description:     "desc",
+			OriginationDate: "2024-02-29",
+			OriginationTime: "23:59:59",
+		},
+		Cart: &Cart{Version: "0101", StartDate: "2024_01_01", StartTime: "00.00.00"},
+	}
+
+	err := valid.Validate()
+	if err != nil {
+		t.Fatalf("expected valid metadata, got %v", err)
+	}
+
+	testCases := []struct {
+		name string
+		md   *Metadata
+	}{
+		{"bext date", &Metadata{BroadcastExtension: &BroadcastExtension{OriginationDate: "29.02.2024"}}},
+		{"bext month", &Metadata{BroadcastExtension: &BroadcastExtension{OriginationDate: "2024-13-01"}}},
+		{"bext time", &Metadata{BroadcastExtension: &BroadcastExtension{OriginationTime: "24:00:00"}}},
+		{"bext originator", &Metadata{BroadcastExtension: &BroadcastExtension{Originator: strings.Repeat("x", bextOriginatorLen+1)}}},
+		{"cart title", &Metadata{Cart: &Cart{Title: strings.Repeat("x", cartTitleLen+1)}}},
+		{"cart end date", &Metadata{Cart: &Cart{EndDate: "tomorrow"}}},
+		{"info NUL", &Metadata{Title: "a\x00b"}},
+		{"duplicate cue", &Metadata{CuePoints: []*CuePoint{{ID: [4]byte{1}}, {ID: [4]byte{1}}}}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := testCase.md.Validate()
+			if !errors.Is(err, ErrInvalidMetadata) {
+				t.Fatalf("expected ErrInvalidMetadata, got %v", err)
+			}
+
+			enc := NewMemoryEncoder(8000, 16, 1, wavFormatPCM)
+
+			err = enc.SetMetadata(testCase.md)
+			if !errors.Is(err, ErrInvalidMetadata) || enc.Metadata != nil {
+				t.Fatalf("expected SetMetadata to reject the metadata, got %v", err)
+			}
+		})
+	}
+}
+
+func TestDecodeAdtlBeforeCue(t *testing.T) {
+	md := NewMetadataBuilder().Cue(3, "odd").Build()
+
+	buf := bytes.NewBuffer(nil)
+	writeTestChunk(t, buf, "fmt ", []byte{1, 0, 1, 0, 0x40, 0x1f, 0, 0, 0x80, 0x3e, 0, 0, 2, 0, 16, 0})
+	writeTestChunk(t, buf, "LIST", encodeAdtlList(md.CuePoints))
+	writeTestChunk(t, buf, "cue ", encodeCueChunk(md.CuePoints))
+	writeTestChunk(t, buf, "data", make([]byte, 8))
+
+	data := append([]byte("RIFF\x00\x00\x00\x00WAVE"), buf.Bytes()...)
+	data[4] = byte(len(data) - 8)
+
+	dec := NewDecoder(bytes.NewReader(data))
+	dec.ReadMetadata()
+
+	if err := dec.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if dec.Metadata == nil || len(dec.Metadata.CuePoints) != 1 || dec.Metadata.CuePoints[0].Label != "odd" {
+		t.Fatalf("expected a labeled cue point, got %+v", dec.Metadata)
+	}
+}
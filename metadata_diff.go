@@ -0,0 +1,194 @@
+package wav
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FieldChange describes a metadata field that differs between two Metadata
+// values. Values are formatted for display, absent values are empty.
+type FieldChange struct {
+	// Field is the field name, prefixed with the chunk struct for nested
+	// fields, e.g. "Title" or "BroadcastExtension.Description".
+	Field string
+	Old   string
+	New   string
+}
+
+// metadataField is a named, formatted metadata value.
+type metadataField struct {
+	name  string
+	value string
+}
+
+// infoFields returns the LIST INFO fields in the order they are written.
+func (m *Metadata) infoFields() []metadataField {
+	return []metadataField{
+		{"Artist", m.Artist},
+		{"Comments", m.Comments},
+		{"Copyright", m.Copyright},
+		{"CreationDate", m.CreationDate},
+		{"Engineer", m.Engineer},
+		{"Technician", m.Technician},
+		{"Genre", m.Genre},
+		{"Keywords", m.Keywords},
+		{"Medium", m.Medium},
+		{"Title", m.Title},
+		{"Product", m.Product},
+		{"Subject", m.Subject},
+		{"Software", m.Software},
+		{"Source", m.Source},
+		{"Location", m.Location},
+		{"TrackNbr", m.TrackNbr},
+		{"SMPTETimecode", m.SMPTETimecode},
+		{"DigitizationTime", m.DigitizationTime},
+	}
+}
+
+// Diff returns the fields that differ from m to other, with m holding the
+// old values, in the order INFO, bext, cart, smpl, cue. A nil Metadata or
+// nil nested struct compares like an empty one, so a removed bext chunk is
+// reported as its non-empty fields changing to empty values.
+func (m *Metadata) Diff(other *Metadata) []FieldChange {
+	if m == nil {
+		m = &Metadata{}
+	}
+
+	if other == nil {
+		other = &Metadata{}
+	}
+
+	var changes []FieldChange
+
+	compare := func(prefix string, before, after []metadataField) {
+		for i := range before {
+			if before[i].value != after[i].value {
+				changes = append(changes, FieldChange{Field: prefix + before[i].name, Old: before[i].value, New: after[i].value})
+			}
+		}
+	}
+
+	compare("", m.infoFields(), other.infoFields())
+	compare("BroadcastExtension.", bextFields(m.BroadcastExtension), bextFields(other.BroadcastExtension))
+	compare("Cart.", cartFields(m.Cart), cartFields(other.Cart))
+	compare("SamplerInfo.", samplerFields(m.SamplerInfo), samplerFields(other.SamplerInfo))
+	compare("", []metadataField{cuePointsField(m.CuePoints)}, []metadataField{cuePointsField(other.CuePoints)})
+
+	return changes
+}
+
+func bextFields(bext *BroadcastExtension) []metadataField {
+	if bext == nil {
+		bext = &BroadcastExtension{}
+	}
+
+	return []metadataField{
+		{"Description", bext.Description},
+		{"Originator", bext.Originator},
+		{"OriginatorReference", bext.OriginatorReference},
+		{"OriginationDate", bext.OriginationDate},
+		{"OriginationTime", bext.OriginationTime},
+		{"TimeReference", formatNonZero(bext.TimeReference)},
+		{"Version", formatNonZero(bext.Version)},
+		{"UMID", formatBytes(bext.UMID[:])},
+		{"Reserved", formatBytes(bext.Reserved)},
+		{"CodingHistory", bext.CodingHistory},
+	}
+}
+
+func cartFields(cart *Cart) []metadataField {
+	if cart == nil {
+		cart = &Cart{}
+	}
+
+	return []metadataField{
+		{"Version", cart.Version},
+		{"Title", cart.Title},
+		{"Artist", cart.Artist},
+		{"CutID", cart.CutID},
+		{"ClientID", cart.ClientID},
+		{"Category", cart.Category},
+		{"Classification", cart.Classification},
+		{"OutCue", cart.OutCue},
+		{"StartDate", cart.StartDate},
+		{"StartTime", cart.StartTime},
+		{"EndDate", cart.EndDate},
+		{"EndTime", cart.EndTime},
+		{"ProducerAppID", cart.ProducerAppID},
+		{"ProducerAppVersion", cart.ProducerAppVersion},
+		{"UserDef", cart.UserDef},
+		{"LevelReference", formatNonZero(cart.LevelReference)},
+		{"PostTimer", formatNonZero(cart.PostTimer)},
+		{"Reserved", formatBytes(cart.Reserved)},
+		{"URL", cart.URL},
+		{"TagText", cart.TagText},
+	}
+}
+
+func samplerFields(info *SamplerInfo) []metadataField {
+	if info == nil {
+		info = &SamplerInfo{}
+	}
+
+	loops := make([]string, 0, len(info.Loops))
+	for _, loop := range info.Loops {
+		if loop != nil {
+			loops = append(loops, fmt.Sprintf("%x type %d %d-%d fraction %d count %d",
+				loop.CuePointID, loop.Type, loop.Start, loop.End, loop.Fraction, loop.PlayCount))
+		}
+	}
+
+	return []metadataField{
+		{"Manufacturer", formatBytes(info.Manufacturer[:])},
+		{"Product", formatBytes(info.Product[:])},
+		{"SamplePeriod", formatNonZero(info.SamplePeriod)},
+		{"MIDIUnityNote", formatNonZero(info.MIDIUnityNote)},
+		{"MIDIPitchFraction", formatNonZero(info.MIDIPitchFraction)},
+		{"SMPTEFormat", formatNonZero(info.SMPTEFormat)},
+		{"SMPTEOffset", formatNonZero(info.SMPTEOffset)},
+		{"NumSampleLoops", formatNonZero(info.NumSampleLoops)},
+		{"Loops", strings.Join(loops, ", ")},
+		{"SamplerData", formatBytes(info.SamplerData)},
+	}
+}
+
+func cuePointsField(points []*CuePoint) metadataField {
+	formatted := make([]string, 0, len(points))
+
+	for _, point := range points {
+		if point == nil {
+			continue
+		}
+
+		entry := fmt.Sprintf("%x@%d", point.ID, point.Position)
+		if point.Label != "" {
+			entry += fmt.Sprintf(" %q", point.Label)
+		}
+
+		formatted = append(formatted, entry)
+	}
+
+	return metadataField{"CuePoints", strings.Join(formatted, ", ")}
+}
+
+// formatNonZero formats value, the zero value formats as empty like an
+// absent field.
+func formatNonZero[T comparable](value T) string {
+	var zero T
+	if value == zero {
+		return ""
+	}
+
+	return fmt.Sprint(value)
+}
+
+// formatBytes formats binary fields as hex, all zero bytes format as empty.
+func formatBytes(b []byte) string {
+	for _, c := range b {
+		if c != 0 {
+			return fmt.Sprintf("%x", b)
+		}
+	}
+
+	return ""
+}
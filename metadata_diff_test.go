@@ -0,0 +1,70 @@
+package wav
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMetadataDiff(t *testing.T) {
+	old := &Metadata{
+		Title:  "Take 1",
+		Artist: "Band",
+		BroadcastExtension: &BroadcastExtension{
+			Description:   "rough mix",
+			TimeReference: 48000,
+		},
+		CuePoints: []*CuePoint{{ID: [4]byte{1}, Position: 10, Label: "verse"}},
+	}
+
+	updated := &Metadata{
+		Title:  "Take 2",
+		Artist: "Band",
+		BroadcastExtension: &BroadcastExtension{
+			Description:   "rough mix",
+			TimeReference: 96000,
+		},
+		Cart:      &Cart{Title: "spot"},
+		CuePoints: []*CuePoint{{ID: [4]byte{1}, Position: 10, Label: "chorus"}},
+	}
+
+	want := []FieldChange{
+		{Field: "Title", Old: "Take 1", New: "Take 2"},
+		{Field: "BroadcastExtension.TimeReference", Old: "48000", New: "96000"},
+		{Field: "Cart.Title", Old: "", New: "spot"},
+		{Field: "CuePoints", Old: `01000000@10 "verse"`, New: `01000000@10 "chorus"`},
+	}
+
+	if got := old.Diff(updated); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected changes\n%+v\ngot\n%+v", want, got)
+	}
+
+	if got := old.Diff(old); len(got) != 0 {
+		t.Fatalf("expected no changes for equal metadata, got %+v", got)
+	}
+}
+
+func TestMetadataDiffNil(t *testing.T) {
+	md := &Metadata{
+		Genre:       "Jazz",
+		SamplerInfo: &SamplerInfo{MIDIUnityNote: 60},
+	}
+
+	want := []FieldChange{
+		{Field: "Genre", Old: "", New: "Jazz"},
+		{Field: "SamplerInfo.MIDIUnityNote", Old: "", New: "60"},
+	}
+
+	var none *Metadata
+
+	if got := none.Diff(md); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected changes\n%+v\ngot\n%+v", want, got)
+	}
+
+	if got := md.Diff(nil); len(got) != 2 || got[0].Old != "Jazz" || got[0].New != "" {
+		t.Fatalf("expected the fields to be removed, got %+v", got)
+	}
+
+	if got := (&Metadata{}).Diff(&Metadata{BroadcastExtension: &BroadcastExtension{}}); len(got) != 0 {
+		t.Fatalf("expected an empty bext to equal a missing one, got %+v", got)
+	}
+}
@@ -0,0 +1,239 @@
+package wav
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+)
+
+// jsonMetadata mirrors Metadata with stable JSON field names. Byte arrays are
+// encoded as hex strings.
+type jsonMetadata struct {
+	Artist             string                  `json:"artist,omitempty"`
+	Comments           string                  `json:"comments,omitempty"`
+	Copyright          string                  `json:"copyright,omitempty"`
+	CreationDate       string                  `json:"creationDate,omitempty"`
+	Engineer           string                  `json:"engineer,omitempty"`
+	Technician         string                  `json:"technician,omitempty"`
+	Genre              string                  `json:"genre,omitempty"`
+	Keywords           string                  `json:"keywords,omitempty"`
+	Medium             string                  `json:"medium,omitempty"`
+	Title              string                  `json:"title,omitempty"`
+	Product            string                  `json:"product,omitempty"`
+	Subject            string                  `json:"subject,omitempty"`
+	Software           string                  `json:"software,omitempty"`
+	Source             string                  `json:"source,omitempty"`
+	Location           string                  `json:"location,omitempty"`
+	TrackNbr           string                  `json:"trackNbr,omitempty"`
+	SMPTETimecode      string                  `json:"smpteTimecode,omitempty"`
+	DigitizationTime   string                  `json:"digitizationTime,omitempty"`
+	SamplerInfo        *jsonSamplerInfo        `json:"samplerInfo,omitempty"`
+	BroadcastExtension *jsonBroadcastExtension `json:"bext,omitempty"`
+	Cart               *jsonCart               `json:"cart,omitempty"`
+	CuePoints          []jsonCuePoint          `json:"cuePoints,omitempty"`
+}
+
+type jsonSamplerInfo struct {
+	Manufacturer      string           `json:"manufacturer"`
+	Product           string           `json:"product"`
+	SamplePeriod      uint32           `json:"samplePeriod"`
+	MIDIUnityNote     uint32           `json:"midiUnityNote"`
+	MIDIPitchFraction uint32           `json:"midiPitchFraction"`
+	SMPTEFormat       uint32           `json:"smpteFormat"`
+	SMPTEOffset       uint32           `json:"smpteOffset"`
+	NumSampleLoops    uint32           `json:"numSampleLoops"`
+	Loops             []jsonSampleLoop `json:"loops,omitempty"`
+	SamplerData       string           `json:"samplerData,omitempty"`
+}
+
+type jsonSampleLoop struct {
+	CuePointID string `json:"cuePointId"`
+	Type       uint32 `json:"type"`
+	Start      uint32 `json:"start"`
+	End        uint32 `json:"end"`
+	Fraction   uint32 `json:"fraction"`
+	PlayCount  uint32 `json:"playCount"`
+}
+
+type jsonBroadcastExtension struct {
+	Description         string `json:"description"`
+	Originator          string `json:"originator"`
+	OriginatorReference string `json:"originatorReference"`
+	OriginationDate     string `json:"originationDate"`
+	OriginationTime     string `json:"originationTime"`
+	TimeReference       uint64 `json:"timeReference"`
+	Version             uint16 `json:"version"`
+	UMID                string `json:"umid"`
+	Reserved            string `json:"reserved,omitempty"`
+	CodingHistory       string `json:"codingHistory,omitempty"`
+}
+
+type jsonCart struct {
+	Version            string    `json:"version"`
+	Title              string    `json:"title"`
+	Artist             string    `json:"artist"`
+	CutID              string    `json:"cutId"`
+	ClientID           string    `json:"clientId"`
+	Category           string    `json:"category"`
+	Classification     string    `json:"classification"`
+	OutCue             string    `json:"outCue"`
+	StartDate          string    `json:"startDate"`
+	StartTime          string    `json:"startTime"`
+	EndDate            string    `json:"endDate"`
+	EndTime            string    `json:"endTime"`
+	ProducerAppID      string    `json:"producerAppId"`
+	ProducerAppVersion string    `json:"producerAppVersion"`
+	UserDef            string    `json:"userDef"`
+	LevelReference     int32     `json:"levelReference"`
+	PostTimer          [8]uint32 `json:"postTimer"`
+	Reserved           string    `json:"reserved,omitempty"`
+	URL                string    `json:"url"`
+	TagText            string    `json:"tagText"`
+}
+
+type jsonCuePoint struct {
+	ID           string `json:"id"`
+	Position     uint32 `json:"position"`
+	DataChunkID  string `json:"dataChunkId"`
+	ChunkStart   uint32 `json:"chunkStart"`
+	BlockStart   uint32 `json:"blockStart"`
+	SampleOffset uint32 `json:"sampleOffset"`
+	Label        string `json:"label,omitempty"`
+}
+
+// MetadataJSON reads the metadata and returns it encoded as JSON. Byte arrays
+// such as the bext UMID are encoded as hex strings. A file without metadata
+// yields null.
+func (d *Decoder) MetadataJSON() ([]byte, error) {
+	if d == nil {
+		return nil, errNilDecoder
+	}
+
+	d.ReadMetadata()
+
+	err := d.Err()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read metadata: %w", err)
+	}
+
+	out, err := json.Marshal(newJSONMetadata(d.Metadata))
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+
+	return out, nil
+}
+
+func newJSONMetadata(m *Metadata) *jsonMetadata {
+	if m == nil {
+		return nil
+	}
+
+	out := &jsonMetadata{
+		Artist:           m.Artist,
+		Comments:         m.Comments,
+		Copyright:        m.Copyright,
+		CreationDate:     m.CreationDate,
+		Engineer:         m.Engineer,
+		Technician:       m.Technician,
+		Genre:            m.Genre,
+		Keywords:         m.Keywords,
+		Medium:           m.Medium,
+		Title:            m.Title,
+		Product:          m.Product,
+		Subject:          m.Subject,
+		Software:         m.Software,
+		Source:           m.Source,
+		Location:         m.Location,
+		TrackNbr:         m.TrackNbr,
+		SMPTETimecode:    m.SMPTETimecode,
+		DigitizationTime: m.DigitizationTime,
+	}
+
+	if info := m.SamplerInfo; info != nil {
+		out.SamplerInfo = &jsonSamplerInfo{
+			Manufacturer:      hex.EncodeToString(info.Manufacturer[:]),
+			Product:           hex.EncodeToString(info.Product[:]),
+			SamplePeriod:      info.SamplePeriod,
+			MIDIUnityNote:     info.MIDIUnityNote,
+			MIDIPitchFraction: info.MIDIPitchFraction,
+			SMPTEFormat:       info.SMPTEFormat,
+			SMPTEOffset:       info.SMPTEOffset,
+			NumSampleLoops:    info.NumSampleLoops,
+			SamplerData:       hex.EncodeToString(info.SamplerData),
+		}
+
+		for _, loop := range info.Loops {
+			if loop == nil {
+				continue
+			}
+
+			out.SamplerInfo.Loops = append(out.SamplerInfo.Loops, jsonSampleLoop{
+				CuePointID: hex.EncodeToString(loop.CuePointID[:]),
+				Type:       loop.Type,
+				Start:      loop.Start,
+				End:        loop.End,
+				Fraction:   loop.Fraction,
+				PlayCount:  loop.PlayCount,
+			})
+		}
+	}
+
+	if bext := m.BroadcastExtension; bext != nil {
+		out.BroadcastExtension = &jsonBroadcastExtension{
+			Description:         bext.Description,
+			Originator:          bext.Originator,
+			OriginatorReference: bext.OriginatorReference,
+			OriginationDate:     bext.OriginationDate,
+			OriginationTime:     bext.OriginationTime,
+			TimeReference:       bext.TimeReference,
+			Version:             bext.Version,
+			UMID:                hex.EncodeToString(bext.UMID[:]),
+			Reserved:            hex.EncodeToString(bext.Reserved),
+			CodingHistory:       bext.CodingHistory,
+		}
+	}
+
+	if cart := m.Cart; cart != nil {
+		out.Cart = &jsonCart{
+			Version:            cart.Version,
+			Title:              cart.Title,
+			Artist:             cart.Artist,
+			CutID:              cart.CutID,
+			ClientID:           cart.ClientID,
+			Category:           cart.Category,
+			Classification:     cart.Classification,
+			OutCue:             cart.OutCue,
+			StartDate:          cart.StartDate,
+			StartTime:          cart.StartTime,
+			EndDate:            cart.EndDate,
+			EndTime:            cart.EndTime,
+			ProducerAppID:      cart.ProducerAppID,
+			ProducerAppVersion: cart.ProducerAppVersion,
+			UserDef:            cart.UserDef,
+			LevelReference:     cart.LevelReference,
+			PostTimer:          cart.PostTimer,
+			Reserved:           hex.EncodeToString(cart.Reserved),
+			URL:                cart.URL,
+			TagText:            cart.TagText,
+		}
+	}
+
+	for _, cue := range m.CuePoints {
+		if cue == nil {
+			continue
+		}
+
+		out.CuePoints = append(out.CuePoints, jsonCuePoint{
+			ID:           hex.EncodeToString(cue.ID[:]),
+			Position:     cue.Position,
+			DataChunkID:  hex.EncodeToString(cue.DataChunkID[:]),
+			ChunkStart:   cue.ChunkStart,
+			BlockStart:   cue.BlockStart,
+			SampleOffset: cue.SampleOffset,
+			Label:        cue.Label,
+		})
+	}
+
+	return out
+}
@@ -1,10 +1,17 @@
 package wav
 
 import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"os"
 	"path"
 	"reflect"
 	"testing"
+
+	"github.com/go-audio/audio"
 )
 
 func TestDecoder_ReadMetadata(t *testing.T) {
@@ -27,96 +34,112 @@ func TestDecoder_ReadMetadata(t *testing.T) {
 					ID:          [4]uint8{0x1, 0x0, 0x0, 0x0},
 					Position:    0x0,
 					DataChunkID: [4]uint8{'d', 'a', 't', 'a'},
+					Label:       "Hat + Kick",
 				},
 				1: {
 					ID:           [4]uint8{0x2, 0x0, 0x0, 0x0},
 					Position:     0x1a5e,
 					DataChunkID:  [4]uint8{'d', 'a', 't', 'a'},
 					SampleOffset: 0x1a5e,
+					Label:        "Hat",
 				},
 				2: {
 					ID:           [4]uint8{0x3, 0x0, 0x0, 0x0},
 					Position:     0x34bc,
 					DataChunkID:  [4]uint8{'d', 'a', 't', 'a'},
 					SampleOffset: 0x34bc,
+					Label:        "Hat",
 				},
 				3: {
 					ID:           [4]uint8{0x4, 0x0, 0x0, 0x0},
 					Position:     0x4f1a,
 					DataChunkID:  [4]uint8{'d', 'a', 't', 'a'},
 					SampleOffset: 0x4f1a,
+					Label:        "Hat",
 				},
 				4: {
 					ID:           [4]uint8{0x5, 0x0, 0x0, 0x0},
 					Position:     0x6978,
 					DataChunkID:  [4]uint8{'d', 'a', 't', 'a'},
 					SampleOffset: 0x6978,
+					Label:        "Snare + Clap + Hat",
 				},
 				5: {
 					ID:           [4]uint8{0x6, 0x0, 0x0, 0x0},
 					Position:     0x83d6,
 					DataChunkID:  [4]uint8{0x64, 0x61, 0x74, 0x61},
 					SampleOffset: 0x83d6,
+					Label:        "Hat",
 				},
 				6: {
 					ID:           [4]uint8{0x7, 0x0, 0x0, 0x0},
 					Position:     0x9e34,
 					DataChunkID:  [4]uint8{0x64, 0x61, 0x74, 0x61},
 					SampleOffset: 0x9e34,
+					Label:        "Hat",
 				},
 				7: {
 					ID:           [4]uint8{0x8, 0x0, 0x0, 0x0},
 					Position:     0xb892,
 					DataChunkID:  [4]uint8{0x64, 0x61, 0x74, 0x61},
 					SampleOffset: 0xb892,
+					Label:        "Hat",
 				},
 				8: {
 					ID:           [4]uint8{0x9, 0x0, 0x0, 0x0},
 					Position:     0xd2f0,
 					DataChunkID:  [4]uint8{0x64, 0x61, 0x74, 0x61},
 					SampleOffset: 0xd2f0,
+					Label:        "Kick + Hat",
 				},
 				9: {
 					ID:           [4]uint8{0xa, 0x0, 0x0, 0x0},
 					Position:     0xed4e,
 					DataChunkID:  [4]uint8{0x64, 0x61, 0x74, 0x61},
 					SampleOffset: 0xed4e,
+					Label:        "Hat",
 				},
 				10: {
 					ID:           [4]uint8{0xb, 0x0, 0x0, 0x0},
 					Position:     0x107ac,
 					DataChunkID:  [4]uint8{0x64, 0x61, 0x74, 0x61},
 					SampleOffset: 0x107ac,
+					Label:        "Hat",
 				},
 				11: {
 					ID:           [4]uint8{0xc, 0x0, 0x0, 0x0},
 					Position:     0x1220a,
 					DataChunkID:  [4]uint8{0x64, 0x61, 0x74, 0x61},
 					SampleOffset: 0x1220a,
+					Label:        "Hat",
 				},
 				12: {
 					ID:           [4]uint8{0xd, 0x0, 0x0, 0x0},
 					Position:     0x13c68,
 					DataChunkID:  [4]uint8{0x64, 0x61, 0x74, 0x61},
 					SampleOffset: 0x13c68,
+					Label:        "Clap + Snare + Hat",
 				},
 				13: {
 					ID:           [4]uint8{0xe, 0x0, 0x0, 0x0},
 					Position:     0x156c6,
 					DataChunkID:  [4]uint8{0x64, 0x61, 0x74, 0x61},
 					SampleOffset: 0x156c6,
+					Label:        "Hat",
 				},
 				14: {
 					ID:           [4]uint8{0xf, 0x0, 0x0, 0x0},
 					Position:     0x17124,
 					DataChunkID:  [4]uint8{0x64, 0x61, 0x74, 0x61},
 					SampleOffset: 0x17124,
+					Label:        "Kick + Hat",
 				},
 				15: {
 					ID:           [4]uint8{0x10, 0x0, 0x0, 0x0},
 					Position:     0x18b82,
 					DataChunkID:  [4]uint8{0x64, 0x61, 0x74, 0x61},
 					SampleOffset: 0x18b82,
+					Label:        "Hat",
 				},
 			},
 			SamplerInfo: &SamplerInfo{
@@ -170,3 +193,333 @@ func TestDecoder_ReadMetadata(t *testing.T) {
 		})
 	}
 }
+
+func TestDecoder_ReadMetadata_ErrorMode(t *testing.T) {
+	cuePayload := make([]byte, 4+24)
+	binary.LittleEndian.PutUint32(cuePayload[0:4], 1)
+	copy(cuePayload[4:8], "cue1")
+	binary.LittleEndian.PutUint32(cuePayload[8:12], 42)
+	copy(cuePayload[12:16], "data")
+
+	var b bytes.Buffer
+	b.WriteString("RIFF")
+	b.Write(make([]byte, 4))
+	b.WriteString("WAVE")
+
+	fmtPayload := make([]byte, 16)
+	binary.LittleEndian.PutUint16(fmtPayload[0:2], wavFormatPCM)
+	binary.LittleEndian.PutUint16(fmtPayload[2:4], 1)
+	binary.LittleEndian.PutUint32(fmtPayload[4:8], 8000)
+	binary.LittleEndian.PutUint32(fmtPayload[8:12], 16000)
+	binary.LittleEndian.PutUint16(fmtPayload[12:14], 2)
+	binary.LittleEndian.PutUint16(fmtPayload[14:16], 16)
+	writeTestChunk(t, &b, "fmt ", fmtPayload)
+	writeTestChunk(t, &b, "data", []byte{0x01, 0x00, 0x02, 0x00})
+	// a truncated smpl chunk fails to decode
+	writeTestChunk(t, &b, "smpl", []byte{'a', 'b', 'c', 'd'})
+	writeTestChunk(t, &b, "cue ", cuePayload)
+
+	data := b.Bytes()
+	binary.LittleEndian.PutUint32(data[4:8], uint32(len(data)-8))
+
+	t.Run("lenient", func(t *testing.T) {
+		dec := NewDecoder(bytes.NewReader(data))
+		dec.ReadMetadata()
+
+		if err := dec.Err(); err != nil {
+			t.Fatalf("expected no decoder error, got %v", err)
+		}
+
+		if len(dec.ChunkErrors) != 1 || !errors.Is(dec.ChunkErrors[0], errSmplProductReadFail) {
+			t.Fatalf("expected one smpl chunk error, got %v", dec.ChunkErrors)
+		}
+
+		if dec.Metadata == nil || len(dec.Metadata.CuePoints) != 1 || dec.Metadata.CuePoints[0].Position != 42 {
+			t.Fatalf("expected the cue point after the bad chunk to be parsed, got %+v", dec.Metadata)
+		}
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		dec := NewDecoder(bytes.NewReader(data))
+		dec.SetErrorMode(true)
+		dec.ReadMetadata()
+
+		if err := dec.Err(); !errors.Is(err, errSmplProductReadFail) {
+			t.Fatalf("expected smpl decode error, got %v", err)
+		}
+
+		if len(dec.ChunkErrors) != 0 {
+			t.Fatalf("expected no collected chunk errors in strict mode, got %v", dec.ChunkErrors)
+		}
+
+		if dec.Metadata != nil && len(dec.Metadata.CuePoints) != 0 {
+			t.Fatal("expected parsing to stop before the cue chunk")
+		}
+	})
+}
+
+func TestDecoder_ReadMetadataPartial(t *testing.T) {
+	const frames = 1 << 19
+
+	enc := NewMemoryEncoder(8000, 16, 1, wavFormatPCM)
+	enc.Metadata = &Metadata{
+		Title:              "partial",
+		BroadcastExtension: &BroadcastExtension{Description: "take"},
+	}
+
+	err := enc.WriteSilence(frames)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := enc.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reader := &countingReadSeeker{ReadSeeker: bytes.NewReader(data)}
+	dec := NewDecoder(reader)
+	dec.ReadMetadataPartial()
+
+	if err := dec.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if dec.Metadata == nil || dec.Metadata.Title != "partial" || dec.Metadata.BroadcastExtension == nil {
+		t.Fatalf("expected the trailing metadata to be read, got %+v", dec.Metadata)
+	}
+
+	if reader.read > frames {
+		t.Fatalf("expected the data payload to be skipped, read %d bytes", reader.read)
+	}
+
+	// without relative seeks the payload is read instead
+	reader = &countingReadSeeker{ReadSeeker: relativeSeekFailer{bytes.NewReader(data)}}
+	dec = NewDecoder(reader)
+	dec.ReadMetadataPartial()
+
+	if dec.Metadata == nil || dec.Metadata.Title != "partial" {
+		t.Fatalf("expected the fallback to read the metadata, got %+v", dec.Metadata)
+	}
+
+	if reader.read < 2*frames {
+		t.Fatalf("expected the data payload to be read, read %d bytes", reader.read)
+	}
+
+	err = dec.Rewind()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf, err := dec.FullPCMBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if buf.NumFrames() != frames {
+		t.Fatalf("expected %d frames after rewinding, got %d", frames, buf.NumFrames())
+	}
+}
+
+func TestMetadata_InfoRoundTrip(t *testing.T) {
+	testCases := []struct {
+		marker string
+		field  string
+	}{
+		{"IART", "Artist"},
+		{"ICMT", "Comments"},
+		{"ICOP", "Copyright"},
+		{"ICRD", "CreationDate"},
+		{"IENG", "Engineer"},
+		{"ITCH", "Technician"},
+		{"IGNR", "Genre"},
+		{"IKEY", "Keywords"},
+		{"IMED", "Medium"},
+		{"INAM", "Title"},
+		{"IPRD", "Product"},
+		{"ISBJ", "Subject"},
+		{"ISFT", "Software"},
+		{"ISRC", "Source"},
+		{"IARL", "Location"},
+		{"ITRK", "TrackNbr"},
+		{"ISMP", "SMPTETimecode"},
+		{"IDIT", "DigitizationTime"},
+	}
+
+	// every string field of Metadata is an INFO entry and must be covered
+	covered := map[string]bool{}
+	for _, tc := range testCases {
+		covered[tc.field] = true
+	}
+
+	mdType := reflect.TypeOf(Metadata{})
+	for i := range mdType.NumField() {
+		field := mdType.Field(i)
+		if field.Type.Kind() == reflect.String && !covered[field.Name] {
+			t.Fatalf("INFO field %s is missing from the round-trip table", field.Name)
+		}
+	}
+
+	all := &Metadata{}
+
+	for _, tc := range testCases {
+		t.Run(tc.marker, func(t *testing.T) {
+			md := &Metadata{}
+			reflect.ValueOf(md).Elem().FieldByName(tc.field).SetString("value of " + tc.field)
+			reflect.ValueOf(all).Elem().FieldByName(tc.field).SetString("value of " + tc.field)
+
+			info := encodeInfoChunk(&Encoder{Metadata: md})
+			if !bytes.Contains(info, []byte(tc.marker)) {
+				t.Fatalf("expected %s to be encoded as %s", tc.field, tc.marker)
+			}
+
+			got := encodeDecodeMetadata(t, md)
+			if !reflect.DeepEqual(got, md) {
+				t.Fatalf("round trip mismatch: got %+v, want %+v", got, md)
+			}
+		})
+	}
+
+	got := encodeDecodeMetadata(t, all)
+	if !reflect.DeepEqual(got, all) {
+		t.Fatalf("round trip mismatch: got %+v, want %+v", got, all)
+	}
+}
+
+func TestDecodeListChunk_LowercaseITRK(t *testing.T) {
+	var info bytes.Buffer
+	info.Write(CIDInfo)
+	writeTestChunk(t, &info, "itrk", []byte("7\x00"))
+
+	var b bytes.Buffer
+	b.WriteString("RIFF")
+	b.Write(make([]byte, 4))
+	b.WriteString("WAVE")
+
+	fmtPayload := make([]byte, 16)
+	binary.LittleEndian.PutUint16(fmtPayload[0:2], wavFormatPCM)
+	binary.LittleEndian.PutUint16(fmtPayload[2:4], 1)
+	binary.LittleEndian.PutUint32(fmtPayload[4:8], 8000)
+	binary.LittleEndian.PutUint32(fmtPayload[8:12], 16000)
+	binary.LittleEndian.PutUint16(fmtPayload[12:14], 2)
+	binary.LittleEndian.PutUint16(fmtPayload[14:16], 16)
+	writeTestChunk(t, &b, "fmt ", fmtPayload)
+	writeTestChunk(t, &b, "data", []byte{0x01, 0x00})
+	writeTestChunk(t, &b, "LIST", info.Bytes())
+
+	data := b.Bytes()
+	binary.LittleEndian.PutUint32(data[4:8], uint32(len(data)-8))
+
+	dec := NewDecoder(bytes.NewReader(data))
+	dec.ReadMetadata()
+
+	if err := dec.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if dec.Metadata == nil || dec.Metadata.TrackNbr != "7" {
+		t.Fatalf("expected the lowercase itrk entry to decode as TrackNbr 7, got %+v", dec.Metadata)
+	}
+}
+
+// encodeDecodeMetadata writes a short file carrying md and returns the
+// metadata decoded from it.
+func encodeDecodeMetadata(t *testing.T, md *Metadata) *Metadata {
+	t.Helper()
+
+	enc := NewMemoryEncoder(8000, 16, 1, wavFormatPCM)
+	enc.Metadata = md
+
+	err := enc.WriteSilence(4)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := enc.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dec := NewDecoder(bytes.NewReader(data))
+	dec.ReadMetadata()
+
+	if err := dec.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	return dec.Metadata
+}
+
+func TestDecoder_MetadataJSON(t *testing.T) {
+	var umid [64]byte
+	copy(umid[:], []byte{0xde, 0xad, 0xbe, 0xef})
+
+	enc := NewMemoryEncoder(48000, 16, 1, wavFormatPCM)
+	enc.Metadata = &Metadata{
+		Artist: "artist",
+		BroadcastExtension: &BroadcastExtension{
+			Description: "desc",
+			UMID:        umid,
+		},
+		Cart: &Cart{Version: "0101", Title: "cart title"},
+	}
+
+	// cue chunks have no typed encoder, pass one as a raw chunk.
+	cuePayload := make([]byte, 4+24)
+	binary.LittleEndian.PutUint32(cuePayload[0:4], 1)
+	cuePayload[4] = 1
+	binary.LittleEndian.PutUint32(cuePayload[8:12], 7)
+	copy(cuePayload[12:16], "data")
+	enc.UnknownChunks = []RawChunk{{ID: CIDCue, Size: uint32(len(cuePayload)), Data: cuePayload}}
+
+	err := enc.Write(&audio.Float32Buffer{
+		Format: &audio.Format{NumChannels: 1, SampleRate: 48000},
+		Data:   []float32{0, 0.5},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := enc.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := NewDecoder(bytes.NewReader(data)).MetadataJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got struct {
+		Artist string `json:"artist"`
+		Bext   struct {
+			Description string `json:"description"`
+			UMID        string `json:"umid"`
+		} `json:"bext"`
+		Cart struct {
+			Title string `json:"title"`
+		} `json:"cart"`
+		CuePoints []struct {
+			ID          string `json:"id"`
+			Position    uint32 `json:"position"`
+			DataChunkID string `json:"dataChunkId"`
+		} `json:"cuePoints"`
+	}
+
+	err = json.Unmarshal(out, &got)
+	if err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+
+	if got.Artist != "artist" || got.Bext.Description != "desc" || got.Cart.Title != "cart title" {
+		t.Fatalf("unexpected JSON: %s", out)
+	}
+
+	if want := hex.EncodeToString(umid[:]); got.Bext.UMID != want {
+		t.Fatalf("umid mismatch: got %q, want %q", got.Bext.UMID, want)
+	}
+
+	if len(got.CuePoints) != 1 || got.CuePoints[0].ID != "01000000" || got.CuePoints[0].Position != 7 || got.CuePoints[0].DataChunkID != "64617461" {
+		t.Fatalf("unexpected cue points: %s", out)
+	}
+}
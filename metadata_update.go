@@ -0,0 +1,201 @@
+package wav
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/go-audio/riff"
+)
+
+var (
+	// ErrMetadataNotTrailing is returned by UpdateMetadata when metadata
+	// chunks precede the data chunk, so they can't be replaced without
+	// moving the audio.
+	ErrMetadataNotTrailing = errors.New("metadata chunks precede the data chunk")
+	errUpdateRIFX          = errors.New("in-place metadata updates don't support RIFX files")
+	errTruncatedDataChunk  = errors.New("data chunk extends past the end of the file")
+
+	// junkChunkID replaces the ID of CSET chunks UpdateMetadata can't remove.
+	junkChunkID = [4]byte{'J', 'U', 'N', 'K'}
+)
+
+// UpdateMetadata replaces the metadata of a wav file in place without
+// touching the audio. The metadata chunks following the data chunk are
+// removed, other chunks there are kept, and the chunks for md are appended
+// as the encoder writes them; only the RIFF size is patched. A nil md removes
+// the metadata. Files with metadata chunks before the data chunk return
+// ErrMetadataNotTrailing and are left unchanged, rewrite those with an
+// Encoder instead. The new strings are written as UTF-8, so a CSET chunk
+// after the data chunk is removed and one before it is turned into a JUNK
+// chunk. f must be opened for reading and writing.
+func UpdateMetadata(f *os.File, md *Metadata) error {
+	if f == nil {
+		return errNilWriter
+	}
+
+	err := md.Validate()
+	if err != nil {
+		return err
+	}
+
+	var id [4]byte
+
+	_, err = f.ReadAt(id[:], 0)
+	if err != nil {
+		return fmt.Errorf("failed to read the RIFF header: %w", err)
+	}
+
+	if id == RIFXID {
+		return errUpdateRIFX
+	}
+
+	layout, err := NewDecoder(f).ChunkLayout()
+	if err != nil {
+		return err
+	}
+
+	end, kept, csets, err := trailingChunks(f, layout)
+	if err != nil {
+		return err
+	}
+
+	for _, off := range csets {
+		_, err = f.WriteAt(junkChunkID[:], off)
+		if err != nil {
+			return fmt.Errorf("failed to replace the CSET chunk: %w", err)
+		}
+	}
+
+	err = f.Truncate(end)
+	if err != nil {
+		return fmt.Errorf("failed to remove the old metadata: %w", err)
+	}
+
+	_, err = f.Seek(end, io.SeekStart)
+	if err != nil {
+		return fmt.Errorf("failed to seek to the end of the data chunk: %w", err)
+	}
+
+	enc := &Encoder{w: f, Metadata: md, WrittenBytes: int(end)}
+
+	for _, chunk := range kept {
+		err = enc.writeRawChunk(chunk)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = enc.writeMetadata()
+	if err != nil {
+		return fmt.Errorf("failed to write metadata - %w", err)
+	}
+
+	err = enc.writeLEAt(4, uint32(enc.WrittenBytes)-8)
+	if err != nil {
+		return fmt.Errorf("%w when writing the total written bytes", err)
+	}
+
+	err = f.Sync()
+	if err != nil {
+		return fmt.Errorf("failed to sync file: %w", err)
+	}
+
+	return nil
+}
+
+// trailingChunks returns the file offset following the data chunk, the
+// non-metadata chunks after it, which UpdateMetadata writes back, and the
+// offsets of the CSET chunks before it.
+func trailingChunks(f *os.File, layout []ChunkInfo) (int64, []RawChunk, []int64, error) {
+	dataIdx := -1
+
+	for i, info := range layout {
+		if info.ID == riff.DataFormatID {
+			dataIdx = i
+
+			break
+		}
+	}
+
+	if dataIdx < 0 {
+		return 0, nil, nil, ErrPCMChunkNotFound
+	}
+
+	var csets []int64
+
+	for _, info := range layout[:dataIdx] {
+		if info.ID == CIDCset {
+			csets = append(csets, info.Offset)
+
+			continue
+		}
+
+		isMeta, err := isMetadataChunk(f, info)
+		if err != nil {
+			return 0, nil, nil, err
+		}
+
+		if isMeta {
+			return 0, nil, nil, fmt.Errorf("%w: %q", ErrMetadataNotTrailing, info.ID)
+		}
+	}
+
+	stat, err := f.Stat()
+	if err != nil {
+		return 0, nil, nil, fmt.Errorf("failed to stat file: %w", err)
+	}
+
+	data := layout[dataIdx]
+	end := data.Offset + 8 + int64(data.Size) + int64(data.Size%2)
+
+	if end > stat.Size() {
+		return 0, nil, nil, errTruncatedDataChunk
+	}
+
+	var kept []RawChunk
+
+	for _, info := range layout[dataIdx+1:] {
+		isMeta, err := isMetadataChunk(f, info)
+		if err != nil {
+			return 0, nil, nil, err
+		}
+
+		if isMeta {
+			continue
+		}
+
+		payload := make([]byte, info.Size)
+
+		_, err = f.ReadAt(payload, info.Offset+8)
+		if err != nil {
+			return 0, nil, nil, fmt.Errorf("failed to read %q chunk: %w", info.ID, err)
+		}
+
+		kept = append(kept, RawChunk{ID: info.ID, Size: info.Size, Data: payload, Order: len(kept)})
+	}
+
+	return end, kept, csets, nil
+}
+
+// isMetadataChunk reports whether the chunk holds metadata written from
+// Metadata: bext, cart, cue, smpl, the INFO and adtl lists and the CSET
+// chunk declaring their character set.
+func isMetadataChunk(f *os.File, info ChunkInfo) (bool, error) {
+	switch info.ID {
+	case CIDBext, CIDCart, CIDCue, CIDSmpl, CIDCset:
+		return true, nil
+	case CIDList:
+		var listType [4]byte
+
+		_, err := f.ReadAt(listType[:], info.Offset+8)
+		if err != nil {
+			return false, fmt.Errorf("failed to read the LIST type: %w", err)
+		}
+
+		return listType == [4]byte(CIDInfo) || listType == [4]byte{'a', 'd', 't', 'l'}, nil
+	default:
+		return false, nil
+	}
+}
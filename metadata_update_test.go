@@ -0,0 +1,230 @@
+package wav
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-audio/audio"
+)
+
+func writeTaggedTestFile(t *testing.T, beforeData bool) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "tagged.wav")
+
+	out, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer out.Close()
+
+	enc := NewEncoder(out, 8000, 16, 1, wavFormatPCM)
+	enc.MetadataBeforeData = beforeData
+	enc.Metadata = NewMetadataBuilder().Title("old title").Artist("old artist").Cue(2, "marker").Build()
+	enc.AppendRawChunk([4]byte{'t', 'a', 'i', 'l'}, []byte{1, 2, 3}, false)
+
+	err = enc.Write(&audio.Float32Buffer{
+		Format: &audio.Format{NumChannels: 1, SampleRate: 8000},
+		Data:   []float32{0.5, -0.5, 0.25, -0.25, 0.125},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = enc.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestUpdateMetadata(t *testing.T) {
+	path := writeTaggedTestFile(t, false)
+
+	before, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = UpdateMetadata(f, NewMetadataBuilder().Title("new title").Build())
+	f.Close()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	after, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := binary.LittleEndian.Uint32(after[4:8]); int(got) != len(after)-8 {
+		t.Fatalf("RIFF size %d doesn't match the file size %d", got, len(after))
+	}
+
+	oldChunks, err := parseWavChunks(before)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newChunks, err := parseWavChunks(after)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	oldData, _ := findChunk(oldChunks, "data")
+	newData, _ := findChunk(newChunks, "data")
+
+	if !bytes.Equal(oldData.data, newData.data) {
+		t.Fatal("the audio changed")
+	}
+
+	if tail, _ := findChunk(newChunks, "tail"); tail == nil || !bytes.Equal(tail.data, []byte{1, 2, 3}) {
+		t.Fatalf("expected the tail chunk to be kept, got %+v", tail)
+	}
+
+	if cue, _ := findChunk(newChunks, "cue "); cue != nil {
+		t.Fatal("expected the old cue chunk to be removed")
+	}
+
+	dec := NewDecoder(bytes.NewReader(after))
+	dec.ReadMetadata()
+
+	if err := dec.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if dec.Metadata.Title != "new title" || dec.Metadata.Artist != "" {
+		t.Fatalf("unexpected metadata %+v", dec.Metadata)
+	}
+}
+
+func TestUpdateMetadata_MetadataBeforeData(t *testing.T) {
+	path := writeTaggedTestFile(t, true)
+
+	before, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	err = UpdateMetadata(f, NewMetadataBuilder().Title("new title").Build())
+	if !errors.Is(err, ErrMetadataNotTrailing) {
+		t.Fatalf("expected ErrMetadataNotTrailing, got %v", err)
+	}
+
+	after, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(before, after) {
+		t.Fatal("the file was modified")
+	}
+}
+
+func TestUpdateMetadata_CharacterSet(t *testing.T) {
+	for _, trailing := range []bool{false, true} {
+		var b bytes.Buffer
+		b.WriteString("RIFF")
+		b.Write(make([]byte, 4))
+		b.WriteString("WAVE")
+
+		fmtPayload := make([]byte, 16)
+		binary.LittleEndian.PutUint16(fmtPayload[0:2], wavFormatPCM)
+		binary.LittleEndian.PutUint16(fmtPayload[2:4], 1)
+		binary.LittleEndian.PutUint32(fmtPayload[4:8], 8000)
+		binary.LittleEndian.PutUint32(fmtPayload[8:12], 16000)
+		binary.LittleEndian.PutUint16(fmtPayload[12:14], 2)
+		binary.LittleEndian.PutUint16(fmtPayload[14:16], 16)
+		writeTestChunk(t, &b, "fmt ", fmtPayload)
+
+		cset := make([]byte, 8)
+		binary.LittleEndian.PutUint16(cset[0:2], 1252)
+
+		if !trailing {
+			writeTestChunk(t, &b, "CSET", cset)
+		}
+
+		writeTestChunk(t, &b, "data", []byte{1, 2, 3, 4})
+
+		if trailing {
+			writeTestChunk(t, &b, "CSET", cset)
+		}
+
+		writeTestChunk(t, &b, "LIST", []byte("INFOINAM\x05\x00\x00\x00Caf\xe9\x00\x00"))
+
+		data := b.Bytes()
+		binary.LittleEndian.PutUint32(data[4:8], uint32(len(data)-8))
+
+		path := filepath.Join(t.TempDir(), "cset.wav")
+
+		err := os.WriteFile(path, data, 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		dec := NewDecoder(bytes.NewReader(data))
+		dec.ReadMetadata()
+
+		if dec.Metadata == nil || dec.Metadata.Title != "Café" {
+			t.Fatalf("trailing=%v: unexpected fixture metadata %+v", trailing, dec.Metadata)
+		}
+
+		f, err := os.OpenFile(path, os.O_RDWR, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = UpdateMetadata(f, NewMetadataBuilder().Title("Crème brûlée").Build())
+		f.Close()
+
+		if err != nil {
+			t.Fatalf("trailing=%v: %v", trailing, err)
+		}
+
+		after, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		chunks, err := parseWavChunks(after)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if c, _ := findChunk(chunks, "CSET"); c != nil {
+			t.Fatalf("trailing=%v: expected the CSET chunk to be gone", trailing)
+		}
+
+		if data, _ := findChunk(chunks, "data"); data == nil || !bytes.Equal(data.data, []byte{1, 2, 3, 4}) {
+			t.Fatalf("trailing=%v: the audio changed", trailing)
+		}
+
+		dec = NewDecoder(bytes.NewReader(after))
+		dec.ReadMetadata()
+
+		if err := dec.Err(); err != nil {
+			t.Fatal(err)
+		}
+
+		if dec.Metadata.Title != "Crème brûlée" {
+			t.Fatalf("trailing=%v: got title %q", trailing, dec.Metadata.Title)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package wav
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidMetadata is returned by Metadata.Validate and Encoder.SetMetadata
+// for metadata that can't be written faithfully.
+var ErrInvalidMetadata = errors.New("invalid metadata")
+
+// Validate checks that the metadata fits the chunks it is written to: bext
+// and cart strings must fit their fixed size fields, their dates must be
+// formatted as yyyy-mm-dd and their times as hh:mm:ss, INFO values and cue
+// labels must not contain NUL bytes and cue point IDs must be unique. All
+// problems are reported, each wrapping ErrInvalidMetadata.
+func (m *Metadata) Validate() error {
+	if m == nil {
+		return nil
+	}
+
+	var errs []error
+
+	invalid := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%w: "+format, append([]any{ErrInvalidMetadata}, args...)...))
+	}
+
+	checkLen := func(field, val string, size int) {
+		if len(val) > size {
+			invalid("%s is %d bytes, the field holds %d", field, len(val), size)
+		}
+	}
+
+	checkDate := func(field, val string) {
+		if val != "" && !isBextDate(val) {
+			invalid("%s %q isn't a yyyy-mm-dd date", field, val)
+		}
+	}
+
+	checkTime := func(field, val string) {
+		if val != "" && !isBextTime(val) {
+			invalid("%s %q isn't a hh:mm:ss time", field, val)
+		}
+	}
+
+	for _, field := range m.infoFields() {
+		if strings.IndexByte(field.value, 0) >= 0 {
+			invalid("%s contains a NUL byte", field.name)
+		}
+	}
+
+	if bext := m.BroadcastExtension; bext != nil {
+		checkLen("bext Description", bext.Description, bextDescriptionLen)
+		checkLen("bext Originator", bext.Originator, bextOriginatorLen)
+		checkLen("bext OriginatorReference", bext.OriginatorReference, bextOriginatorReferenceLen)
+		checkDate("bext OriginationDate", bext.OriginationDate)
+		checkTime("bext OriginationTime", bext.OriginationTime)
+	}
+
+	if cart := m.Cart; cart != nil {
+		checkLen("cart Version", cart.Version, cartVersionLen)
+		checkLen("cart Title", cart.Title, cartTitleLen)
+		checkLen("cart Artist", cart.Artist, cartArtistLen)
+		checkLen("cart CutID", cart.CutID, cartCutIDLen)
+		checkLen("cart ClientID", cart.ClientID, cartClientIDLen)
+		checkLen("cart Category", cart.Category, cartCategoryLen)
+		checkLen("cart Classification", cart.Classification, cartClassificationLen)
+		checkLen("cart OutCue", cart.OutCue, cartOutCueLen)
+		checkDate("cart StartDate", cart.StartDate)
+		checkTime("cart StartTime", cart.StartTime)
+		checkDate("cart EndDate", cart.EndDate)
+		checkTime("cart EndTime", cart.EndTime)
+		checkLen("cart ProducerAppID", cart.ProducerAppID, cartProducerAppIDLen)
+		checkLen("cart ProducerAppVersion", cart.ProducerAppVersion, cartProducerAppVersionLen)
+		checkLen("cart UserDef", cart.UserDef, cartUserDefLen)
+	}
+
+	seen := map[[4]byte]bool{}
+
+	for i, cue := range m.CuePoints {
+		if cue == nil {
+			continue
+		}
+
+		if seen[cue.ID] {
+			invalid("cue point %d reuses ID % x", i, cue.ID)
+		}
+
+		seen[cue.ID] = true
+
+		if strings.IndexByte(cue.Label, 0) >= 0 {
+			invalid("cue point %d label contains a NUL byte", i)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// isBextDate reports whether s is a yyyy-mm-dd date. EBU Tech 3285 also
+// allows '_', ':', ' ' and '.' as separators.
+func isBextDate(s string) bool {
+	if !hasDigitGroups(s, bextOriginationDateLen, 4, 7) {
+		return false
+	}
+
+	month := atoi2(s[5:7])
+	day := atoi2(s[8:10])
+
+	return month >= 1 && month <= 12 && day >= 1 && day <= 31
+}
+
+// isBextTime reports whether s is a hh:mm:ss time, with the same separators
+// as isBextDate.
+func isBextTime(s string) bool {
+	if !hasDigitGroups(s, bextOriginationTimeLen, 2, 5) {
+		return false
+	}
+
+	return atoi2(s[0:2]) < 24 && atoi2(s[3:5]) < 60 && atoi2(s[6:8]) < 60
+}
+
+// hasDigitGroups reports whether s has the given length and consists of
+// digits apart from a separator at each of the two separator positions.
+func hasDigitGroups(s string, length, sep1, sep2 int) bool {
+	if len(s) != length {
+		return false
+	}
+
+	for i := range len(s) {
+		if i == sep1 || i == sep2 {
+			if !bytes.ContainsRune([]byte("-_:. "), rune(s[i])) {
+				return false
+			}
+
+			continue
+		}
+
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+func atoi2(s string) int {
+	return int(s[0]-'0')*10 + int(s[1]-'0')
+}
@@ -0,0 +1,295 @@
+package wav
+
+import (
+	"math"
+
+	"github.com/go-audio/audio"
+)
+
+// NormalizeMode selects the amplitude normalization applied by Encoder.Write.
+type NormalizeMode int
+
+const (
+	// NormalizeOff leaves samples untouched (default).
+	NormalizeOff NormalizeMode = iota
+	// NormalizePeak scales each written buffer so its absolute peak matches
+	// the target level in dBFS.
+	NormalizePeak
+	// NormalizeLUFS scales each written buffer so its integrated loudness
+	// (ITU-R BS.1770, all channels weighted equally) matches the target level
+	// in LUFS.
+	NormalizeLUFS
+)
+
+const (
+	// DefaultNormalizePeakTarget is the peak level used in NormalizePeak
+	// mode unless Encoder.SetNormalizeTarget was called.
+	DefaultNormalizePeakTarget = -1.0
+	// DefaultNormalizeLUFSTarget is the loudness used in NormalizeLUFS mode
+	// unless Encoder.SetNormalizeTarget was called.
+	DefaultNormalizeLUFSTarget = -23.0
+
+	loudnessBlockDuration  = 0.4
+	loudnessBlockOverlap   = 0.75
+	loudnessAbsoluteGate   = -70.0
+	loudnessRelativeGate   = -10.0
+	loudnessOffset         = -0.691
+	kWeightShelfFrequency  = 1681.974450955533
+	kWeightShelfGain       = 3.999843853973347
+	kWeightShelfQ          = 0.7071752369554196
+	kWeightShelfVbExponent = 0.4996667741545416
+	kWeightHighPassCorner  = 38.13547087602444
+	kWeightHighPassQ       = 0.5003270373238773
+	normalizeSilenceFloor  = 1e-9
+	decibelScale           = 20.0
+)
+
+// normalizeGain returns the linear gain that brings buf to the target level
+// for the given mode. It returns 1 when no gain should be applied.
+func normalizeGain(buf *audio.Float32Buffer, mode NormalizeMode, target float64) float64 {
+	if buf == nil || len(buf.Data) == 0 {
+		return 1
+	}
+
+	switch mode {
+	case NormalizePeak:
+		peak := peakAmplitude(buf.Data)
+		if peak < normalizeSilenceFloor {
+			return 1
+		}
+
+		return math.Pow(10, target/decibelScale) / peak
+	case NormalizeLUFS:
+		numChans, sampleRate := 1, 0
+		if buf.Format != nil {
+			numChans, sampleRate = buf.Format.NumChannels, buf.Format.SampleRate
+		}
+
+		loudness := integratedLoudness(buf.Data, numChans, sampleRate)
+		if math.IsInf(loudness, -1) {
+			return 1
+		}
+
+		return math.Pow(10, (target-loudness)/decibelScale)
+	default:
+		return 1
+	}
+}
+
+func peakAmplitude(samples []float32) float64 {
+	var peak float64
+
+	for _, s := range samples {
+		peak = max(peak, math.Abs(float64(s)))
+	}
+
+	return peak
+}
+
+// integratedLoudness measures the gated loudness of interleaved samples in
+// LUFS following ITU-R BS.1770-4. Inputs shorter than one 400ms block are
+// measured as a single block. Silence yields -Inf.
+func integratedLoudness(samples []float32, numChans, sampleRate int) float64 {
+	if numChans < 1 || sampleRate < 1 || len(samples) < numChans {
+		return math.Inf(-1)
+	}
+
+	numFrames := len(samples) / numChans
+	weighted := make([][]float64, numChans)
+
+	for ch := range numChans {
+		shelf := newKWeightShelf(float64(sampleRate))
+		highPass := newKWeightHighPass(float64(sampleRate))
+
+		weighted[ch] = make([]float64, numFrames)
+		for i := range numFrames {
+			weighted[ch][i] = highPass.process(shelf.process(float64(samples[i*numChans+ch])))
+		}
+	}
+
+	blockSize := max(int(loudnessBlockDuration*float64(sampleRate)), 1)
+	step := max(int(float64(blockSize)*(1-loudnessBlockOverlap)), 1)
+
+	var blocks []float64
+
+	for start := 0; start == 0 || start+blockSize <= numFrames; start += step {
+		end := min(start+blockSize, numFrames)
+
+		var power float64
+
+		for ch := range numChans {
+			var sum float64
+			for _, v := range weighted[ch][start:end] {
+				sum += v * v
+			}
+
+			power += sum / float64(end-start)
+		}
+
+		blocks = append(blocks, power)
+	}
+
+	gated := gateLoudnessBlocks(blocks, loudnessAbsoluteGate)
+	if len(gated) == 0 {
+		return math.Inf(-1)
+	}
+
+	relative := blockLoudness(meanPower(gated)) + loudnessRelativeGate
+
+	gated = gateLoudnessBlocks(gated, relative)
+	if len(gated) == 0 {
+		return math.Inf(-1)
+	}
+
+	return blockLoudness(meanPower(gated))
+}
+
+func gateLoudnessBlocks(blocks []float64, threshold float64) []float64 {
+	out := make([]float64, 0, len(blocks))
+
+	for _, power := range blocks {
+		if power > 0 && blockLoudness(power) > threshold {
+			out = append(out, power)
+		}
+	}
+
+	return out
+}
+
+func meanPower(blocks []float64) float64 {
+	var sum float64
+	for _, power := range blocks {
+		sum += power
+	}
+
+	return sum / float64(len(blocks))
+}
+
+func blockLoudness(power float64) float64 {
+	return loudnessOffset + 10*math.Log10(power)
+}
+
+// biquad is a direct form I second order IIR filter.
+type biquad struct {
+	b0, b1, b2 float64
+	a1, a2     float64
+	x1, x2     float64
+	y1, y2     float64
+}
+
+func (f *biquad) process(x float64) float64 {
+	y := f.b0*x + f.b1*f.x1 + f.b2*f.x2 - f.a1*f.y1 - f.a2*f.y2
+	f.x2, f.x1 = f.x1, x
+	f.y2, f.y1 = f.y1, y
+
+	return y
+}
+
+// newKWeightShelf returns the BS.1770 pre-filter (high shelf) designed for
+// the given sample rate.
+func newKWeightShelf(sampleRate float64) *biquad {
+	k := math.Tan(math.Pi * kWeightShelfFrequency / sampleRate)
+	vh := math.Pow(10, kWeightShelfGain/decibelScale)
+	vb := math.Pow(vh, kWeightShelfVbExponent)
+	a0 := 1 + k/kWeightShelfQ + k*k
+
+	return &biquad{
+		b0: (vh + vb*k/kWeightShelfQ + k*k) / a0,
+		b1: 2 * (k*k - vh) / a0,
+		b2: (vh - vb*k/kWeightShelfQ + k*k) / a0,
+		a1: 2 * (k*k - 1) / a0,
+		a2: (1 - k/kWeightShelfQ + k*k) / a0,
+	}
+}
+
+// newKWeightHighPass returns the BS.1770 RLB high-pass filter designed for
+// the given sample rate.
+func newKWeightHighPass(sampleRate float64) *biquad {
+	k := math.Tan(math.Pi * kWeightHighPassCorner / sampleRate)
+	a0 := 1 + k/kWeightHighPassQ + k*k
+
+	return &biquad{
+		b0: 1,
+		b1: -2,
+		b2: 1,
+		a1: 2 * (k*k - 1) / a0,
+		a2: (1 - k/kWeightHighPassQ + k*k) / a0,
+	}
+}
+
+// SetNormalizeTarget sets the target level for Normalize, in dBFS for
+// NormalizePeak and LUFS for NormalizeLUFS. Any level is taken as is, so 0
+// normalizes to full scale. Without a call, DefaultNormalizePeakTarget or
+// DefaultNormalizeLUFSTarget applies.
+func (e *Encoder) SetNormalizeTarget(level float64) {
+	if e == nil {
+		return
+	}
+
+	e.normalizeTargetLevel = &level
+}
+
+// normalizeTarget returns the level Normalize scales to, the one set with
+// SetNormalizeTarget or else the mode's default.
+func (e *Encoder) normalizeTarget() float64 {
+	if e.normalizeTargetLevel != nil {
+		return *e.normalizeTargetLevel
+	}
+
+	if e.Normalize == NormalizeLUFS {
+		return DefaultNormalizeLUFSTarget
+	}
+
+	return DefaultNormalizePeakTarget
+}
+
+// normalizedCopy returns buf scaled by the encoder's normalization gain. The
+// caller's buffer is never modified.
+func (e *Encoder) normalizedCopy(buf *audio.Float32Buffer) *audio.Float32Buffer {
+	if buf == nil || e.Normalize == NormalizeOff {
+		return buf
+	}
+
+	gain := normalizeGain(buf, e.Normalize, e.normalizeTarget())
+	if gain == 1 {
+		return buf
+	}
+
+	out := &audio.Float32Buffer{
+		Data:           make([]float32, len(buf.Data)),
+		Format:         buf.Format,
+		SourceBitDepth: buf.SourceBitDepth,
+	}
+
+	for i, s := range buf.Data {
+		out.Data[i] = float32(float64(s) * gain)
+	}
+
+	return out
+}
+
+// normalizedFloat64Copy is normalizedCopy for float64 samples. The gain is
+// measured at float32 precision, which is plenty for a level estimate, and
+// applied in float64.
+func (e *Encoder) normalizedFloat64Copy(data []float64, numChans int) []float64 {
+	measure := &audio.Float32Buffer{
+		Data:   make([]float32, len(data)),
+		Format: &audio.Format{NumChannels: numChans, SampleRate: e.SampleRate},
+	}
+
+	for i, s := range data {
+		measure.Data[i] = float32(s)
+	}
+
+	gain := normalizeGain(measure, e.Normalize, e.normalizeTarget())
+	if gain == 1 {
+		return data
+	}
+
+	out := make([]float64, len(data))
+	for i, s := range data {
+		out[i] = s * gain
+	}
+
+	return out
+}
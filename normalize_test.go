@@ -0,0 +1,139 @@
+package wav
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-audio/audio"
+)
+
+func makeSineBuffer(freq, amplitude float64, sampleRate, numChans, numFrames int) *audio.Float32Buffer {
+	data := make([]float32, numFrames*numChans)
+	for i := range numFrames {
+		v := float32(amplitude * math.Sin(2*math.Pi*freq*float64(i)/float64(sampleRate)))
+		for ch := range numChans {
+			data[i*numChans+ch] = v
+		}
+	}
+
+	return &audio.Float32Buffer{
+		Data:           data,
+		Format:         &audio.Format{NumChannels: numChans, SampleRate: sampleRate},
+		SourceBitDepth: 16,
+	}
+}
+
+func TestNormalizeGain(t *testing.T) {
+	buf := makeSineBuffer(1000, 0.5, 48000, 1, 48000)
+
+	if got := normalizeGain(buf, NormalizeOff, 0); got != 1 {
+		t.Fatalf("off gain mismatch: got %f want 1", got)
+	}
+
+	want := math.Pow(10, -1.0/20) / 0.5
+	if got := normalizeGain(buf, NormalizePeak, DefaultNormalizePeakTarget); math.Abs(got-want) > 1e-3 {
+		t.Fatalf("peak gain mismatch: got %f want %f", got, want)
+	}
+
+	if got := normalizeGain(buf, NormalizePeak, 0); math.Abs(got-2) > 1e-3 {
+		t.Fatalf("0 dBFS peak gain mismatch: got %f want 2", got)
+	}
+
+	silent := &audio.Float32Buffer{Data: make([]float32, 64), Format: buf.Format}
+	if got := normalizeGain(silent, NormalizePeak, -3); got != 1 {
+		t.Fatalf("silent peak gain mismatch: got %f want 1", got)
+	}
+
+	if got := normalizeGain(silent, NormalizeLUFS, -23); got != 1 {
+		t.Fatalf("silent loudness gain mismatch: got %f want 1", got)
+	}
+}
+
+func TestIntegratedLoudness_FullScaleSine(t *testing.T) {
+	// A full scale 997 Hz sine on a single channel measures -3.01 LUFS.
+	buf := makeSineBuffer(997, 1, 48000, 1, 48000*3)
+
+	got := integratedLoudness(buf.Data, 1, 48000)
+	if math.Abs(got-(-3.01)) > 0.05 {
+		t.Fatalf("loudness mismatch: got %f want -3.01", got)
+	}
+}
+
+func TestEncoder_NormalizeRoundTrip(t *testing.T) {
+	level := func(val float64) *float64 { return &val }
+
+	testCases := []struct {
+		name   string
+		mode   NormalizeMode
+		target *float64
+		want   float64
+	}{
+		{name: "peak", mode: NormalizePeak, target: level(-3), want: -3},
+		{name: "peak default", mode: NormalizePeak, want: DefaultNormalizePeakTarget},
+		{name: "peak 0 dBFS", mode: NormalizePeak, target: level(0), want: 0},
+		{name: "lufs", mode: NormalizeLUFS, target: level(-20), want: -20},
+		{name: "lufs default", mode: NormalizeLUFS, want: DefaultNormalizeLUFSTarget},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			src := makeSineBuffer(997, 0.25, 48000, 2, 48000)
+			orig := append([]float32(nil), src.Data...)
+
+			outPath := filepath.Join(t.TempDir(), "normalized.wav")
+
+			out, err := os.Create(outPath)
+			if err != nil {
+				t.Fatalf("create output: %v", err)
+			}
+
+			enc := NewEncoder(out, 48000, 24, 2, wavFormatPCM)
+			enc.Normalize = testCase.mode
+
+			if testCase.target != nil {
+				enc.SetNormalizeTarget(*testCase.target)
+			}
+
+			err = enc.Write(src)
+			if err != nil {
+				t.Fatalf("write: %v", err)
+			}
+
+			err = enc.Close()
+			if err != nil {
+				t.Fatalf("close encoder: %v", err)
+			}
+
+			err = out.Close()
+			if err != nil {
+				t.Fatalf("close output: %v", err)
+			}
+
+			assertFloat32SlicesClose(t, src.Data, orig, 0)
+
+			in, err := os.Open(outPath)
+			if err != nil {
+				t.Fatalf("open output: %v", err)
+			}
+			defer in.Close()
+
+			decoded, err := NewDecoder(in).FullPCMBuffer()
+			if err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+
+			var got float64
+			if testCase.mode == NormalizePeak {
+				got = 20 * math.Log10(peakAmplitude(decoded.Data))
+			} else {
+				got = integratedLoudness(decoded.Data, 2, 48000)
+			}
+
+			if math.Abs(got-testCase.want) > 0.05 {
+				t.Fatalf("normalized level mismatch: got %f want %f", got, testCase.want)
+			}
+		})
+	}
+}
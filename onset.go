@@ -0,0 +1,45 @@
+package wav
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/go-audio/audio"
+)
+
+const onsetBufferSize = 4096
+
+// FirstNonSilentFrame returns the index of the first frame in which any
+// channel has an absolute sample value above threshold, or -1 when all frames
+// are at or below it. Samples are decoded in small blocks from the current
+// position and decoding stops at the onset, so the cost grows with the onset
+// position rather than the file length. Frame indexes are relative to the
+// position decoding started at; use Rewind to scan from the start again.
+func (d *Decoder) FirstNonSilentFrame(threshold float64) (int64, error) {
+	if d == nil {
+		return 0, ErrPCMDataNotFound
+	}
+
+	buf := &audio.Float32Buffer{Data: make([]float32, onsetBufferSize)}
+
+	var samples int64
+
+	for {
+		n, err := d.pcmBuffer(buf)
+		if err != nil {
+			return 0, fmt.Errorf("failed to decode samples: %w", err)
+		}
+
+		if n == 0 {
+			return -1, nil
+		}
+
+		for i, val := range buf.Data[:n] {
+			if math.Abs(float64(val)) > threshold {
+				return (samples + int64(i)) / int64(max(d.NumChans, 1)), nil
+			}
+		}
+
+		samples += int64(n)
+	}
+}
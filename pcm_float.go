@@ -60,10 +60,20 @@ func normalizePCMInt(sample int, bitDepth int) float32 {
 	}
 }
 
+// normalizePCMIntBits scales a signed sample holding validBits significant
+// bits to [-1, 1).
+func normalizePCMIntBits(sample int, validBits int) float32 {
+	return float32(float64(sample) / float64(int64(1)<<(validBits-1)))
+}
+
 func float32ToPCMUint8(value float32) uint8 {
-	value = clampFloat32(value, -1, 1)
+	return float64ToPCMUint8(float64(value))
+}
+
+func float64ToPCMUint8(value float64) uint8 {
+	value = clampFloat64(value, -1, 1)
 
-	scaled := int(math.Round(float64((value + 1.0) * floatPCM8Scale)))
+	scaled := int(math.Round((value + 1.0) * floatPCM8Scale))
 	if scaled < 0 {
 		return 0
 	}
@@ -75,20 +85,30 @@ func float32ToPCMUint8(value float32) uint8 {
 	return uint8(scaled)
 }
 
+// float32ToPCMInt8 quantizes like float32ToPCMUint8 but centers the result
+// at 0.
+func float32ToPCMInt8(value float32) int8 {
+	return int8(int(float32ToPCMUint8(value)) - 128)
+}
+
 func float32ToPCMInt32(value float32, bitDepth int) int32 {
-	value = clampFloat32(value, -1, 1)
+	return float64ToPCMInt32(float64(value), bitDepth)
+}
+
+func float64ToPCMInt32(value float64, bitDepth int) int32 {
+	value = clampFloat64(value, -1, 1)
 
 	switch bitDepth {
 	case 16:
-		sample := max(min(int64(math.Round(float64(value)*scalePCMInt16)), maxPCMInt16), -scalePCMInt16)
+		sample := max(min(int64(math.Round(value*scalePCMInt16)), maxPCMInt16), -scalePCMInt16)
 
 		return int32(sample)
 	case 24:
-		sample := max(min(int64(math.Round(float64(value)*scalePCMInt24)), maxPCMInt24), -scalePCMInt24)
+		sample := max(min(int64(math.Round(value*scalePCMInt24)), maxPCMInt24), -scalePCMInt24)
 
 		return int32(sample)
 	case 32:
-		sample := max(min(int64(math.Round(float64(value)*scalePCMInt32)), maxPCMInt32), -scalePCMInt32)
+		sample := max(min(int64(math.Round(value*scalePCMInt32)), maxPCMInt32), -scalePCMInt32)
 
 		return int32(sample)
 	default:
@@ -100,6 +100,29 @@ func TestFloat32ToPCMUint8(t *testing.T) {
 	}
 }
 
+func TestFloat32ToPCMInt8(t *testing.T) {
+	tests := []struct {
+		name  string
+		value float32
+		want  int8
+	}{
+		{"min clamped", -2, -128},
+		{"negative one", -1, -128},
+		{"zero", 0, 0},
+		{"positive one", 1, 127},
+		{"max clamped", 2, 127},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := float32ToPCMInt8(tt.value)
+			if got != tt.want {
+				t.Fatalf("float32ToPCMInt8(%f)=%d, want %d", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
 func TestFloat32ToPCMInt32(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -0,0 +1,131 @@
+package wav
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/go-audio/audio"
+)
+
+var (
+	errPlanarChannelMismatch  = errors.New("planar buffer count doesn't match channel count")
+	errPlanarCapacityMismatch = errors.New("planar buffers must have equal capacities")
+)
+
+// PlanarBuffer decodes up to cap(out[0]) frames from the current position
+// into one slice per channel and returns the number of frames decoded; the
+// decoded samples are out[ch][:frames]. len(out) must match the channel count
+// and all slices must have the same capacity. Integer PCM, float and G.711
+// samples are written straight into the channel slices, other codecs are
+// decoded interleaved first. A trailing partial frame is dropped and 0 frames
+// signal the end of the data.
+func (d *Decoder) PlanarBuffer(out [][]float32) (frames int, err error) {
+	if d == nil {
+		return 0, ErrPCMDataNotFound
+	}
+
+	if !d.pcmDataAccessed {
+		err := d.FwdToPCM()
+		if err != nil {
+			return 0, d.err
+		}
+	}
+
+	if d.PCMChunk == nil {
+		return 0, ErrPCMChunkNotFound
+	}
+
+	numChans := int(d.NumChans)
+	if numChans == 0 || len(out) != numChans {
+		return 0, fmt.Errorf("%w: got %d buffers for %d channels", errPlanarChannelMismatch, len(out), numChans)
+	}
+
+	capacity := cap(out[0])
+	for ch := range out {
+		if cap(out[ch]) != capacity {
+			return 0, fmt.Errorf("%w: channel %d has %d, channel 0 has %d", errPlanarCapacityMismatch, ch, cap(out[ch]), capacity)
+		}
+
+		out[ch] = out[ch][:capacity]
+	}
+
+	if capacity == 0 {
+		return 0, nil
+	}
+
+	codec, err := d.sampleDecoder()
+	if err != nil {
+		return 0, err
+	}
+
+	if _, ok := codec.(*pcmSampleDecoder); ok {
+		frames, err = d.decodePlanarPCM(out, capacity)
+	} else {
+		frames, err = d.decodePlanarInterleaved(out, capacity)
+	}
+
+	if frames <= 0 && err == nil {
+		d.pcmDone = true
+	}
+
+	return frames, err
+}
+
+// decodePlanarPCM decodes PCM, float and G.711 frames directly into out.
+func (d *Decoder) decodePlanarPCM(out [][]float32, capacity int) (int, error) {
+	decodeF, err := sampleDecodeFloat32Func(d.containerBitDepth(), d.validBitsPerSample(), d.WavAudioFormat, d.byteOrder(), d.clampDecodedFloat())
+	if err != nil {
+		return 0, fmt.Errorf("could not get sample decode func %w", err)
+	}
+
+	bPerSample := bytesPerSample(d.containerBitDepth())
+	frameSize := bPerSample * len(out)
+	raw := make([]byte, capacity*frameSize)
+
+	n, err := io.ReadFull(&progressReader{d: d, r: d.PCMChunk.R}, raw)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return 0, fmt.Errorf("failed to read PCM data: %w", err)
+	}
+
+	frames := n / frameSize
+	bufR := bytes.NewReader(raw[:frames*frameSize])
+	sampleBuf := make([]byte, bPerSample)
+
+	for i := range frames {
+		for ch := range out {
+			out[ch][i], err = decodeF(bufR, sampleBuf)
+			if err != nil {
+				return i, fmt.Errorf("failed to decode sample: %w", err)
+			}
+		}
+	}
+
+	for ch := range out {
+		d.finishDecoded(out[ch][:frames])
+	}
+
+	return frames, nil
+}
+
+// decodePlanarInterleaved decodes frames through PCMBuffer and scatters them
+// into out, for codecs that only decode interleaved samples.
+func (d *Decoder) decodePlanarInterleaved(out [][]float32, capacity int) (int, error) {
+	buf := &audio.Float32Buffer{Data: make([]float32, capacity*len(out))}
+
+	n, err := d.pcmBuffer(buf)
+	if err != nil {
+		return 0, err
+	}
+
+	frames := n / len(out)
+
+	for i := range frames {
+		for ch := range out {
+			out[ch][i] = buf.Data[i*len(out)+ch]
+		}
+	}
+
+	return frames, nil
+}
@@ -0,0 +1,114 @@
+package wav
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/go-audio/audio"
+)
+
+func TestDecoder_PlanarBuffer(t *testing.T) {
+	enc := NewMemoryEncoder(8000, 16, 2, wavFormatPCM)
+
+	err := enc.Write(&audio.Float32Buffer{
+		Format: &audio.Format{NumChannels: 2, SampleRate: 8000},
+		Data:   []float32{0.5, -0.5, 0.25, -0.25, 0.125, -0.125, 0.75, -0.75, 0, 1},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := enc.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want, err := NewDecoder(bytes.NewReader(data)).FullPCMBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dec := NewDecoder(bytes.NewReader(data))
+	out := [][]float32{make([]float32, 0, 3), make([]float32, 0, 3)}
+
+	var got [2][]float32
+
+	for {
+		frames, err := dec.PlanarBuffer(out)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if frames == 0 {
+			break
+		}
+
+		for ch := range out {
+			got[ch] = append(got[ch], out[ch][:frames]...)
+		}
+	}
+
+	for ch := range got {
+		if len(got[ch]) != 5 {
+			t.Fatalf("channel %d: expected 5 frames, got %d", ch, len(got[ch]))
+		}
+
+		for i, val := range got[ch] {
+			if val != want.Data[i*2+ch] {
+				t.Fatalf("channel %d frame %d: got %v, want %v", ch, i, val, want.Data[i*2+ch])
+			}
+		}
+	}
+
+	if dec.Progress() != 1 {
+		t.Fatalf("expected progress 1 at the end, got %v", dec.Progress())
+	}
+
+	_, err = NewDecoder(bytes.NewReader(data)).PlanarBuffer([][]float32{make([]float32, 4)})
+	if !errors.Is(err, errPlanarChannelMismatch) {
+		t.Fatalf("expected errPlanarChannelMismatch, got %v", err)
+	}
+
+	_, err = NewDecoder(bytes.NewReader(data)).PlanarBuffer([][]float32{make([]float32, 4), make([]float32, 3)})
+	if !errors.Is(err, errPlanarCapacityMismatch) {
+		t.Fatalf("expected errPlanarCapacityMismatch, got %v", err)
+	}
+}
+
+func TestDecoder_PlanarBufferCompressed(t *testing.T) {
+	data, err := os.ReadFile("fixtures/addf8-GSM-GW.wav")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want, err := NewDecoder(bytes.NewReader(data)).FullPCMBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dec := NewDecoder(bytes.NewReader(data))
+	out := [][]float32{make([]float32, 512)}
+
+	var got []float32
+
+	for {
+		frames, err := dec.PlanarBuffer(out)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if frames == 0 {
+			break
+		}
+
+		got = append(got, out[0][:frames]...)
+	}
+
+	if len(got) != len(want.Data) {
+		t.Fatalf("expected %d frames, got %d", len(want.Data), len(got))
+	}
+
+	assertFloat32SlicesClose(t, got, want.Data, 0)
+}
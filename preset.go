@@ -0,0 +1,138 @@
+package wav
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Preset bundles an audio format with the bit depth it requires, so both are
+// set together and can't disagree.
+type Preset int
+
+const (
+	// PresetCD16 is 16-bit integer PCM, as used on audio CDs.
+	PresetCD16 Preset = iota + 1
+	// PresetPCM24 is 24-bit integer PCM.
+	PresetPCM24
+	// PresetFloat32 is 32-bit IEEE float.
+	PresetFloat32
+	// PresetFloat64 is 64-bit IEEE float.
+	PresetFloat64
+	// PresetTelephony8kALaw is 8-bit A-law at 8 kHz.
+	PresetTelephony8kALaw
+	// PresetTelephony8kMuLaw is 8-bit mu-law at 8 kHz.
+	PresetTelephony8kMuLaw
+)
+
+var (
+	errUnknownPreset     = errors.New("unknown encoder preset")
+	errPresetSampleRate  = errors.New("sample rate doesn't match the encoder preset")
+	errInvalidSampleRate = errors.New("invalid sample rate")
+)
+
+// presetFormat returns the audio format, bit depth and, for presets tied to
+// one rate, the sample rate of p.
+func presetFormat(p Preset) (audioFormat, bitDepth, sampleRate int, err error) {
+	switch p {
+	case PresetCD16:
+		return wavFormatPCM, 16, 0, nil
+	case PresetPCM24:
+		return wavFormatPCM, 24, 0, nil
+	case PresetFloat32:
+		return wavFormatIEEEFloat, 32, 0, nil
+	case PresetFloat64:
+		return wavFormatIEEEFloat, 64, 0, nil
+	case PresetTelephony8kALaw:
+		return wavFormatALaw, 8, 8000, nil
+	case PresetTelephony8kMuLaw:
+		return wavFormatMuLaw, 8, 8000, nil
+	default:
+		return 0, 0, 0, fmt.Errorf("%w: %d", errUnknownPreset, int(p))
+	}
+}
+
+// String returns the name of the preset.
+func (p Preset) String() string {
+	switch p {
+	case PresetCD16:
+		return "CD16"
+	case PresetPCM24:
+		return "PCM24"
+	case PresetFloat32:
+		return "Float32"
+	case PresetFloat64:
+		return "Float64"
+	case PresetTelephony8kALaw:
+		return "Telephony8kALaw"
+	case PresetTelephony8kMuLaw:
+		return "Telephony8kMuLaw"
+	default:
+		return fmt.Sprintf("Preset(%d)", int(p))
+	}
+}
+
+// NewEncoderPreset creates an encoder whose audio format and bit depth come
+// from preset. Presets tied to one sample rate, like the telephony ones,
+// reject any other rate; 0 selects the preset rate and is invalid for the
+// others.
+func NewEncoderPreset(w io.WriteSeeker, sampleRate, numChans int, preset Preset) (*Encoder, error) {
+	audioFormat, bitDepth, presetRate, err := presetFormat(preset)
+	if err != nil {
+		return nil, err
+	}
+
+	if sampleRate == 0 {
+		sampleRate = presetRate
+	}
+
+	if sampleRate < 1 {
+		return nil, fmt.Errorf("%w: %d", errInvalidSampleRate, sampleRate)
+	}
+
+	enc := NewEncoder(w, sampleRate, bitDepth, numChans, audioFormat)
+
+	err = enc.SetCompression(preset)
+	if err != nil {
+		return nil, err
+	}
+
+	return enc, nil
+}
+
+// SetCompression sets the audio format and bit depth from preset. It must be
+// called before the header is written and drops a FmtChunk carried over from
+// a decoder, since its format no longer applies. A SampleRate of 0 is set to
+// the rate of presets tied to one rate.
+func (e *Encoder) SetCompression(preset Preset) error {
+	if e == nil {
+		return errNilEncoder
+	}
+
+	if e.wroteHeader {
+		return errAlreadyWroteHdr
+	}
+
+	audioFormat, bitDepth, sampleRate, err := presetFormat(preset)
+	if err != nil {
+		return err
+	}
+
+	if e.NumChans < 1 {
+		return fmt.Errorf("%w: %d", errInvalidChannelCount, e.NumChans)
+	}
+
+	if sampleRate > 0 {
+		if e.SampleRate == 0 {
+			e.SampleRate = sampleRate
+		} else if e.SampleRate != sampleRate {
+			return fmt.Errorf("%w: %s needs %d Hz, got %d Hz", errPresetSampleRate, preset, sampleRate, e.SampleRate)
+		}
+	}
+
+	e.WavAudioFormat = audioFormat
+	e.BitDepth = bitDepth
+	e.FmtChunk = nil
+
+	return nil
+}
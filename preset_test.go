@@ -0,0 +1,92 @@
+package wav
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/go-audio/audio"
+)
+
+func TestNewEncoderPreset(t *testing.T) {
+	testCases := []struct {
+		preset      Preset
+		sampleRate  int
+		audioFormat uint16
+		bitDepth    uint16
+		wantRate    uint32
+	}{
+		{PresetCD16, 44100, wavFormatPCM, 16, 44100},
+		{PresetPCM24, 48000, wavFormatPCM, 24, 48000},
+		{PresetFloat32, 96000, wavFormatIEEEFloat, 32, 96000},
+		{PresetFloat64, 48000, wavFormatIEEEFloat, 64, 48000},
+		{PresetTelephony8kALaw, 8000, wavFormatALaw, 8, 8000},
+		{PresetTelephony8kMuLaw, 0, wavFormatMuLaw, 8, 8000},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.preset.String(), func(t *testing.T) {
+			out := &seekBuffer{}
+
+			enc, err := NewEncoderPreset(out, tc.sampleRate, 1, tc.preset)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = enc.Write(&audio.Float32Buffer{
+				Format: &audio.Format{NumChannels: 1, SampleRate: int(tc.wantRate)},
+				Data:   []float32{0, 0.5, -0.5, 0.25},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = enc.Close()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			dec := NewDecoder(bytes.NewReader(out.data))
+			dec.ReadInfo()
+
+			if dec.WavAudioFormat != tc.audioFormat || dec.BitDepth != tc.bitDepth || dec.SampleRate != tc.wantRate {
+				t.Fatalf("expected format %d/%d bits/%d Hz, got %d/%d bits/%d Hz",
+					tc.audioFormat, tc.bitDepth, tc.wantRate, dec.WavAudioFormat, dec.BitDepth, dec.SampleRate)
+			}
+		})
+	}
+}
+
+func TestNewEncoderPreset_Invalid(t *testing.T) {
+	_, err := NewEncoderPreset(&seekBuffer{}, 44100, 1, Preset(99))
+	if !errors.Is(err, errUnknownPreset) {
+		t.Fatalf("expected errUnknownPreset, got %v", err)
+	}
+
+	_, err = NewEncoderPreset(&seekBuffer{}, 44100, 1, PresetTelephony8kALaw)
+	if !errors.Is(err, errPresetSampleRate) {
+		t.Fatalf("expected errPresetSampleRate, got %v", err)
+	}
+
+	_, err = NewEncoderPreset(&seekBuffer{}, 0, 1, PresetCD16)
+	if !errors.Is(err, errInvalidSampleRate) {
+		t.Fatalf("expected errInvalidSampleRate, got %v", err)
+	}
+
+	_, err = NewEncoderPreset(&seekBuffer{}, 44100, 0, PresetCD16)
+	if !errors.Is(err, errInvalidChannelCount) {
+		t.Fatalf("expected errInvalidChannelCount, got %v", err)
+	}
+
+	enc := NewMemoryEncoder(44100, 16, 1, wavFormatPCM)
+
+	err = enc.WriteSilence(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = enc.SetCompression(PresetFloat32)
+	if !errors.Is(err, errAlreadyWroteHdr) {
+		t.Fatalf("expected errAlreadyWroteHdr after writing, got %v", err)
+	}
+}
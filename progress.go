@@ -0,0 +1,41 @@
+package wav
+
+import "io"
+
+// Progress returns the fraction of the data chunk decoded so far, from 0
+// before the samples are accessed to 1 once PCMBuffer reached the end of the
+// data or FullPCMBuffer decoded it. Compressed formats with a fact chunk
+// report the decoded samples relative to the fact sample count, all other
+// formats the bytes read relative to PCMSize.
+func (d *Decoder) Progress() float64 {
+	if d == nil || !d.pcmDataAccessed || d.PCMChunk == nil {
+		return 0
+	}
+
+	if d.pcmDone {
+		return 1
+	}
+
+	if d.WavAudioFormat == wavFormatGSM610 && d.CompressedSamples > 0 {
+		return min(float64(d.gsmDecoded)/float64(d.CompressedSamples), 1)
+	}
+
+	if d.PCMSize <= 0 {
+		return 0
+	}
+
+	return min(float64(d.pcmConsumed)/float64(d.PCMSize), 1)
+}
+
+// progressReader counts the data chunk bytes read through it for Progress.
+type progressReader struct {
+	d *Decoder
+	r io.Reader
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	p.d.pcmConsumed += int64(n)
+
+	return n, err
+}
@@ -0,0 +1,108 @@
+package wav
+
+import (
+	"bytes"
+	"math"
+	"os"
+	"testing"
+
+	"github.com/go-audio/audio"
+)
+
+func TestDecoder_Progress(t *testing.T) {
+	enc := NewMemoryEncoder(8000, 16, 1, wavFormatPCM)
+
+	err := enc.WriteSilence(1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := enc.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dec := NewDecoder(bytes.NewReader(data))
+	if progress := dec.Progress(); progress != 0 {
+		t.Fatalf("expected no progress before decoding, got %v", progress)
+	}
+
+	buf := &audio.Float32Buffer{Data: make([]float32, 250)}
+
+	for _, want := range []float64{0.25, 0.5, 0.75, 1, 1} {
+		_, err := dec.PCMBuffer(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if progress := dec.Progress(); progress != want {
+			t.Fatalf("expected progress %v, got %v", want, progress)
+		}
+	}
+
+	err = dec.SeekToFrame(400)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if progress := dec.Progress(); progress != 0.4 {
+		t.Fatalf("expected progress 0.4 after seeking, got %v", progress)
+	}
+
+	err = dec.Rewind()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if progress := dec.Progress(); progress != 0 {
+		t.Fatalf("expected no progress after rewinding, got %v", progress)
+	}
+
+	_, err = dec.FullPCMBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if progress := dec.Progress(); progress != 1 {
+		t.Fatalf("expected full progress, got %v", progress)
+	}
+}
+
+func TestDecoder_ProgressGSM(t *testing.T) {
+	data, err := os.ReadFile("fixtures/addf8-GSM-GW.wav")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dec := NewDecoder(bytes.NewReader(data))
+	buf := &audio.Float32Buffer{Data: make([]float32, 1000)}
+
+	var last float64
+
+	for {
+		n, err := dec.PCMBuffer(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		progress := dec.Progress()
+		if progress < last {
+			t.Fatalf("progress went back from %v to %v", last, progress)
+		}
+
+		last = progress
+
+		if n == 0 {
+			break
+		}
+
+		want := float64(dec.gsmDecoded) / float64(dec.CompressedSamples)
+		if math.Abs(progress-want) > 1e-9 {
+			t.Fatalf("expected progress %v from the fact count, got %v", want, progress)
+		}
+	}
+
+	if last != 1 {
+		t.Fatalf("expected full progress at the end, got %v", last)
+	}
+}
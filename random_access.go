@@ -0,0 +1,143 @@
+package wav
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/go-audio/audio"
+)
+
+var (
+	errFrameOutOfRange        = errors.New("frame out of range")
+	errSeekUnsupportedFormat  = errors.New("frame seeking not supported for format")
+	errSeekInvalidBlockLayout = errors.New("invalid block align")
+)
+
+// NewReaderAtDecoder creates a decoder reading from r, which holds size bytes.
+// All reads are issued through ReadAt, so seeking is free: SeekToFrame and
+// Duration don't read any of the data they skip. This suits object storage
+// accessed via range requests.
+func NewReaderAtDecoder(r io.ReaderAt, size int64) *Decoder {
+	return NewDecoder(io.NewSectionReader(r, 0, size))
+}
+
+// SeekToFrame positions the decoder at the given frame of the data chunk so
+// that the next PCMBuffer call starts there. It forwards to the PCM chunk if
+// needed. Seeking requires a fixed block size, so it isn't supported for GSM
+// and other compressed formats.
+func (d *Decoder) SeekToFrame(frame int64) error {
+	if d == nil {
+		return ErrPCMDataNotFound
+	}
+
+	if !d.pcmDataAccessed {
+		err := d.FwdToPCM()
+		if err != nil {
+			return d.err
+		}
+	}
+
+	if d.PCMChunk == nil {
+		return ErrPCMChunkNotFound
+	}
+
+	if d.WavAudioFormat == wavFormatGSM610 || isUnsupportedCompressedFormat(d.WavAudioFormat) {
+		return fmt.Errorf("%w: %d", errSeekUnsupportedFormat, d.WavAudioFormat)
+	}
+
+	blockAlign := d.frameSize()
+	if blockAlign <= 0 {
+		return errSeekInvalidBlockLayout
+	}
+
+	numFrames := int64(d.PCMSize) / blockAlign
+	if frame < 0 || frame > numFrames {
+		return fmt.Errorf("%w: %d (%d frames)", errFrameOutOfRange, frame, numFrames)
+	}
+
+	err := d.seekToDataOffset(frame * blockAlign)
+	if err != nil {
+		return fmt.Errorf("failed to seek to frame %d: %w", frame, err)
+	}
+
+	return nil
+}
+
+// seekToDataOffset positions the decoder at a byte offset into the data
+// chunk, which needn't fall on a frame boundary.
+func (d *Decoder) seekToDataOffset(offset int64) error {
+	_, err := d.r.Seek(d.pcmStart+offset, io.SeekStart)
+	if err != nil {
+		return err
+	}
+
+	if d.dataSegments != nil {
+		d.PCMChunk.R = newSegmentReader(d.r, d.dataSegments, offset)
+	} else {
+		d.PCMChunk.R = newDataChunkReader(d.r, int64(d.PCMSize)-offset, d.PCMSize%2 == 1)
+	}
+	d.PCMChunk.Pos = int(offset)
+	d.pcmConsumed = offset
+	d.pcmDone = false
+	d.err = nil
+
+	return nil
+}
+
+// FrameAt decodes the single frame at the given index of the data chunk and
+// returns one sample per channel, e.g. to sample every Nth frame for a
+// preview. The read position of PCMBuffer is restored afterwards. Like
+// SeekToFrame, it isn't supported for GSM and other compressed formats.
+func (d *Decoder) FrameAt(frame int64) ([]float32, error) {
+	if d == nil {
+		return nil, ErrPCMDataNotFound
+	}
+
+	if !d.pcmDataAccessed {
+		err := d.FwdToPCM()
+		if err != nil {
+			return nil, d.err
+		}
+	}
+
+	prevConsumed, prevDone, prevClips := d.pcmConsumed, d.pcmDone, d.clipCount
+
+	err := d.SeekToFrame(frame)
+	if err != nil {
+		return nil, err
+	}
+
+	numChans := max(int(d.NumChans), 1)
+	buf := &audio.Float32Buffer{Data: make([]float32, numChans)}
+
+	n, err := d.pcmBuffer(buf)
+	if err == nil && n < numChans {
+		err = fmt.Errorf("%w: %d", errFrameOutOfRange, frame)
+	}
+
+	// FrameAt doesn't count as decoding, restore the stream state
+	d.clipCount = prevClips
+
+	restoreErr := d.seekToDataOffset(prevConsumed)
+	if restoreErr != nil && err == nil {
+		err = fmt.Errorf("failed to restore the read position: %w", restoreErr)
+	}
+
+	d.pcmDone = prevDone
+
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Data, nil
+}
+
+// frameSize returns the number of bytes per frame of the data chunk.
+func (d *Decoder) frameSize() int64 {
+	if d.FmtChunk != nil && d.FmtChunk.BlockAlign > 0 {
+		return int64(d.FmtChunk.BlockAlign)
+	}
+
+	return int64(d.NumChans) * int64(bytesPerSample(d.containerBitDepth()))
+}
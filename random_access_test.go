@@ -0,0 +1,251 @@
+package wav
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-audio/audio"
+)
+
+// trackingReaderAt records the byte ranges requested through ReadAt.
+type trackingReaderAt struct {
+	r         io.ReaderAt
+	bytesRead int64
+}
+
+func (t *trackingReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	n, err := t.r.ReadAt(p, off)
+	t.bytesRead += int64(n)
+
+	return n, err
+}
+
+func TestNewReaderAtDecoder_SeekToFrame(t *testing.T) {
+	file, err := os.Open("fixtures/kick.wav")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	full, err := NewDecoder(file).FullPCMBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tracker := &trackingReaderAt{r: file}
+	d := NewReaderAtDecoder(tracker, info.Size())
+
+	const frame = 1000
+
+	err = d.SeekToFrame(frame)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if tracker.bytesRead >= int64(d.PCMSize) {
+		t.Fatalf("seeking read %d bytes, expected the skipped data to stay unread", tracker.bytesRead)
+	}
+
+	numChans := int(d.NumChans)
+	buf := &audio.Float32Buffer{Data: make([]float32, 64*numChans)}
+
+	n, err := d.PCMBuffer(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n != len(buf.Data) {
+		t.Fatalf("expected %d samples, got %d", len(buf.Data), n)
+	}
+
+	want := full.Data[frame*numChans : frame*numChans+n]
+	for i := range want {
+		if buf.Data[i] != want[i] {
+			t.Fatalf("sample %d mismatch: got %f, want %f", i, buf.Data[i], want[i])
+		}
+	}
+
+	// Seeking backwards must work too.
+	err = d.SeekToFrame(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	n, err = d.PCMBuffer(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < n; i++ {
+		if buf.Data[i] != full.Data[i] {
+			t.Fatalf("sample %d mismatch after rewind: got %f, want %f", i, buf.Data[i], full.Data[i])
+		}
+	}
+}
+
+func TestNewReaderAtDecoder_Duration(t *testing.T) {
+	file, err := os.Open("fixtures/kick.wav")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dur, err := NewReaderAtDecoder(file, info.Size()).Duration()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := 204172335 * time.Nanosecond; dur != want {
+		t.Fatalf("expected duration to be: %s but was %s", want, dur)
+	}
+}
+
+func TestDecoder_SeekToFrame_Errors(t *testing.T) {
+	file, err := os.Open("fixtures/kick.wav")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	d := NewDecoder(file)
+
+	err = d.SeekToFrame(-1)
+	if !errors.Is(err, errFrameOutOfRange) {
+		t.Fatalf("expected errFrameOutOfRange for negative frame, got %v", err)
+	}
+
+	err = d.SeekToFrame(int64(d.PCMSize) + 1)
+	if !errors.Is(err, errFrameOutOfRange) {
+		t.Fatalf("expected errFrameOutOfRange past the end, got %v", err)
+	}
+
+	gsmFile, err := os.Open("fixtures/addf8-GSM-GW.wav")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer gsmFile.Close()
+
+	err = NewDecoder(gsmFile).SeekToFrame(0)
+	if !errors.Is(err, errSeekUnsupportedFormat) {
+		t.Fatalf("expected errSeekUnsupportedFormat for GSM, got %v", err)
+	}
+
+	var nilDecoder *Decoder
+	if err := nilDecoder.SeekToFrame(0); !errors.Is(err, ErrPCMDataNotFound) {
+		t.Fatalf("expected ErrPCMDataNotFound for nil decoder, got %v", err)
+	}
+}
+
+func TestDecoder_FrameAt(t *testing.T) {
+	data, err := os.ReadFile("fixtures/kick.wav")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	full, err := NewDecoder(bytes.NewReader(data)).FullPCMBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := NewDecoder(bytes.NewReader(data))
+
+	numChans := int(full.Format.NumChannels)
+	numFrames := int64(len(full.Data) / numChans)
+
+	for _, frame := range []int64{0, 1000, numFrames - 1} {
+		got, err := d.FrameAt(frame)
+		if err != nil {
+			t.Fatalf("frame %d: %v", frame, err)
+		}
+
+		assertFloat32SlicesClose(t, got, full.Data[frame*int64(numChans):(frame+1)*int64(numChans)], 0)
+	}
+
+	// the streaming position survives a FrameAt call
+	buf := &audio.Float32Buffer{Data: make([]float32, 64*numChans)}
+
+	_, err = d.PCMBuffer(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = d.FrameAt(numFrames / 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := d.PCMBuffer(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertFloat32SlicesClose(t, buf.Data[:n], full.Data[64*numChans:64*numChans+n], 0)
+
+	// a position inside a frame is restored to the byte
+	stereo := make([]float32, 32)
+	for i := range stereo {
+		stereo[i] = float32(i) / 64
+	}
+
+	enc := NewMemoryEncoder(8000, 16, 2, wavFormatPCM)
+
+	err = enc.Write(&audio.Float32Buffer{Format: &audio.Format{NumChannels: 2, SampleRate: 8000}, Data: stereo})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encoded, err := enc.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sd := NewDecoder(bytes.NewReader(encoded))
+	partial := &audio.Float32Buffer{Data: make([]float32, 3)}
+
+	_, err = sd.PCMBuffer(partial)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = sd.FrameAt(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	n, err = sd.PCMBuffer(partial)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertFloat32SlicesClose(t, partial.Data[:n], stereo[3:6], 1e-4)
+
+	_, err = d.FrameAt(numFrames)
+	if !errors.Is(err, errFrameOutOfRange) {
+		t.Fatalf("expected errFrameOutOfRange past the last frame, got %v", err)
+	}
+
+	gsmFile, err := os.Open("fixtures/addf8-GSM-GW.wav")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer gsmFile.Close()
+
+	_, err = NewDecoder(gsmFile).FrameAt(0)
+	if !errors.Is(err, errSeekUnsupportedFormat) {
+		t.Fatalf("expected errSeekUnsupportedFormat for GSM, got %v", err)
+	}
+}
@@ -0,0 +1,48 @@
+package wav
+
+import (
+	"encoding/binary"
+	"io"
+)
+
+// RawPCMDecoder returns a decoder for headerless audio, such as raw telephony
+// recordings or files that lost their header. All of r is read as the data
+// chunk of a WAV file with the given sample rate, bit depth, channel count
+// and format tag, e.g. 1 for PCM, 3 for IEEE float, 6 for A-law and 7 for
+// mu-law, so streaming and the other decoding methods work as for a WAV file.
+// Samples are little endian and 8-bit PCM is unsigned, as in WAV files.
+//
+// If r can seek, the bytes from its current position to its end are the
+// data chunk and Rewind and random access are supported. Any other reader is
+// read until it ends; the data chunk size is unknown then, so methods
+// relying on it, like Duration, don't apply.
+func RawPCMDecoder(r io.Reader, sampleRate, bitDepth, numChans, format int) *Decoder {
+	fmtChunk := rawFmtChunk(sampleRate, bitDepth, numChans, format)
+
+	if rs, ok := r.(io.ReadSeeker); ok {
+		start, err := rs.Seek(0, io.SeekCurrent)
+		if err == nil {
+			end, err := rs.Seek(0, io.SeekEnd)
+			if err == nil {
+				return NewDecoder(newVirtualWAVReader(rs, fmtChunk, []dataSegment{{start: start, size: end - start}}))
+			}
+		}
+	}
+
+	return NewDecoder(newStreamWAVReader(r, fmtChunk))
+}
+
+// rawFmtChunk returns the payload of a fmt chunk describing the raw samples.
+func rawFmtChunk(sampleRate, bitDepth, numChans, format int) []byte {
+	blockAlign := numChans * ((bitDepth + 7) / 8)
+
+	fmtChunk := make([]byte, 16)
+	binary.LittleEndian.PutUint16(fmtChunk[0:], uint16(format))
+	binary.LittleEndian.PutUint16(fmtChunk[2:], uint16(numChans))
+	binary.LittleEndian.PutUint32(fmtChunk[4:], uint32(sampleRate))
+	binary.LittleEndian.PutUint32(fmtChunk[8:], uint32(sampleRate*blockAlign))
+	binary.LittleEndian.PutUint16(fmtChunk[12:], uint16(blockAlign))
+	binary.LittleEndian.PutUint16(fmtChunk[14:], uint16(bitDepth))
+
+	return fmtChunk
+}
@@ -0,0 +1,125 @@
+package wav
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/go-audio/audio"
+)
+
+func TestRawPCMDecoder(t *testing.T) {
+	frames := [][]float32{{0.5, -0.5}, {0.25, -0.25}, {0, 0.125}, {-1, 0.75}, {0.5, 0}}
+
+	testCases := []struct {
+		name     string
+		bitDepth int
+		format   int
+	}{
+		{name: "pcm8", bitDepth: 8, format: wavFormatPCM},
+		{name: "pcm16", bitDepth: 16, format: wavFormatPCM},
+		{name: "pcm24", bitDepth: 24, format: wavFormatPCM},
+		{name: "float32", bitDepth: 32, format: wavFormatIEEEFloat},
+		{name: "alaw", bitDepth: 8, format: wavFormatALaw},
+		{name: "mulaw", bitDepth: 8, format: wavFormatMuLaw},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			enc := NewMemoryEncoder(8000, testCase.bitDepth, 2, testCase.format)
+
+			err := enc.WriteFrames(frames)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			file, err := enc.Close()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			want, err := NewDecoder(bytes.NewReader(file)).FullPCMBuffer()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// the samples of the data chunk, which ends the file
+			raw := file[bytes.Index(file, []byte("data"))+8:]
+
+			dec := RawPCMDecoder(bytes.NewReader(raw), 8000, testCase.bitDepth, 2, testCase.format)
+
+			buf, err := dec.FullPCMBuffer()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assertFloat32SlicesClose(t, buf.Data, want.Data, 0)
+
+			if dec.SampleRate != 8000 || dec.NumChans != 2 || dec.PCMLen() != int64(len(raw)) {
+				t.Fatalf("unexpected format %d Hz, %d channels, %d bytes", dec.SampleRate, dec.NumChans, dec.PCMLen())
+			}
+
+			sample, err := dec.FrameAt(3)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assertFloat32SlicesClose(t, sample, want.Data[6:8], 0)
+
+			// a reader that can't seek is decoded as a stream
+			stream := RawPCMDecoder(io.MultiReader(bytes.NewReader(raw)), 8000, testCase.bitDepth, 2, testCase.format)
+
+			chunk := &audio.Float32Buffer{Data: make([]float32, 4)}
+
+			var got []float32
+
+			for {
+				n, err := stream.PCMBuffer(chunk)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if n == 0 {
+					break
+				}
+
+				got = append(got, chunk.Data[:n]...)
+			}
+
+			assertFloat32SlicesClose(t, got, want.Data, 0)
+		})
+	}
+}
+
+func TestRawPCMDecoderStartsAtReaderPosition(t *testing.T) {
+	raw := []byte{0xFF, 0xFF, 0x00, 0x40, 0x00, 0xC0}
+
+	r := bytes.NewReader(raw)
+
+	// skip a two byte prefix such as a custom header
+	_, err := r.Seek(2, io.SeekStart)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dec := RawPCMDecoder(r, 8000, 16, 1, wavFormatPCM)
+
+	buf, err := dec.FullPCMBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertFloat32SlicesClose(t, buf.Data, []float32{0.5, -0.5}, 0)
+
+	err = dec.Rewind()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf, err = dec.FullPCMBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertFloat32SlicesClose(t, buf.Data, []float32{0.5, -0.5}, 0)
+}
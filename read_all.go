@@ -0,0 +1,201 @@
+package wav
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/go-audio/audio"
+	"github.com/go-audio/riff"
+)
+
+// ReadAll decodes the samples like FullPCMBuffer and the metadata like
+// ReadMetadata in a single forward pass over the file, so metadata chunks
+// following the data chunk are found without a rewind. Unknown chunks are
+// collected in UnknownChunks. The returned metadata is nil when the file has
+// none. If the samples were accessed before, the file is read again from the
+// start, which needs a seekable reader.
+func (d *Decoder) ReadAll() (*audio.Float32Buffer, *Metadata, error) {
+	if d == nil {
+		return nil, nil, ErrPCMDataNotFound
+	}
+
+	if d.pcmDataAccessed {
+		_, err := d.r.Seek(0, io.SeekStart)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to seek back to the start %w", err)
+		}
+
+		d.resetParser()
+	}
+
+	d.ReadInfo()
+
+	err := d.Err()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	d.UnknownChunks = nil
+	d.unknownChunkOrder = 0
+
+	var buf *audio.Float32Buffer
+
+	for {
+		chunk, err := d.NextChunk()
+		if err != nil {
+			break
+		}
+
+		d.unknownChunkOrder++
+
+		switch {
+		case chunk.ID == riff.FmtID:
+			chunk.Drain()
+		case chunk.ID == riff.DataFormatID:
+			if d.OnChunk != nil {
+				d.OnChunk(chunk.ID, d.declaredChunkSize(chunk), nil)
+			}
+
+			// only the first data chunk is decoded, like FullPCMBuffer does
+			if buf != nil {
+				d.skipChunk(chunk)
+
+				continue
+			}
+
+			// startPCM sets the chunk size to the length of the joined
+			// segments
+			dataSize := int64(chunk.Size)
+
+			err = d.startPCM(chunk)
+			if err != nil {
+				d.err = err
+
+				return nil, d.Metadata, err
+			}
+
+			buf, err = d.readAllSamples()
+			if err != nil {
+				return buf, d.Metadata, err
+			}
+
+			if d.dataSegments != nil {
+				// the segments were read out of order, continue after the
+				// data chunk
+				_, err = d.r.Seek(d.pcmStart+dataSize, io.SeekStart)
+				if err != nil {
+					return buf, d.Metadata, fmt.Errorf("failed to skip the data chunk: %w", err)
+				}
+			}
+		case chunk.ID == CIDSlnt:
+			if d.OnChunk != nil {
+				err = d.notifyChunk(chunk)
+				if err != nil {
+					d.err = err
+
+					return buf, d.Metadata, err
+				}
+			}
+
+			// slnt chunks after the data chunk were decoded with it
+			if buf != nil {
+				d.skipChunk(chunk)
+
+				continue
+			}
+
+			err = d.readLeadingSilence(chunk)
+			if err != nil && d.handleChunkError(chunk, err) {
+				return nil, d.Metadata, d.err
+			}
+		case chunk.ID == CIDList && buf == nil:
+			waveList, err := d.startWaveList(chunk)
+			if err != nil {
+				d.err = err
+
+				return nil, d.Metadata, err
+			}
+
+			if !waveList {
+				err = d.readAllChunk(chunk, true)
+				if err != nil {
+					return nil, d.Metadata, err
+				}
+
+				continue
+			}
+
+			buf, err = d.readAllSamples()
+			if err != nil {
+				return buf, d.Metadata, err
+			}
+
+			// the segments were read out of order, continue after the list
+			_, err = d.r.Seek(d.pcmStart-4+int64(chunk.Size), io.SeekStart)
+			if err != nil {
+				return buf, d.Metadata, fmt.Errorf("failed to skip the wave list: %w", err)
+			}
+		default:
+			err = d.readAllChunk(chunk, buf == nil)
+			if err != nil {
+				return buf, d.Metadata, err
+			}
+		}
+	}
+
+	if buf == nil {
+		return nil, d.Metadata, ErrPCMChunkNotFound
+	}
+
+	return buf, d.Metadata, nil
+}
+
+// readAllSamples decodes the data chunk set up by startPCM or
+// startWaveList.
+func (d *Decoder) readAllSamples() (*audio.Float32Buffer, error) {
+	d.pcmDataAccessed = true
+
+	buf, err := d.FullPCMBuffer()
+	if err != nil {
+		return buf, fmt.Errorf("failed to decode the data chunk: %w", err)
+	}
+
+	// a decoder may stop before the end of the chunk, e.g. at the fact
+	// chunk sample count
+	_, err = io.Copy(io.Discard, d.PCMChunk.R)
+	if err != nil {
+		return buf, fmt.Errorf("failed to skip the rest of the data chunk: %w", err)
+	}
+
+	return buf, nil
+}
+
+// readAllChunk decodes a chunk other than fmt and data, capturing it as an
+// unknown chunk when no handler is registered. An error is only returned in
+// strict mode.
+func (d *Decoder) readAllChunk(chunk *riff.Chunk, beforeData bool) error {
+	if d.OnChunk != nil {
+		err := d.notifyChunk(chunk)
+		if err != nil {
+			d.err = err
+
+			return err
+		}
+	}
+
+	handled, err := d.decodeChunkViaRegistry(chunk)
+	if err != nil && !errors.Is(err, io.EOF) {
+		if d.handleChunkError(chunk, err) {
+			return d.err
+		}
+
+		return nil
+	}
+
+	if !handled {
+		d.captureUnknownChunk(chunk, beforeData)
+	}
+
+	return nil
+}
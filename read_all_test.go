@@ -0,0 +1,124 @@
+package wav
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"reflect"
+	"testing"
+)
+
+// forwardOnlyReader fails to seek, like a stream.
+type forwardOnlyReader struct {
+	io.Reader
+}
+
+func (f *forwardOnlyReader) Seek(int64, int) (int64, error) {
+	return 0, errors.New("seek not supported")
+}
+
+func TestDecoder_ReadAll(t *testing.T) {
+	for _, path := range []string{
+		"fixtures/listinfo.wav",
+		"fixtures/bwf.wav",
+		"fixtures/Pmiscck.wav",
+		"fixtures/trailing-padded-chunks.wav",
+		"fixtures/addf8-GSM-GW.wav",
+		"fixtures/M1F1-uint8-AFsp.wav",
+	} {
+		t.Run(path, func(t *testing.T) {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			want, err := NewDecoder(bytes.NewReader(data)).FullPCMBuffer()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			dec := NewDecoder(bytes.NewReader(data))
+			dec.ReadMetadata()
+
+			if err := dec.Err(); err != nil {
+				t.Fatal(err)
+			}
+
+			single := NewDecoder(bytes.NewReader(data))
+
+			buf, metadata, err := single.ReadAll()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assertFloat32SlicesClose(t, buf.Data, want.Data, 0)
+
+			if !reflect.DeepEqual(metadata, dec.Metadata) {
+				t.Fatalf("metadata mismatch:\n got: %#v\nwant: %#v", metadata, dec.Metadata)
+			}
+
+			if !reflect.DeepEqual(single.UnknownChunks, dec.UnknownChunks) {
+				t.Fatalf("unknown chunks mismatch:\n got: %v\nwant: %v", single.UnknownChunks, dec.UnknownChunks)
+			}
+
+			// a second call reads the file again from the start
+			buf, metadata, err = single.ReadAll()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assertFloat32SlicesClose(t, buf.Data, want.Data, 0)
+
+			if !reflect.DeepEqual(metadata, dec.Metadata) {
+				t.Fatalf("metadata mismatch after reading again:\n got: %#v\nwant: %#v", metadata, dec.Metadata)
+			}
+
+			// without seeking, chunks before the fmt chunk are decoded but
+			// not revisited, so only the samples and metadata are compared
+			buf, metadata, err = NewDecoder(&forwardOnlyReader{bytes.NewReader(data)}).ReadAll()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assertFloat32SlicesClose(t, buf.Data, want.Data, 0)
+
+			if !reflect.DeepEqual(metadata, dec.Metadata) {
+				t.Fatalf("metadata mismatch without seeking:\n got: %#v\nwant: %#v", metadata, dec.Metadata)
+			}
+		})
+	}
+}
+
+func TestDecoder_ReadAllTrailingMetadata(t *testing.T) {
+	enc := NewMemoryEncoder(8000, 16, 1, wavFormatPCM)
+	enc.Metadata = &Metadata{Title: "after the data", Artist: "tester"}
+
+	err := enc.WriteSilence(100)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := enc.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf, metadata, err := NewDecoder(&forwardOnlyReader{bytes.NewReader(data)}).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(buf.Data) != 100 {
+		t.Fatalf("expected 100 samples, got %d", len(buf.Data))
+	}
+
+	if metadata == nil || metadata.Title != "after the data" || metadata.Artist != "tester" {
+		t.Fatalf("expected the trailing INFO chunk, got %+v", metadata)
+	}
+
+	_, _, err = NewDecoder(bytes.NewReader(data[:36])).ReadAll()
+	if !errors.Is(err, ErrPCMChunkNotFound) {
+		t.Fatalf("expected ErrPCMChunkNotFound, got %v", err)
+	}
+}
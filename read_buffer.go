@@ -0,0 +1,39 @@
+package wav
+
+const (
+	// DefaultReadBufferSize is the read granularity in bytes used when
+	// SetReadBufferSize wasn't called.
+	DefaultReadBufferSize = 16 * 1024
+	// MinReadBufferSize and MaxReadBufferSize bound SetReadBufferSize.
+	MinReadBufferSize = 512
+	MaxReadBufferSize = 16 * 1024 * 1024
+)
+
+// SetReadBufferSize sets the size in bytes of the blocks FullPCMBuffer reads
+// from the data chunk. Larger reads mean fewer calls into the underlying
+// reader, which helps throughput on fast storage, at the cost of memory. The
+// size is clamped to [MinReadBufferSize, MaxReadBufferSize]; n <= 0 restores
+// DefaultReadBufferSize. PCMBuffer reads are sized by the passed buffer
+// instead.
+func (d *Decoder) SetReadBufferSize(n int) {
+	if d == nil {
+		return
+	}
+
+	if n <= 0 {
+		d.readBufSize = 0
+
+		return
+	}
+
+	d.readBufSize = min(max(n, MinReadBufferSize), MaxReadBufferSize)
+}
+
+// readBufferSize returns the configured read granularity in bytes.
+func (d *Decoder) readBufferSize() int {
+	if d.readBufSize == 0 {
+		return DefaultReadBufferSize
+	}
+
+	return d.readBufSize
+}
@@ -0,0 +1,72 @@
+package wav
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"testing"
+)
+
+type readCallCounter struct {
+	io.ReadSeeker
+	calls int
+}
+
+func (c *readCallCounter) Read(p []byte) (int, error) {
+	c.calls++
+
+	return c.ReadSeeker.Read(p)
+}
+
+func TestDecoder_SetReadBufferSize(t *testing.T) {
+	data, err := os.ReadFile("fixtures/kick.wav")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decode := func(size int) ([]float32, int) {
+		t.Helper()
+
+		r := &readCallCounter{ReadSeeker: bytes.NewReader(data)}
+		dec := NewDecoder(r)
+		dec.SetReadBufferSize(size)
+
+		buf, err := dec.FullPCMBuffer()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return buf.Data, r.calls
+	}
+
+	small, smallCalls := decode(1)
+	def, defCalls := decode(0)
+	large, largeCalls := decode(1 << 20)
+
+	assertFloat32SlicesClose(t, small, def, 0)
+	assertFloat32SlicesClose(t, large, def, 0)
+
+	if smallCalls <= defCalls || largeCalls > defCalls {
+		t.Fatalf("expected fewer reads with larger buffers, got %d, %d, %d", smallCalls, defCalls, largeCalls)
+	}
+
+	dec := NewDecoder(bytes.NewReader(data))
+
+	dec.SetReadBufferSize(1)
+
+	if dec.readBufferSize() != MinReadBufferSize {
+		t.Fatalf("expected the size to be clamped to %d, got %d", MinReadBufferSize, dec.readBufferSize())
+	}
+
+	dec.SetReadBufferSize(MaxReadBufferSize + 1)
+
+	if dec.readBufferSize() != MaxReadBufferSize {
+		t.Fatalf("expected the size to be clamped to %d, got %d", MaxReadBufferSize, dec.readBufferSize())
+	}
+
+	dec.SetReadBufferSize(-1)
+
+	if dec.readBufferSize() != DefaultReadBufferSize {
+		t.Fatalf("expected the default size, got %d", dec.readBufferSize())
+	}
+}
@@ -0,0 +1,165 @@
+package wav
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/go-audio/audio"
+)
+
+// resampleFilterZeros is the number of zero crossings of the windowed sinc
+// on each side of the FIR used for integer ratio resampling.
+const resampleFilterZeros = 16
+
+// FullPCMBufferAtRate decodes the full data chunk like FullPCMBuffer and
+// converts it to rate. Integer ratios such as 44100 to 88200 are upsampled by
+// zero stuffing and a windowed sinc FIR, unit fractions such as 48000 to
+// 24000 are low pass filtered and decimated; any other ratio falls back to
+// linear interpolation. The returned buffer's Format.SampleRate is rate.
+func (d *Decoder) FullPCMBufferAtRate(rate int) (*audio.Float32Buffer, error) {
+	if rate < 1 {
+		return nil, fmt.Errorf("%w: %d", errInvalidSampleRate, rate)
+	}
+
+	buf, err := d.fullPCMBuffer()
+	if err != nil {
+		return nil, err
+	}
+
+	src := int(d.SampleRate)
+	if src < 1 {
+		return nil, fmt.Errorf("%w: %d", errInvalidSampleRate, src)
+	}
+
+	numChans := max(int(d.NumChans), 1)
+	frames := len(buf.Data) / numChans
+
+	var data []float32
+
+	switch {
+	case rate == src:
+		data = buf.Data
+	case rate%src == 0:
+		data = upsampleFIR(buf.Data, numChans, frames, rate/src)
+	case src%rate == 0:
+		data = decimateFIR(buf.Data, numChans, frames, src/rate)
+	default:
+		data = resampleLinear(buf.Data, numChans, frames, src, rate)
+	}
+
+	if d.SampleOrder == SampleOrderPlanar {
+		deinterleave(data, numChans)
+	}
+
+	return &audio.Float32Buffer{
+		Format:         &audio.Format{NumChannels: numChans, SampleRate: rate},
+		Data:           data,
+		SourceBitDepth: buf.SourceBitDepth,
+	}, nil
+}
+
+// lowPassFIR returns a Blackman windowed sinc low pass with its cutoff at
+// 1/(2*factor) of the sample rate and a DC gain of 1.
+func lowPassFIR(factor int) []float64 {
+	half := resampleFilterZeros * factor
+	taps := make([]float64, 2*half+1)
+	cutoff := 0.5 / float64(factor)
+
+	var sum float64
+
+	for i := range taps {
+		n := float64(i - half)
+
+		h := 2 * cutoff
+		if n != 0 {
+			h = math.Sin(2*math.Pi*cutoff*n) / (math.Pi * n)
+		}
+
+		phase := 2 * math.Pi * float64(i) / float64(len(taps)-1)
+		taps[i] = h * (0.42 - 0.5*math.Cos(phase) + 0.08*math.Cos(2*phase))
+		sum += taps[i]
+	}
+
+	for i := range taps {
+		taps[i] /= sum
+	}
+
+	return taps
+}
+
+// upsampleFIR inserts factor-1 zeros after every frame and filters the result,
+// only evaluating the taps that hit input samples.
+func upsampleFIR(data []float32, numChans, frames, factor int) []float32 {
+	taps := lowPassFIR(factor)
+	half := len(taps) / 2
+	out := make([]float32, frames*factor*numChans)
+
+	for m := range frames * factor {
+		// input frames k with |m - k*factor| <= half
+		first := max((m-half+factor-1)/factor, 0)
+		last := min((m+half)/factor, frames-1)
+
+		for ch := range numChans {
+			var acc float64
+			for k := first; k <= last; k++ {
+				acc += float64(data[k*numChans+ch]) * taps[m-k*factor+half]
+			}
+
+			// zero stuffing scales the signal level down by factor
+			out[m*numChans+ch] = float32(acc * float64(factor))
+		}
+	}
+
+	return out
+}
+
+// decimateFIR low pass filters data and keeps every factor-th frame.
+func decimateFIR(data []float32, numChans, frames, factor int) []float32 {
+	taps := lowPassFIR(factor)
+	half := len(taps) / 2
+	outFrames := (frames + factor - 1) / factor
+	out := make([]float32, outFrames*numChans)
+
+	for m := range outFrames {
+		center := m * factor
+		first := max(center-half, 0)
+		last := min(center+half, frames-1)
+
+		for ch := range numChans {
+			var acc float64
+			for n := first; n <= last; n++ {
+				acc += float64(data[n*numChans+ch]) * taps[center-n+half]
+			}
+
+			out[m*numChans+ch] = float32(acc)
+		}
+	}
+
+	return out
+}
+
+// resampleLinear converts data from src to dst by linear interpolation
+// between neighbouring frames.
+func resampleLinear(data []float32, numChans, frames, src, dst int) []float32 {
+	if frames == 0 {
+		return nil
+	}
+
+	outFrames := int((int64(frames)*int64(dst) + int64(src) - 1) / int64(src))
+	out := make([]float32, outFrames*numChans)
+
+	for m := range outFrames {
+		pos := float64(m) * float64(src) / float64(dst)
+		k := int(pos)
+		frac := float32(pos - float64(k))
+		next := min(k+1, frames-1)
+
+		for ch := range numChans {
+			a := data[k*numChans+ch]
+			b := data[next*numChans+ch]
+			out[m*numChans+ch] = a + (b-a)*frac
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,112 @@
+package wav
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/go-audio/audio"
+)
+
+// encodeSine encodes a stereo float file holding a sine of freq Hz on the
+// left channel and its negation on the right.
+func encodeSine(t *testing.T, sampleRate, frames int, freq float64) []byte {
+	t.Helper()
+
+	data := make([]float32, frames*2)
+	for i := range frames {
+		val := float32(0.5 * math.Sin(2*math.Pi*freq*float64(i)/float64(sampleRate)))
+		data[i*2] = val
+		data[i*2+1] = -val
+	}
+
+	enc := NewMemoryEncoder(sampleRate, 32, 2, wavFormatIEEEFloat)
+
+	err := enc.Write(&audio.Float32Buffer{
+		Format: &audio.Format{NumChannels: 2, SampleRate: sampleRate},
+		Data:   data,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := enc.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return out
+}
+
+// assertSine checks that the frames away from the edges hold the sine
+// written by encodeSine at the given rate.
+func assertSine(t *testing.T, buf *audio.Float32Buffer, sampleRate int, freq, tolerance float64) {
+	t.Helper()
+
+	frames := len(buf.Data) / 2
+	for i := frames / 4; i < frames*3/4; i++ {
+		want := 0.5 * math.Sin(2*math.Pi*freq*float64(i)/float64(sampleRate))
+		if math.Abs(float64(buf.Data[i*2])-want) > tolerance || math.Abs(float64(buf.Data[i*2+1])+want) > tolerance {
+			t.Fatalf("frame %d: got %v/%v, want %v/%v", i, buf.Data[i*2], buf.Data[i*2+1], want, -want)
+		}
+	}
+}
+
+func TestDecoder_FullPCMBufferAtRate(t *testing.T) {
+	testCases := []struct {
+		name      string
+		src, dst  int
+		frames    int
+		wantFrame int
+		tolerance float64
+	}{
+		{"upsample", 44100, 88200, 4410, 8820, 1e-3},
+		{"decimate", 48000, 24000, 4800, 2400, 1e-3},
+		{"linear", 44100, 48000, 4410, 4800, 1e-2},
+		{"unchanged", 44100, 44100, 4410, 4410, 1e-6},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dec := NewDecoder(bytes.NewReader(encodeSine(t, tc.src, tc.frames, 1000)))
+
+			buf, err := dec.FullPCMBufferAtRate(tc.dst)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if buf.Format.SampleRate != tc.dst || buf.Format.NumChannels != 2 {
+				t.Fatalf("unexpected format %+v", buf.Format)
+			}
+
+			if buf.NumFrames() != tc.wantFrame {
+				t.Fatalf("expected %d frames, got %d", tc.wantFrame, buf.NumFrames())
+			}
+
+			assertSine(t, buf, tc.dst, 1000, tc.tolerance)
+		})
+	}
+}
+
+func TestDecoder_FullPCMBufferAtRateFiltersAliases(t *testing.T) {
+	// 15 kHz is above the 12 kHz Nyquist frequency of the target rate
+	dec := NewDecoder(bytes.NewReader(encodeSine(t, 48000, 4800, 15000)))
+
+	buf, err := dec.FullPCMBufferAtRate(24000)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	frames := buf.NumFrames()
+	for i := frames / 4; i < frames*3/4; i++ {
+		if math.Abs(float64(buf.Data[i*2])) > 1e-3 {
+			t.Fatalf("frame %d: expected the tone to be filtered out, got %v", i, buf.Data[i*2])
+		}
+	}
+
+	_, err = NewDecoder(bytes.NewReader(encodeSine(t, 8000, 8, 1000))).FullPCMBufferAtRate(0)
+	if !errors.Is(err, errInvalidSampleRate) {
+		t.Fatalf("expected errInvalidSampleRate, got %v", err)
+	}
+}
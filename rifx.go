@@ -0,0 +1,79 @@
+package wav
+
+import (
+	"encoding/binary"
+	"io"
+	"math/bits"
+
+	"github.com/go-audio/riff"
+)
+
+// IsBigEndian reports whether the decoded file is a big-endian RIFX file.
+func (d *Decoder) IsBigEndian() bool {
+	if d == nil {
+		return false
+	}
+
+	return d.bigEndian
+}
+
+// byteOrder returns the byte order used by chunk headers, fmt fields and
+// samples of the current file.
+func (d *Decoder) byteOrder() binary.ByteOrder {
+	if d != nil && d.bigEndian {
+		return binary.BigEndian
+	}
+
+	return binary.LittleEndian
+}
+
+// readChunkHeader reads the next chunk ID and size honoring the file byte
+// order.
+func (d *Decoder) readChunkHeader() ([4]byte, uint32, error) {
+	id, size, err := d.parser.IDnSize()
+	if err != nil {
+		return id, size, err
+	}
+
+	if d.bigEndian {
+		size = bits.ReverseBytes32(size)
+	}
+
+	return id, size, nil
+}
+
+// nextHeaderChunk mirrors riff.Parser.NextChunk but honors the file byte
+// order.
+func (d *Decoder) nextHeaderChunk() (*riff.Chunk, error) {
+	id, size, err := d.readChunkHeader()
+	if err != nil {
+		return nil, err
+	}
+
+	if size%2 == 1 {
+		size++
+	}
+
+	return &riff.Chunk{ID: id, Size: int(size), R: d.r}, nil
+}
+
+// readChunk reads chunk data in the given byte order. riff.Chunk.ReadBE
+// decodes little-endian data, so it can't be used for RIFX files.
+func readChunk(ch *riff.Chunk, order binary.ByteOrder, dst any) error {
+	if order != binary.BigEndian {
+		return ch.ReadLE(dst)
+	}
+
+	if ch.IsFullyRead() {
+		return io.EOF
+	}
+
+	ch.Pos += binary.Size(dst)
+
+	return binary.Read(ch.R, order, dst)
+}
+
+// int24BETo32 converts a big-endian signed 24-bit value to int32.
+func int24BETo32(b []byte) int32 {
+	return int32(uint32(b[0])<<24|uint32(b[1])<<16|uint32(b[2])<<8) >> 8
+}
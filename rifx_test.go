@@ -0,0 +1,114 @@
+package wav
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// riffToRIFX converts a little-endian RIFF/WAVE file to its big-endian RIFX
+// equivalent by swapping chunk sizes, fmt fields and PCM samples.
+func riffToRIFX(t *testing.T, data []byte) []byte {
+	t.Helper()
+
+	chunks, err := parseWavChunks(data)
+	if err != nil {
+		t.Fatalf("parse chunks: %v", err)
+	}
+
+	var sampleBytes int
+
+	var b bytes.Buffer
+	b.WriteString("RIFX")
+	_ = binary.Write(&b, binary.BigEndian, binary.LittleEndian.Uint32(data[4:8]))
+	b.WriteString("WAVE")
+
+	for _, ch := range chunks {
+		payload := append([]byte(nil), ch.data...)
+
+		switch ch.id {
+		case "fmt ":
+			sampleBytes = bytesPerSample(int(binary.LittleEndian.Uint16(payload[14:16])))
+			for _, field := range [][2]int{{0, 2}, {2, 4}, {4, 8}, {8, 12}, {12, 14}, {14, 16}} {
+				swapBytes(payload[field[0]:field[1]])
+			}
+		case "data":
+			for i := 0; i+sampleBytes <= len(payload); i += sampleBytes {
+				swapBytes(payload[i : i+sampleBytes])
+			}
+		}
+
+		b.WriteString(ch.id)
+		_ = binary.Write(&b, binary.BigEndian, ch.size)
+		b.Write(payload)
+
+		if ch.size%2 == 1 {
+			b.WriteByte(0)
+		}
+	}
+
+	return b.Bytes()
+}
+
+func swapBytes(b []byte) {
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+}
+
+func TestDecoder_RIFX(t *testing.T) {
+	fixtures := []string{
+		"fixtures/kick.wav",
+		"fixtures/bass.wav",
+		"fixtures/32bit.wav",
+		"fixtures/stereofl.wav",
+		"fixtures/M1F1-float64-AFsp.wav",
+		"fixtures/M1F1-uint8-AFsp.wav",
+	}
+
+	for _, fixture := range fixtures {
+		t.Run(filepath.Base(fixture), func(t *testing.T) {
+			data, err := os.ReadFile(fixture)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			riffDec := NewDecoder(bytes.NewReader(data))
+
+			want, err := riffDec.FullPCMBuffer()
+			if err != nil {
+				t.Fatalf("decode RIFF: %v", err)
+			}
+
+			rifx := riffToRIFX(t, data)
+
+			validDec := NewDecoder(bytes.NewReader(rifx))
+			if !validDec.IsValidFile() {
+				t.Fatal("RIFX file should be valid")
+			}
+
+			rifxDec := NewDecoder(bytes.NewReader(rifx))
+
+			got, err := rifxDec.FullPCMBuffer()
+			if err != nil {
+				t.Fatalf("decode RIFX: %v", err)
+			}
+
+			if !rifxDec.IsBigEndian() || riffDec.IsBigEndian() {
+				t.Fatal("byte order detection mismatch")
+			}
+
+			if rifxDec.SampleRate != riffDec.SampleRate || rifxDec.BitDepth != riffDec.BitDepth || rifxDec.NumChans != riffDec.NumChans {
+				t.Fatalf("format mismatch: RIFX=%v RIFF=%v", rifxDec.FmtChunk, riffDec.FmtChunk)
+			}
+
+			if len(got.Data) != len(want.Data) {
+				t.Fatalf("sample count mismatch: RIFX=%d RIFF=%d", len(got.Data), len(want.Data))
+			}
+
+			assertFloat32SlicesClose(t, got.Data, want.Data, 0)
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package wav
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// SampleEncoding describes how a single sample is stored, independent of the
+// container. It can be derived from a fmt chunk or a decoder and drives
+// conversions between WAV, RIFX and AIFF sample layouts.
+type SampleEncoding struct {
+	// BitDepth is the storage size of a sample in bits.
+	BitDepth int
+	// ValidBits is the number of significant bits, the remaining low bits
+	// are padding. Zero means BitDepth.
+	ValidBits int
+	// Signed reports two's complement integer samples. 8-bit WAV PCM is
+	// unsigned, 8-bit AIFF is signed.
+	Signed bool
+	// Float reports IEEE float samples.
+	Float bool
+	// BigEndian reports big-endian samples as found in RIFX and AIFF files.
+	BigEndian bool
+	// Compressed reports codecs such as G.711 or GSM whose samples can't be
+	// read as plain integers.
+	Compressed bool
+}
+
+// SampleEncoding derives the sample encoding described by the fmt chunk. The
+// fmt chunk doesn't know the container byte order, so BigEndian is false; use
+// Decoder.SampleEncoding for decoded files.
+func (f *FmtChunk) SampleEncoding() SampleEncoding {
+	if f == nil {
+		return SampleEncoding{}
+	}
+
+	enc := SampleEncoding{
+		BitDepth:  f.containerBits(),
+		ValidBits: int(f.BitsPerSample),
+	}
+
+	if f.Extensible != nil && f.Extensible.ValidBitsPerSample > 0 && int(f.Extensible.ValidBitsPerSample) < enc.BitDepth {
+		enc.ValidBits = int(f.Extensible.ValidBitsPerSample)
+	}
+
+	switch f.EffectiveFormatTag() {
+	case wavFormatPCM:
+		enc.Signed = f.BitsPerSample > 8
+	case wavFormatIEEEFloat:
+		enc.Float = true
+		enc.Signed = true
+	default:
+		enc.Compressed = true
+	}
+
+	return enc
+}
+
+// SampleEncoding returns the sample encoding of the decoded file, including
+// the RIFX byte order. The header is read if needed.
+func (d *Decoder) SampleEncoding() SampleEncoding {
+	if d == nil {
+		return SampleEncoding{}
+	}
+
+	if d.FmtChunk == nil {
+		d.ReadInfo()
+	}
+
+	enc := d.FmtChunk.SampleEncoding()
+	enc.BigEndian = d.bigEndian
+
+	return enc
+}
+
+// ByteOrder returns the byte order of multi-byte samples.
+func (e SampleEncoding) ByteOrder() binary.ByteOrder {
+	if e.BigEndian {
+		return binary.BigEndian
+	}
+
+	return binary.LittleEndian
+}
+
+// BytesPerSample returns the storage size of a sample in bytes.
+func (e SampleEncoding) BytesPerSample() int {
+	return (e.BitDepth + 7) / 8
+}
+
+// Quantize converts a float sample in [-1, 1] to the integer code of this
+// encoding, left aligned when ValidBits is below BitDepth and offset for
+// unsigned encodings. Values are clamped. Float and compressed encodings, as
+// well as bit depths outside 8 to 32, return 0.
+func (e SampleEncoding) Quantize(value float32) int {
+	if e.Float || e.Compressed || e.BitDepth < 8 || e.BitDepth > 32 {
+		return 0
+	}
+
+	validBits := e.ValidBits
+	if validBits <= 0 || validBits > e.BitDepth {
+		validBits = e.BitDepth
+	}
+
+	// match the encoder's 8-bit quantization
+	if e.BitDepth == 8 && validBits == 8 {
+		if e.Signed {
+			return int(float32ToPCMInt8(value))
+		}
+
+		return int(float32ToPCMUint8(value))
+	}
+
+	scale := int64(1) << (validBits - 1)
+	sample := int64(math.Round(float64(clampFloat32(value, -1, 1)) * float64(scale)))
+	sample = max(min(sample, scale-1), -scale) << (e.BitDepth - validBits)
+
+	if !e.Signed {
+		sample += int64(1) << (e.BitDepth - 1)
+	}
+
+	return int(sample)
+}
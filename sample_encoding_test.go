@@ -0,0 +1,101 @@
+package wav
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"testing"
+)
+
+func TestFmtChunkSampleEncoding(t *testing.T) {
+	tests := []struct {
+		name string
+		fmt  *FmtChunk
+		want SampleEncoding
+	}{
+		{
+			name: "8-bit PCM is unsigned",
+			fmt:  &FmtChunk{FormatTag: wavFormatPCM, BitsPerSample: 8},
+			want: SampleEncoding{BitDepth: 8, ValidBits: 8},
+		},
+		{
+			name: "16-bit PCM",
+			fmt:  &FmtChunk{FormatTag: wavFormatPCM, BitsPerSample: 16},
+			want: SampleEncoding{BitDepth: 16, ValidBits: 16, Signed: true},
+		},
+		{
+			name: "20 valid bits in 24",
+			fmt: &FmtChunk{
+				FormatTag:     wavFormatExtensible,
+				BitsPerSample: 24,
+				Extensible:    &FmtExtensible{ValidBitsPerSample: 20, SubFormat: makeSubFormatGUID(wavFormatPCM)},
+			},
+			want: SampleEncoding{BitDepth: 24, ValidBits: 20, Signed: true},
+		},
+		{
+			name: "float",
+			fmt:  &FmtChunk{FormatTag: wavFormatIEEEFloat, BitsPerSample: 32},
+			want: SampleEncoding{BitDepth: 32, ValidBits: 32, Signed: true, Float: true},
+		},
+		{
+			name: "A-law",
+			fmt:  &FmtChunk{FormatTag: wavFormatALaw, BitsPerSample: 8},
+			want: SampleEncoding{BitDepth: 8, ValidBits: 8, Compressed: true},
+		},
+		{
+			name: "nil",
+			want: SampleEncoding{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.fmt.SampleEncoding(); got != tt.want {
+				t.Fatalf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecoderSampleEncodingByteOrder(t *testing.T) {
+	data, err := os.ReadFile("fixtures/kick.wav")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	enc := NewDecoder(bytes.NewReader(data)).SampleEncoding()
+	if enc.BigEndian || enc.ByteOrder() != binary.LittleEndian || enc.BytesPerSample() != 2 {
+		t.Fatalf("unexpected RIFF encoding %+v", enc)
+	}
+
+	enc = NewDecoder(bytes.NewReader(riffToRIFX(t, data))).SampleEncoding()
+	if !enc.BigEndian || enc.ByteOrder() != binary.BigEndian || !enc.Signed {
+		t.Fatalf("unexpected RIFX encoding %+v", enc)
+	}
+}
+
+func TestSampleEncodingQuantize(t *testing.T) {
+	tests := []struct {
+		name  string
+		enc   SampleEncoding
+		value float32
+		want  int
+	}{
+		{"unsigned 8-bit center", SampleEncoding{BitDepth: 8}, 0, 128},
+		{"unsigned 8-bit max", SampleEncoding{BitDepth: 8}, 1, 255},
+		{"signed 8-bit min", SampleEncoding{BitDepth: 8, Signed: true}, -1, -128},
+		{"16-bit clamped", SampleEncoding{BitDepth: 16, Signed: true}, 2, 32767},
+		{"24-bit half", SampleEncoding{BitDepth: 24, Signed: true}, 0.5, 4194304},
+		{"20 valid bits left aligned", SampleEncoding{BitDepth: 24, ValidBits: 20, Signed: true}, 1, 524287 << 4},
+		{"float", SampleEncoding{BitDepth: 32, Float: true, Signed: true}, 0.5, 0},
+		{"compressed", SampleEncoding{BitDepth: 8, Compressed: true}, 0.5, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.enc.Quantize(tt.value); got != tt.want {
+				t.Fatalf("Quantize(%f)=%d, want %d", tt.value, got, tt.want)
+			}
+		})
+	}
+}
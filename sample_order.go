@@ -0,0 +1,127 @@
+package wav
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/go-audio/audio"
+)
+
+// SampleOrder describes how the samples of multi-channel audio are laid out
+// in memory.
+type SampleOrder int
+
+const (
+	// SampleOrderInterleaved stores frames one after another, each holding
+	// one sample per channel (default).
+	SampleOrderInterleaved SampleOrder = iota
+	// SampleOrderPlanar stores all samples of a channel one after another,
+	// followed by the next channel, as produced by concatenating the
+	// channel slices filled by Decoder.PlanarBuffer or by a Decoder with
+	// this SampleOrder.
+	SampleOrderPlanar
+)
+
+var errPlanarLengthMismatch = errors.New("planar channels must have equal lengths")
+
+// String returns the name of the sample order.
+func (o SampleOrder) String() string {
+	switch o {
+	case SampleOrderInterleaved:
+		return "interleaved"
+	case SampleOrderPlanar:
+		return "planar"
+	default:
+		return fmt.Sprintf("SampleOrder(%d)", int(o))
+	}
+}
+
+// WritePlanar writes one slice of samples per channel, for instance the
+// slices filled by Decoder.PlanarBuffer, without interleaving them first.
+// len(channels) must match NumChans and all channels must have the same
+// length. Normalize and the channel routing apply as for Write.
+func (e *Encoder) WritePlanar(channels [][]float32) error {
+	if len(channels) != e.NumChans {
+		return fmt.Errorf("%w: got %d channels for %d", errFrameChannelMismatch, len(channels), e.NumChans)
+	}
+
+	for ch := range channels {
+		if len(channels[ch]) != len(channels[0]) {
+			return fmt.Errorf("%w: channel %d has %d samples, channel 0 has %d", errPlanarLengthMismatch, ch, len(channels[ch]), len(channels[0]))
+		}
+	}
+
+	if len(channels) == 0 || len(channels[0]) == 0 {
+		return nil
+	}
+
+	err := e.startPCMChunk()
+	if err != nil {
+		return err
+	}
+
+	// normalization measures interleaved frames, it is the rare path
+	if e.Normalize != NormalizeOff {
+		return e.addBuffer(e.normalizedCopy(interleavePlanar(channels, e.SampleRate)))
+	}
+
+	return e.addFrames(len(channels[0]), len(channels), func(frame, ch int) float64 {
+		return float64(channels[ch][frame])
+	})
+}
+
+// planarChannels splits planar buffer data into per-channel slices without
+// copying.
+func planarChannels(buf *audio.Float32Buffer) ([][]float32, error) {
+	numChans := buf.Format.NumChannels
+	if numChans < 1 {
+		return nil, fmt.Errorf("%w: %d", errInvalidChannelCount, numChans)
+	}
+
+	if len(buf.Data)%numChans != 0 {
+		return nil, fmt.Errorf("%w: %d samples for %d channels", errPlanarLengthMismatch, len(buf.Data), numChans)
+	}
+
+	frames := len(buf.Data) / numChans
+	channels := make([][]float32, numChans)
+
+	for ch := range channels {
+		channels[ch] = buf.Data[ch*frames : (ch+1)*frames]
+	}
+
+	return channels, nil
+}
+
+// deinterleave rearranges interleaved samples into planar order in place.
+// samples must hold whole frames.
+func deinterleave(samples []float32, numChans int) {
+	if numChans < 2 {
+		return
+	}
+
+	frames := len(samples) / numChans
+	planar := make([]float32, len(samples))
+
+	for i, val := range samples {
+		planar[(i%numChans)*frames+i/numChans] = val
+	}
+
+	copy(samples, planar)
+}
+
+// interleavePlanar returns an interleaved copy of planar channels.
+func interleavePlanar(channels [][]float32, sampleRate int) *audio.Float32Buffer {
+	numChans := len(channels)
+	buf := &audio.Float32Buffer{
+		Data:   make([]float32, len(channels[0])*numChans),
+		Format: &audio.Format{NumChannels: numChans, SampleRate: sampleRate},
+	}
+
+	for ch, samples := range channels {
+		for i, val := range samples {
+			buf.Data[i*numChans+ch] = val
+		}
+	}
+
+	return buf
+}
@@ -0,0 +1,145 @@
+package wav
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/go-audio/audio"
+)
+
+func TestEncoder_PlanarInput(t *testing.T) {
+	interleaved := []float32{0.5, -0.5, 0.25, -0.25, 0.125, -0.125}
+	planar := [][]float32{{0.5, 0.25, 0.125}, {-0.5, -0.25, -0.125}}
+
+	encode := func(normalize NormalizeMode, write func(enc *Encoder) error) []byte {
+		t.Helper()
+
+		enc := NewMemoryEncoder(8000, 16, 2, wavFormatPCM)
+		enc.Normalize = normalize
+		enc.SwapChannels = [][2]int{{0, 1}}
+
+		err := write(enc.Encoder)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		data, err := enc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return data
+	}
+
+	for _, normalize := range []NormalizeMode{NormalizeOff, NormalizePeak} {
+		want := encode(normalize, func(enc *Encoder) error {
+			return enc.Write(&audio.Float32Buffer{
+				Format: &audio.Format{NumChannels: 2, SampleRate: 8000},
+				Data:   interleaved,
+			})
+		})
+
+		got := encode(normalize, func(enc *Encoder) error {
+			return enc.WritePlanar(planar)
+		})
+		if !bytes.Equal(got, want) {
+			t.Fatalf("normalize %d: WritePlanar output differs from interleaved Write", normalize)
+		}
+
+		got = encode(normalize, func(enc *Encoder) error {
+			enc.SampleOrder = SampleOrderPlanar
+
+			return enc.Write(&audio.Float32Buffer{
+				Format: &audio.Format{NumChannels: 2, SampleRate: 8000},
+				Data:   append(append([]float32(nil), planar[0]...), planar[1]...),
+			})
+		})
+		if !bytes.Equal(got, want) {
+			t.Fatalf("normalize %d: planar Write output differs from interleaved Write", normalize)
+		}
+	}
+
+	enc := NewMemoryEncoder(8000, 16, 2, wavFormatPCM)
+
+	err := enc.WritePlanar([][]float32{{0.5, 0.25}, {0.5}})
+	if !errors.Is(err, errPlanarLengthMismatch) {
+		t.Fatalf("expected errPlanarLengthMismatch, got %v", err)
+	}
+
+	err = enc.WritePlanar([][]float32{{0.5}})
+	if !errors.Is(err, errFrameChannelMismatch) {
+		t.Fatalf("expected errFrameChannelMismatch, got %v", err)
+	}
+
+	if SampleOrderPlanar.String() != "planar" {
+		t.Fatalf("unexpected name %q", SampleOrderPlanar)
+	}
+}
+
+func TestDecoder_PlanarOutput(t *testing.T) {
+	data, err := os.ReadFile("fixtures/stereol.wav")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	interleaved, err := NewDecoder(bytes.NewReader(data)).FullPCMBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	frames := len(interleaved.Data) / 2
+
+	dec := NewDecoder(bytes.NewReader(data))
+	dec.SampleOrder = SampleOrderPlanar
+
+	full, err := dec.FullPCMBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := range frames {
+		if full.Data[i] != interleaved.Data[2*i] || full.Data[frames+i] != interleaved.Data[2*i+1] {
+			t.Fatalf("frame %d isn't planar", i)
+		}
+	}
+
+	// PCMBuffer decodes whole frames, each buffer planar on its own
+	dec = NewDecoder(bytes.NewReader(data))
+	dec.SampleOrder = SampleOrderPlanar
+	buf := &audio.Float32Buffer{Data: make([]float32, 7)}
+
+	n, err := dec.PCMBuffer(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []float32{interleaved.Data[0], interleaved.Data[2], interleaved.Data[4], interleaved.Data[1], interleaved.Data[3], interleaved.Data[5]}
+	if n != 6 {
+		t.Fatalf("expected 3 whole frames, got %d samples", n)
+	}
+
+	assertFloat32SlicesClose(t, buf.Data[:n], want, 0)
+
+	// the planar buffer goes straight into an encoder with the same order
+	enc := NewMemoryEncoder(int(dec.SampleRate), 32, 2, wavFormatIEEEFloat)
+	enc.SampleOrder = SampleOrderPlanar
+
+	err = enc.Write(full)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encoded, err := enc.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	roundTrip, err := NewDecoder(bytes.NewReader(encoded)).FullPCMBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertFloat32SlicesClose(t, roundTrip.Data, interleaved.Data, 0)
+}
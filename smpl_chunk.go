@@ -19,8 +19,16 @@ var (
 	errSmplManufacturerReadFail = errors.New("failed to read the smpl Manufacturer")
 	errSmplProductReadFail      = errors.New("failed to read the smpl Product")
 	errSmplCuePointIDReadFail   = errors.New("failed to read the sample loop cue point id")
+
+	// ErrSmplLoopCountMismatch is reported as a decoder warning when the smpl
+	// chunk declares more loops than it can hold. Only the loops present in
+	// the chunk are decoded.
+	ErrSmplLoopCountMismatch = errors.New("smpl loop count exceeds the chunk size")
 )
 
+// smplLoopSize is the size of a sample loop entry in bytes.
+const smplLoopSize = 24
+
 // DecodeSamplerChunk decodes a smpl chunk and put the data in Decoder.Metadata.SamplerInfo.
 func DecodeSamplerChunk(d *Decoder, ch *riff.Chunk) error {
 	if ch == nil {
@@ -96,16 +104,22 @@ func DecodeSamplerChunk(d *Decoder, ch *riff.Chunk) error {
 			return fmt.Errorf("failed to read number of sample loops: %w", err)
 		}
 
-		var remaining uint32
-		// sampler data
-		err = binary.Read(Reader, binary.BigEndian, &remaining)
+		var samplerDataSize uint32
+
+		err = binary.Read(Reader, binary.LittleEndian, &samplerDataSize)
 		if err != nil {
-			return fmt.Errorf("failed to read remaining sampler data: %w", err)
+			return fmt.Errorf("failed to read sampler data size: %w", err)
 		}
 
-		if d.Metadata.SamplerInfo.NumSampleLoops > 0 {
+		numLoops := d.Metadata.SamplerInfo.NumSampleLoops
+		if available := uint32(Reader.Len() / smplLoopSize); numLoops > available {
+			d.addWarning(fmt.Errorf("%w: %d loops declared, room for %d", ErrSmplLoopCountMismatch, numLoops, available))
+			numLoops = available
+		}
+
+		if numLoops > 0 {
 			d.Metadata.SamplerInfo.Loops = []*SampleLoop{}
-			for range d.Metadata.SamplerInfo.NumSampleLoops {
+			for range numLoops {
 				sampleLoop := &SampleLoop{}
 
 				_, err = Reader.Read(scratch)
@@ -143,9 +157,51 @@ func DecodeSamplerChunk(d *Decoder, ch *riff.Chunk) error {
 				d.Metadata.SamplerInfo.Loops = append(d.Metadata.SamplerInfo.Loops, sampleLoop)
 			}
 		}
+
+		// sampler specific data follows the loops
+		if samplerDataSize > 0 && Reader.Len() > 0 {
+			d.Metadata.SamplerInfo.SamplerData = make([]byte, min(int(samplerDataSize), Reader.Len()))
+
+			_, err = io.ReadFull(Reader, d.Metadata.SamplerInfo.SamplerData)
+			if err != nil {
+				return fmt.Errorf("failed to read sampler data: %w", err)
+			}
+		}
 	}
 
 	ch.Drain()
 
 	return nil
 }
+
+// encodeSamplerChunk returns the payload of a smpl chunk. The loop count is
+// taken from Loops, nil loops are skipped.
+func encodeSamplerChunk(info *SamplerInfo) []byte {
+	loops := make([]*SampleLoop, 0, len(info.Loops))
+	for _, loop := range info.Loops {
+		if loop != nil {
+			loops = append(loops, loop)
+		}
+	}
+
+	payload := make([]byte, 0, 36+smplLoopSize*len(loops)+len(info.SamplerData))
+	payload = append(payload, info.Manufacturer[:]...)
+	payload = append(payload, info.Product[:]...)
+
+	for _, v := range []uint32{
+		info.SamplePeriod, info.MIDIUnityNote, info.MIDIPitchFraction, info.SMPTEFormat, info.SMPTEOffset,
+		uint32(len(loops)), uint32(len(info.SamplerData)),
+	} {
+		payload = binary.LittleEndian.AppendUint32(payload, v)
+	}
+
+	for _, loop := range loops {
+		payload = append(payload, loop.CuePointID[:]...)
+
+		for _, v := range []uint32{loop.Type, loop.Start, loop.End, loop.Fraction, loop.PlayCount} {
+			payload = binary.LittleEndian.AppendUint32(payload, v)
+		}
+	}
+
+	return append(payload, info.SamplerData...)
+}
@@ -0,0 +1,94 @@
+package wav
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	"github.com/go-audio/riff"
+)
+
+func TestDecodeSamplerChunk_InflatedLoopCount(t *testing.T) {
+	payload := encodeTestSmplChunk(
+		SampleLoop{CuePointID: [4]byte{1}, Start: 2, End: 5},
+		SampleLoop{CuePointID: [4]byte{2}, Start: 8, End: 12},
+	)
+	binary.LittleEndian.PutUint32(payload[28:32], 1<<30)
+
+	dec := NewDecoder(bytes.NewReader(nil))
+	ch := &riff.Chunk{ID: CIDSmpl, Size: len(payload), R: bytes.NewReader(payload)}
+
+	err := DecodeSamplerChunk(dec, ch)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	info := dec.Metadata.SamplerInfo
+	if len(info.Loops) != 2 || info.Loops[1].End != 12 {
+		t.Fatalf("expected the 2 loops present in the chunk, got %+v", info.Loops)
+	}
+
+	warnings := dec.Warnings()
+	if len(warnings) != 1 || !errors.Is(warnings[0], ErrSmplLoopCountMismatch) {
+		t.Fatalf("expected ErrSmplLoopCountMismatch, got %v", warnings)
+	}
+}
+
+func TestSamplerChunk_SamplerDataRoundTrip(t *testing.T) {
+	payload := encodeTestSmplChunk(SampleLoop{CuePointID: [4]byte{1}, Start: 2, End: 5, PlayCount: 1})
+	binary.LittleEndian.PutUint32(payload[8:12], 22676)
+	binary.LittleEndian.PutUint32(payload[12:16], 60)
+
+	samplerData := []byte{0xde, 0xad, 0xbe, 0xef, 0x01}
+	binary.LittleEndian.PutUint32(payload[32:36], uint32(len(samplerData)))
+	payload = append(payload, samplerData...)
+
+	enc := NewMemoryEncoder(8000, 16, 1, wavFormatPCM)
+	enc.UnknownChunks = []RawChunk{{ID: CIDSmpl, Data: payload}}
+
+	err := enc.WriteSilence(8)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := enc.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dec := NewDecoder(bytes.NewReader(data))
+	dec.ReadMetadata()
+
+	if err := dec.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	info := dec.Metadata.SamplerInfo
+	if info == nil || !bytes.Equal(info.SamplerData, samplerData) {
+		t.Fatalf("expected the sampler data to be decoded, got %+v", info)
+	}
+
+	enc = NewMemoryEncoder(8000, 16, 1, wavFormatPCM)
+	enc.Metadata = dec.Metadata
+
+	err = enc.WriteSilence(8)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err = enc.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chunks, err := parseWavChunks(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	smpl, _ := findChunk(chunks, "smpl")
+	if smpl == nil || !bytes.Equal(smpl.data, payload) {
+		t.Fatalf("smpl chunk didn't round trip:\n got %x\nwant %x", smpl, payload)
+	}
+}
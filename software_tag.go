@@ -0,0 +1,60 @@
+package wav
+
+import (
+	"runtime/debug"
+	"strings"
+)
+
+// softwareTagName names this package in the ISFT entry written for
+// Encoder.AutoSoftwareTag.
+const softwareTagName = "cwbudde/wav"
+
+// SoftwareTag returns the ISFT value written when Encoder.AutoSoftwareTag is
+// set: the package name followed by its module version, e.g.
+// "cwbudde/wav v1.2.0". The version is left out when the binary carries no
+// module information or was built from a development tree.
+func SoftwareTag() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return softwareTagName
+	}
+
+	const modulePath = "github.com/cwbudde/wav"
+
+	version := ""
+	if info.Main.Path == modulePath {
+		version = info.Main.Version
+	}
+
+	for _, dep := range info.Deps {
+		if dep.Path == modulePath {
+			version = dep.Version
+			if dep.Replace != nil {
+				version = dep.Replace.Version
+			}
+		}
+	}
+
+	if version == "" || version == "(devel)" || strings.HasPrefix(version, "v0.0.0-") {
+		return softwareTagName
+	}
+
+	return softwareTagName + " " + version
+}
+
+// applySoftwareTag fills an empty Metadata.Software with SoftwareTag when
+// AutoSoftwareTag is set. The metadata is copied so the caller's value is
+// left untouched.
+func (e *Encoder) applySoftwareTag() {
+	if !e.AutoSoftwareTag || (e.Metadata != nil && e.Metadata.Software != "") {
+		return
+	}
+
+	md := Metadata{}
+	if e.Metadata != nil {
+		md = *e.Metadata
+	}
+
+	md.Software = SoftwareTag()
+	e.Metadata = &md
+}
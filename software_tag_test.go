@@ -0,0 +1,70 @@
+package wav
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestEncoder_AutoSoftwareTag(t *testing.T) {
+	decodeSoftware := func(t *testing.T, enc *MemoryEncoder) string {
+		t.Helper()
+
+		err := enc.WriteSilence(4)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		data, err := enc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		dec := NewDecoder(bytes.NewReader(data))
+		dec.ReadMetadata()
+
+		if dec.Metadata == nil {
+			return ""
+		}
+
+		return dec.Metadata.Software
+	}
+
+	if !strings.HasPrefix(SoftwareTag(), "cwbudde/wav") {
+		t.Fatalf("unexpected software tag %q", SoftwareTag())
+	}
+
+	enc := NewMemoryEncoder(8000, 16, 1, wavFormatPCM)
+	enc.AutoSoftwareTag = true
+
+	if got := decodeSoftware(t, enc); got != SoftwareTag() {
+		t.Fatalf("expected the software tag without metadata, got %q", got)
+	}
+
+	md := &Metadata{Title: "title"}
+	enc = NewMemoryEncoder(8000, 16, 1, wavFormatPCM)
+	enc.AutoSoftwareTag = true
+	enc.Metadata = md
+
+	if got := decodeSoftware(t, enc); got != SoftwareTag() {
+		t.Fatalf("expected the software tag next to other metadata, got %q", got)
+	}
+
+	if md.Software != "" {
+		t.Fatalf("expected the caller's metadata to be left untouched, got %q", md.Software)
+	}
+
+	enc = NewMemoryEncoder(8000, 16, 1, wavFormatPCM)
+	enc.AutoSoftwareTag = true
+	enc.Metadata = &Metadata{Software: "my tool"}
+
+	if got := decodeSoftware(t, enc); got != "my tool" {
+		t.Fatalf("expected an explicit software entry to win, got %q", got)
+	}
+
+	enc = NewMemoryEncoder(8000, 16, 1, wavFormatPCM)
+
+	if got := decodeSoftware(t, enc); got != "" {
+		t.Fatalf("expected no software tag by default, got %q", got)
+	}
+}
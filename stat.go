@@ -0,0 +1,210 @@
+package wav
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/go-audio/riff"
+)
+
+// ErrFactChunkNotFound is returned by AccurateDuration for compressed formats
+// without a fact chunk, whose frame count can't be derived from the data size.
+var ErrFactChunkNotFound = errors.New("fact chunk not found")
+
+// FileStat summarizes the layout and format of a wav file, see Decoder.Stat.
+type FileStat struct {
+	// Format is the name of the audio format, such as "PCM", "IEEE float"
+	// or "GSM 6.10". Unknown formats are named by their hex format tag.
+	Format string
+	// FormatTag is the format tag, resolved through the extensible sub
+	// format.
+	FormatTag uint16
+	// Extensible reports a WAVE_FORMAT_EXTENSIBLE fmt chunk.
+	Extensible bool
+	// BigEndian reports a RIFX file.
+	BigEndian     bool
+	Channels      int
+	ChannelLayout string
+	SampleRate    int
+	BitDepth      int
+	// Frames is the number of sample frames. Compressed formats other than
+	// G.711 rely on the fact chunk and report 0 without one.
+	Frames   int64
+	Duration time.Duration
+	// DataOffset is the file offset of the first byte of the data chunk
+	// payload, DataSize the declared payload size.
+	DataOffset int64
+	DataSize   int64
+	// Chunks lists the IDs of the top-level chunks in file order.
+	Chunks []string
+}
+
+// Stat walks the chunks of the file and returns a summary of its format and
+// layout. Like ForEachChunk, it leaves the decoder reset to the start of the
+// file.
+func (d *Decoder) Stat() (*FileStat, error) {
+	if d == nil || d.r == nil {
+		return nil, errNilDecoder
+	}
+
+	stat := &FileStat{DataOffset: -1}
+
+	var (
+		factSamples int64
+		hasFact     bool
+	)
+
+	err := d.ForEachChunk(func(id [4]byte, r io.Reader) error {
+		stat.Chunks = append(stat.Chunks, string(id[:]))
+		stat.BigEndian = d.bigEndian
+
+		switch id {
+		case riff.DataFormatID:
+			if stat.DataOffset >= 0 {
+				return nil
+			}
+
+			offset, err := d.r.Seek(0, io.SeekCurrent)
+			if err != nil {
+				return fmt.Errorf("failed to locate the data chunk: %w", err)
+			}
+
+			stat.DataOffset = offset
+
+			if lr, ok := r.(*io.LimitedReader); ok {
+				stat.DataSize = lr.N
+			}
+		case CIDFact:
+			var samples uint32
+
+			err := binary.Read(r, d.byteOrder(), &samples)
+			if err != nil {
+				return fmt.Errorf("failed to read the fact sample count: %w", err)
+			}
+
+			factSamples = int64(samples)
+			hasFact = true
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	fmtChunk, err := d.PeekFormat()
+	if err != nil {
+		return nil, err
+	}
+
+	stat.FormatTag = fmtChunk.EffectiveFormatTag()
+	stat.Format = formatTagName(stat.FormatTag)
+	stat.Extensible = fmtChunk.Extensible != nil
+	stat.Channels = int(fmtChunk.NumChannels)
+	stat.SampleRate = int(fmtChunk.SampleRate)
+	stat.BitDepth = int(fmtChunk.BitsPerSample)
+
+	var mask uint32
+	if fmtChunk.Extensible != nil {
+		mask = fmtChunk.Extensible.ChannelMask
+	}
+
+	stat.ChannelLayout = channelLayoutName(stat.Channels, mask)
+
+	switch stat.FormatTag {
+	case wavFormatPCM, wavFormatIEEEFloat, wavFormatALaw, wavFormatMuLaw:
+		if blockAlign := frameBytes(fmtChunk); blockAlign > 0 {
+			stat.Frames = stat.DataSize / blockAlign
+		}
+	default:
+		if hasFact {
+			stat.Frames = factSamples
+		}
+	}
+
+	if stat.SampleRate > 0 {
+		stat.Duration = time.Duration(stat.Frames) * time.Second / time.Duration(stat.SampleRate)
+	}
+
+	return stat, nil
+}
+
+// AccurateDuration returns the duration of the audio the file actually holds.
+// Unlike Duration, which trusts the header sizes, it counts the frames of the
+// data chunk bytes present in the file, so data chunks declaring more bytes
+// than the file contains are measured by their real content. Compressed
+// formats other than G.711 use the fact chunk sample count and return
+// ErrFactChunkNotFound without one. The decoder is left reset to the start of
+// the file, see Stat.
+func (d *Decoder) AccurateDuration() (time.Duration, error) {
+	stat, err := d.Stat()
+	if err != nil {
+		return 0, err
+	}
+
+	if stat.DataOffset < 0 {
+		return 0, ErrPCMChunkNotFound
+	}
+
+	end, err := d.r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0, fmt.Errorf("failed to seek to the end: %w", err)
+	}
+
+	fmtChunk, err := d.PeekFormat()
+	if err != nil {
+		return 0, err
+	}
+
+	dataSize := min(stat.DataSize, max(end-stat.DataOffset, 0))
+	frames := stat.Frames
+
+	switch stat.FormatTag {
+	case wavFormatPCM, wavFormatIEEEFloat, wavFormatALaw, wavFormatMuLaw:
+		frames = 0
+		if blockAlign := frameBytes(fmtChunk); blockAlign > 0 {
+			frames = dataSize / blockAlign
+		}
+	default:
+		if frames == 0 && dataSize > 0 {
+			return 0, ErrFactChunkNotFound
+		}
+	}
+
+	if stat.SampleRate <= 0 {
+		return 0, nil
+	}
+
+	return time.Duration(frames) * time.Second / time.Duration(stat.SampleRate), nil
+}
+
+// frameBytes returns the size of a sample frame, falling back to the channel
+// count and bit depth when the block align is missing.
+func frameBytes(f *FmtChunk) int64 {
+	if f.BlockAlign > 0 {
+		return int64(f.BlockAlign)
+	}
+
+	return int64(f.NumChannels) * int64(bytesPerSample(int(f.BitsPerSample)))
+}
+
+// formatTagName returns a readable name for a wav format tag.
+func formatTagName(tag uint16) string {
+	switch tag {
+	case wavFormatPCM:
+		return "PCM"
+	case wavFormatIEEEFloat:
+		return "IEEE float"
+	case wavFormatALaw:
+		return "A-law"
+	case wavFormatMuLaw:
+		return "mu-law"
+	case wavFormatGSM610:
+		return "GSM 6.10"
+	default:
+		return fmt.Sprintf("0x%04X", tag)
+	}
+}
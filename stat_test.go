@@ -0,0 +1,154 @@
+package wav
+
+import (
+	"bytes"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDecoder_Stat(t *testing.T) {
+	testCases := []struct {
+		path string
+		want FileStat
+	}{
+		{"fixtures/kick.wav", FileStat{
+			Format: "PCM", FormatTag: wavFormatPCM, Channels: 1, ChannelLayout: "mono", SampleRate: 22050, BitDepth: 16,
+			Frames: 4484, Duration: 4484 * time.Second / 22050, DataOffset: 44, DataSize: 8968,
+			Chunks: []string{"fmt ", "data"},
+		}},
+		{"fixtures/two-data-chunks.wav", FileStat{
+			Format: "PCM", FormatTag: wavFormatPCM, Channels: 1, ChannelLayout: "mono", SampleRate: 8000, BitDepth: 16,
+			Frames: 4, Duration: 500 * time.Microsecond, DataOffset: 44, DataSize: 8,
+			Chunks: []string{"fmt ", "data", "JUNK", "data"},
+		}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.path, func(t *testing.T) {
+			data, err := os.ReadFile(testCase.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			dec := NewDecoder(bytes.NewReader(data))
+
+			stat, err := dec.Stat()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(*stat, testCase.want) {
+				t.Fatalf("expected\n%+v\ngot\n%+v", testCase.want, *stat)
+			}
+
+			// the decoder must still be usable
+			buf, err := dec.FullPCMBuffer()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if int64(buf.NumFrames()) != stat.Frames {
+				t.Fatalf("expected %d decoded frames, got %d", stat.Frames, buf.NumFrames())
+			}
+		})
+	}
+
+	gsm, err := os.ReadFile("fixtures/addf8-GSM-GW.wav")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dec := NewDecoder(bytes.NewReader(gsm))
+
+	stat, err := dec.Stat()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dec.ReadInfo()
+	dec.ReadMetadata()
+
+	if stat.Format != "GSM 6.10" || stat.Frames == 0 || stat.Frames != int64(dec.CompressedSamples) {
+		t.Fatalf("expected the GSM frame count from the fact chunk, got %+v", stat)
+	}
+
+	kick, err := os.ReadFile("fixtures/kick.wav")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stat, err = NewDecoder(bytes.NewReader(riffToRIFX(t, kick))).Stat()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !stat.BigEndian || stat.Frames != 4484 || stat.SampleRate != 22050 {
+		t.Fatalf("unexpected RIFX stat: %+v", stat)
+	}
+}
+
+func TestDecoder_AccurateDuration(t *testing.T) {
+	enc := NewMemoryEncoder(8000, 16, 1, wavFormatPCM)
+
+	err := enc.WriteSilence(100)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := enc.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// cut 20 frames off the end, the header still declares 100
+	truncated := data[:len(data)-40]
+
+	dec := NewDecoder(bytes.NewReader(truncated))
+
+	dur, err := dec.AccurateDuration()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := 80 * time.Second / 8000; dur != want {
+		t.Fatalf("expected %v, got %v", want, dur)
+	}
+
+	// the decoder must still be usable afterwards
+	buf, err := dec.FullPCMBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if buf.NumFrames() != 80 {
+		t.Fatalf("expected 80 decoded frames, got %d", buf.NumFrames())
+	}
+
+	testCases := []struct {
+		path string
+		want time.Duration
+	}{
+		{"fixtures/GLASS.WAV", 40200 * time.Second / 8000},
+		{"fixtures/addf8-GSM-GW.wav", 23808 * time.Second / 8000},
+	}
+
+	for _, testCase := range testCases {
+		file, err := os.Open(testCase.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		dur, err := NewDecoder(file).AccurateDuration()
+		file.Close()
+
+		if err != nil {
+			t.Fatalf("%s: %v", testCase.path, err)
+		}
+
+		if dur != testCase.want {
+			t.Fatalf("%s: expected %v, got %v", testCase.path, testCase.want, dur)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package wav
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+var errG711Transcode = errors.New("only A-law and mu-law can be transcoded into each other")
+
+// TranscodeG711 converts an A-law file to mu-law or the other way around.
+// Each sample is expanded to its exact 16-bit linear value and compressed
+// with the target law, so the only loss is the target quantization instead
+// of the two conversions of a float decode and re-encode. target is the
+// format tag to write, 6 for A-law or 7 for mu-law. Metadata and preserved
+// chunks are copied. The source must use the other law.
+func TranscodeG711(dst io.WriteSeeker, src io.ReadSeeker, target int) error {
+	if target != wavFormatALaw && target != wavFormatMuLaw {
+		return fmt.Errorf("%w: target format %d", errG711Transcode, target)
+	}
+
+	dec := NewDecoder(src)
+	dec.ReadMetadata()
+
+	err := dec.Err()
+	if err != nil {
+		return fmt.Errorf("failed to read the source: %w", err)
+	}
+
+	source := int(dec.WavAudioFormat)
+	if source == target || source != wavFormatALaw && source != wavFormatMuLaw {
+		return fmt.Errorf("%w: format %d to %d", errG711Transcode, source, target)
+	}
+
+	err = dec.Rewind()
+	if err != nil {
+		return err
+	}
+
+	enc := NewEncoderFromDecoder(dst, dec)
+	enc.WavAudioFormat = target
+	enc.Metadata = dec.Metadata
+
+	if enc.FmtChunk != nil {
+		if enc.FmtChunk.Extensible != nil {
+			enc.FmtChunk.Extensible.SubFormat = makeSubFormatGUID(uint16(target))
+		} else {
+			enc.FmtChunk.FormatTag = uint16(target)
+		}
+	}
+
+	var table [256]byte
+
+	for code := range table {
+		if target == wavFormatMuLaw {
+			table[code] = encodeMuLawSample(decodeALawSample(byte(code)))
+		} else {
+			table[code] = encodeALawSample(decodeMuLawSample(byte(code)))
+		}
+	}
+
+	err = enc.startPCMChunk()
+	if err != nil {
+		return err
+	}
+
+	numChans := max(int(dec.NumChans), 1)
+	data := io.LimitReader(dec.PCMChunk.R, int64(dec.PCMSize))
+	buf := make([]byte, 4096*numChans)
+
+	for {
+		n, err := io.ReadFull(data, buf)
+		for i, code := range buf[:n] {
+			buf[i] = table[code]
+		}
+
+		writeErr := enc.writeEncodedFrames(buf[:n], n/numChans)
+		if writeErr != nil {
+			return fmt.Errorf("failed to write samples: %w", writeErr)
+		}
+
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			break
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to read samples: %w", err)
+		}
+	}
+
+	return enc.Close()
+}
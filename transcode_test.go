@@ -0,0 +1,102 @@
+package wav
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"testing"
+)
+
+func TestTranscodeG711(t *testing.T) {
+	src, err := os.ReadFile("fixtures/M1F1-Alaw-AFsp.wav")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := &seekBuffer{}
+
+	err = TranscodeG711(out, bytes.NewReader(src), wavFormatMuLaw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srcDec := NewDecoder(bytes.NewReader(src))
+
+	srcBuf, err := srcDec.FullPCMBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dec := NewDecoder(bytes.NewReader(out.data))
+
+	buf, err := dec.FullPCMBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if dec.WavAudioFormat != wavFormatMuLaw || dec.NumChans != srcDec.NumChans || dec.SampleRate != srcDec.SampleRate {
+		t.Fatalf("unexpected format %d, %d channels at %d Hz", dec.WavAudioFormat, dec.NumChans, dec.SampleRate)
+	}
+
+	if len(buf.Data) != len(srcBuf.Data) {
+		t.Fatalf("expected %d samples, got %d", len(srcBuf.Data), len(buf.Data))
+	}
+
+	// each sample is the mu-law code closest to the linear A-law value
+	want := make([]float32, len(srcBuf.Data))
+	for i, value := range srcBuf.Data {
+		code := encodeMuLawSample(int16(float32ToPCMInt32(value, 16)))
+		want[i] = normalizePCMInt(int(decodeMuLawSample(code)), 16)
+	}
+
+	assertFloat32SlicesClose(t, buf.Data, want, 0)
+
+	// and back again
+	back := &seekBuffer{}
+
+	err = TranscodeG711(back, bytes.NewReader(out.data), wavFormatALaw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if format := NewDecoder(bytes.NewReader(back.data)).SampleEncoding(); format.BitDepth != 8 || !format.Compressed {
+		t.Fatalf("unexpected A-law encoding %+v", format)
+	}
+}
+
+func TestTranscodeG711RejectsOtherFormats(t *testing.T) {
+	alaw, err := os.ReadFile("fixtures/M1F1-Alaw-AFsp.wav")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pcm, err := os.ReadFile("fixtures/kick.wav")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name   string
+		src    []byte
+		target int
+	}{
+		{name: "pcm target", src: alaw, target: wavFormatPCM},
+		{name: "same law", src: alaw, target: wavFormatALaw},
+		{name: "pcm source", src: pcm, target: wavFormatMuLaw},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			out := &seekBuffer{}
+
+			err := TranscodeG711(out, bytes.NewReader(testCase.src), testCase.target)
+			if !errors.Is(err, errG711Transcode) {
+				t.Fatalf("expected a transcode error, got %v", err)
+			}
+
+			if len(out.data) != 0 {
+				t.Fatalf("expected nothing to be written, got %d bytes", len(out.data))
+			}
+		})
+	}
+}
@@ -3,8 +3,11 @@ package wav
 import (
 	"bytes"
 	"encoding/binary"
+	"errors"
+	"io"
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 )
 
@@ -162,3 +165,241 @@ func writeTestChunk(t *testing.T, b *bytes.Buffer, id string, payload []byte) {
 		}
 	}
 }
+
+func TestUnknownChunkRoundTripPreservesOddSizedRadioChunks(t *testing.T) {
+	var b bytes.Buffer
+	b.WriteString("RIFF")
+	b.Write(make([]byte, 4))
+	b.WriteString("WAVE")
+
+	fmtPayload := make([]byte, 16)
+	binary.LittleEndian.PutUint16(fmtPayload[0:2], wavFormatPCM)
+	binary.LittleEndian.PutUint16(fmtPayload[2:4], 1)
+	binary.LittleEndian.PutUint32(fmtPayload[4:8], 8000)
+	binary.LittleEndian.PutUint32(fmtPayload[8:12], 16000)
+	binary.LittleEndian.PutUint16(fmtPayload[12:14], 2)
+	binary.LittleEndian.PutUint16(fmtPayload[14:16], 16)
+	writeTestChunk(t, &b, "fmt ", fmtPayload)
+	// odd sized payloads are followed by a pad byte that isn't part of the size
+	writeTestChunk(t, &b, "scot", []byte{0x01, 0x02, 0x03, 0x04, 0x05})
+	writeTestChunk(t, &b, "data", []byte{0x01, 0x00, 0x02, 0x00})
+	writeTestChunk(t, &b, "plst", []byte{0x01, 0x00, 0x00, 0x00, 0x07, 0x08, 0x09})
+
+	input := b.Bytes()
+	binary.LittleEndian.PutUint32(input[4:8], uint32(len(input)-8))
+
+	dec := NewDecoder(bytes.NewReader(input))
+	dec.ReadMetadata()
+
+	if err := dec.Err(); err != nil {
+		t.Fatalf("read metadata: %v", err)
+	}
+
+	if err := dec.Rewind(); err != nil {
+		t.Fatalf("rewind: %v", err)
+	}
+
+	buf, err := dec.FullPCMBuffer()
+	if err != nil {
+		t.Fatalf("decode PCM: %v", err)
+	}
+
+	memEnc := NewMemoryEncoder(int(dec.SampleRate), int(dec.BitDepth), int(dec.NumChans), int(dec.WavAudioFormat))
+	memEnc.UnknownChunks = dec.UnknownChunks
+
+	if err := memEnc.Write(buf); err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+
+	output, err := memEnc.Close()
+	if err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	before, err := parseWavChunks(input)
+	if err != nil {
+		t.Fatalf("parse input wav chunks: %v", err)
+	}
+
+	after, err := parseWavChunks(output)
+	if err != nil {
+		t.Fatalf("parse output wav chunks: %v", err)
+	}
+
+	if !reflect.DeepEqual(buildChunkInventory(before), buildChunkInventory(after)) {
+		t.Fatalf("chunk inventory mismatch:\nbefore: %+v\nafter:  %+v", buildChunkInventory(before), buildChunkInventory(after))
+	}
+
+	for _, id := range []string{"scot", "plst"} {
+		want, _ := findChunk(before, id)
+		got, _ := findChunk(after, id)
+
+		if got == nil || !bytes.Equal(got.data, want.data) {
+			t.Fatalf("%s payload mismatch", id)
+		}
+	}
+
+	if len(output) != len(input) {
+		t.Fatalf("expected output size %d, got %d", len(input), len(output))
+	}
+}
+
+func TestDecoder_OnChunk(t *testing.T) {
+	type seenChunk struct {
+		id   string
+		size int
+		data []byte
+	}
+
+	var seen []seenChunk
+
+	dec := NewDecoder(bytes.NewReader(makeWavWithUnknownChunks(t)))
+	dec.OnChunk = func(id [4]byte, size int, data []byte) {
+		seen = append(seen, seenChunk{id: string(id[:]), size: size, data: append([]byte(nil), data...)})
+	}
+	dec.ReadMetadata()
+
+	if err := dec.Err(); err != nil {
+		t.Fatalf("read metadata: %v", err)
+	}
+
+	expected := []seenChunk{
+		{id: "JUNK", size: 4, data: []byte{0x01, 0x02, 0x03, 0x04}},
+		{id: "data", size: 4},
+		{id: "xtra", size: 4, data: []byte{0x09, 0x08, 0x07, 0x06}},
+	}
+
+	if len(seen) != len(expected) {
+		t.Fatalf("expected %d chunks, got %+v", len(expected), seen)
+	}
+
+	for i := range expected {
+		if seen[i].id != expected[i].id || seen[i].size != expected[i].size || !bytes.Equal(seen[i].data, expected[i].data) {
+			t.Fatalf("chunk %d: got %+v, want %+v", i, seen[i], expected[i])
+		}
+	}
+
+	// the chunks must still be captured after being observed
+	if len(dec.UnknownChunks) != 2 || !bytes.Equal(dec.UnknownChunks[1].Data, expected[2].data) {
+		t.Fatalf("unknown chunks not captured: %+v", dec.UnknownChunks)
+	}
+}
+
+func TestDecoder_ForEachChunk(t *testing.T) {
+	b := bytes.NewBuffer(makeWavWithUnknownChunks(t))
+	// the pad byte of an odd sized chunk must be skipped as well
+	writeTestChunk(t, b, "scot", []byte{0x01, 0x02, 0x03})
+	writeTestChunk(t, b, "last", []byte{0x05, 0x06})
+
+	input := b.Bytes()
+	binary.LittleEndian.PutUint32(input[4:8], uint32(len(input)-8))
+
+	dec := NewDecoder(bytes.NewReader(input))
+
+	var (
+		ids   []string
+		heads [][]byte
+	)
+
+	err := dec.ForEachChunk(func(id [4]byte, r io.Reader) error {
+		ids = append(ids, string(id[:]))
+
+		// read only part of each payload, the rest must be drained.
+		head := make([]byte, 2)
+
+		_, err := io.ReadFull(r, head)
+		heads = append(heads, head)
+
+		return err
+	})
+	if err != nil {
+		t.Fatalf("for each chunk: %v", err)
+	}
+
+	wantIDs := []string{"fmt ", "JUNK", "data", "xtra", "scot", "last"}
+	if !reflect.DeepEqual(ids, wantIDs) {
+		t.Fatalf("chunk ids %q, want %q", ids, wantIDs)
+	}
+
+	if !bytes.Equal(heads[3], []byte{0x09, 0x08}) || !bytes.Equal(heads[5], []byte{0x05, 0x06}) {
+		t.Fatalf("unexpected chunk payloads % x", heads)
+	}
+
+	buf, err := dec.FullPCMBuffer()
+	if err != nil {
+		t.Fatalf("decode after iteration: %v", err)
+	}
+
+	if buf.NumFrames() != 2 {
+		t.Fatalf("expected 2 frames after iteration, got %d", buf.NumFrames())
+	}
+
+	errStop := errors.New("stop")
+	calls := 0
+
+	err = dec.ForEachChunk(func(_ [4]byte, _ io.Reader) error {
+		calls++
+
+		return errStop
+	})
+	if !errors.Is(err, errStop) || calls != 1 {
+		t.Fatalf("expected iteration to stop with errStop after one call, got %v after %d", err, calls)
+	}
+}
+
+func TestDecoder_TrailingPaddedChunks(t *testing.T) {
+	// an odd sized data chunk followed by odd sized PAD and JUNK chunks,
+	// each with its pad byte
+	data, err := os.ReadFile("fixtures/trailing-padded-chunks.wav")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []RawChunk{
+		{ID: [4]byte{'P', 'A', 'D', ' '}, Size: 5, Data: make([]byte, 5)},
+		{ID: [4]byte{'J', 'U', 'N', 'K'}, Size: 3, Data: []byte("abc")},
+	}
+
+	checkChunks := func(t *testing.T, dec *Decoder) {
+		t.Helper()
+
+		if err := dec.Err(); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(dec.UnknownChunks) != len(want) {
+			t.Fatalf("expected trailing chunks %+v, got %+v", want, dec.UnknownChunks)
+		}
+
+		for i, chunk := range dec.UnknownChunks {
+			if chunk.ID != want[i].ID || chunk.Size != want[i].Size || !bytes.Equal(chunk.Data, want[i].Data) || chunk.BeforeData {
+				t.Fatalf("expected trailing chunk %+v, got %+v", want[i], chunk)
+			}
+		}
+	}
+
+	t.Run("metadata", func(t *testing.T) {
+		dec := NewDecoder(bytes.NewReader(data))
+		dec.ReadMetadata()
+		checkChunks(t, dec)
+	})
+
+	t.Run("after samples", func(t *testing.T) {
+		dec := NewDecoder(bytes.NewReader(data))
+
+		buf, err := dec.FullPCMBuffer()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// the pad byte of the data chunk isn't decoded as a sample
+		assertFloat32SlicesClose(t, buf.Data, []float32{0.003921569, 0.5058824, -0.49803922}, 1e-6)
+
+		if dec.PCMLen() != 3 {
+			t.Fatalf("expected 3 data bytes, got %d", dec.PCMLen())
+		}
+
+		dec.ReadMetadata()
+		checkChunks(t, dec)
+	})
+}
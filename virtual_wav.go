@@ -0,0 +1,142 @@
+package wav
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// maxStreamDataSize is the data chunk size declared for a stream.
+const maxStreamDataSize = 0xFFFFFFFE
+
+var errStreamSeek = errors.New("can't seek in a stream")
+
+// virtualWAVReader presents audio stored outside of a WAV file as one: an
+// in-memory header holding the fmt chunk and the data chunk header, followed
+// by the samples read from the source.
+type virtualWAVReader struct {
+	src      io.ReadSeeker
+	header   []byte
+	segments []dataSegment
+	size     int64
+	pos      int64
+	// data reads the samples from the current position, it is nil after a
+	// seek.
+	data io.Reader
+	// stream is set instead of src and segments for a source that can't
+	// seek, its samples are read once in order.
+	stream io.Reader
+}
+
+// newVirtualWAVReader returns a reader for the fmt chunk payload fmtChunk and
+// the samples stored in the segments of src.
+func newVirtualWAVReader(src io.ReadSeeker, fmtChunk []byte, segments []dataSegment) *virtualWAVReader {
+	var dataSize int64
+	for _, seg := range segments {
+		dataSize += seg.size
+	}
+
+	header := virtualWAVHeader(fmtChunk, dataSize)
+
+	return &virtualWAVReader{
+		src:      src,
+		header:   header,
+		segments: segments,
+		size:     int64(len(header)) + dataSize,
+	}
+}
+
+// newStreamWAVReader returns a reader for the fmt chunk payload fmtChunk and
+// the samples read from stream until it ends. The data chunk size is unknown
+// and declared as the largest size that needs no pad byte.
+func newStreamWAVReader(stream io.Reader, fmtChunk []byte) *virtualWAVReader {
+	header := virtualWAVHeader(fmtChunk, maxStreamDataSize)
+
+	return &virtualWAVReader{
+		header: header,
+		size:   int64(len(header)) + maxStreamDataSize,
+		stream: stream,
+	}
+}
+
+// virtualWAVHeader returns the RIFF header, the fmt chunk and the header of a
+// data chunk of dataSize bytes.
+func virtualWAVHeader(fmtChunk []byte, dataSize int64) []byte {
+	fmtSize := len(fmtChunk) + len(fmtChunk)%2
+
+	var header bytes.Buffer
+	header.WriteString("RIFF")
+	_ = binary.Write(&header, binary.LittleEndian, uint32(min(4+8+int64(fmtSize)+8+dataSize, 0xFFFFFFFF)))
+	header.WriteString("WAVEfmt ")
+	_ = binary.Write(&header, binary.LittleEndian, uint32(len(fmtChunk)))
+	header.Write(fmtChunk)
+	header.Write(make([]byte, fmtSize-len(fmtChunk)))
+	header.WriteString("data")
+	_ = binary.Write(&header, binary.LittleEndian, uint32(min(dataSize, 0xFFFFFFFF)))
+
+	return header.Bytes()
+}
+
+// Read fills p from the header and the samples.
+func (r *virtualWAVReader) Read(p []byte) (int, error) {
+	if r.pos >= r.size {
+		return 0, io.EOF
+	}
+
+	var total int
+
+	if r.pos < int64(len(r.header)) {
+		total = copy(p, r.header[r.pos:])
+		r.pos += int64(total)
+	}
+
+	if total == len(p) {
+		return total, nil
+	}
+
+	if r.data == nil {
+		if r.stream != nil {
+			r.data = r.stream
+		} else {
+			r.data = newSegmentReader(r.src, r.segments, r.pos-int64(len(r.header)))
+		}
+	}
+
+	n, err := r.data.Read(p[total:])
+	total += n
+	r.pos += int64(n)
+
+	if errors.Is(err, io.EOF) && total > 0 {
+		err = nil
+	}
+
+	return total, err
+}
+
+// Seek moves the read position within the virtual WAV file. A stream can't
+// seek, the decoder reads it like any other reader that can't.
+func (r *virtualWAVReader) Seek(offset int64, whence int) (int64, error) {
+	if r.stream != nil {
+		return r.pos, errStreamSeek
+	}
+
+	switch whence {
+	case io.SeekCurrent:
+		offset += r.pos
+	case io.SeekEnd:
+		offset += r.size
+	}
+
+	if offset < 0 {
+		return 0, fmt.Errorf("%w: %d", errNegativeSeek, offset)
+	}
+
+	if offset != r.pos {
+		r.pos = offset
+		r.data = nil
+	}
+
+	return offset, nil
+}
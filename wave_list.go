@@ -0,0 +1,193 @@
+package wav
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/go-audio/riff"
+)
+
+var (
+	// CIDSlnt is the chunk ID of a silence chunk, found in a wave list or next
+	// to the data chunk.
+	CIDSlnt = [4]byte{'s', 'l', 'n', 't'}
+
+	errWaveListFormat = errors.New("wave lists are only supported for PCM, float and G.711 data")
+
+	waveListType = [4]byte{'w', 'a', 'v', 'l'}
+)
+
+// startWaveList checks whether chunk is a LIST of type wavl and if so sets up
+// the PCM chunk to read its data chunks in order, with every slnt chunk
+// expanded to the given number of silent frames. The timeline is presented
+// as one data chunk, so decoding, Rewind and SeekToFrame work as for a plain
+// data chunk. Other LIST chunks are left readable from the start and false
+// is returned. A seekable reader is needed.
+func (d *Decoder) startWaveList(chunk *riff.Chunk) (bool, error) {
+	if chunk.ID != CIDList || chunk.Size < 4 {
+		return false, nil
+	}
+
+	var listType [4]byte
+
+	n, err := io.ReadFull(chunk.R, listType[:])
+	if err != nil || listType != waveListType {
+		d.unreadListType(chunk, listType[:n])
+
+		return false, nil
+	}
+
+	blockAlign := d.frameSize()
+	if blockAlign <= 0 || !d.isWaveListFormat() {
+		return true, fmt.Errorf("%w: format %d", errWaveListFormat, d.WavAudioFormat)
+	}
+
+	listStart, err := d.r.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return true, fmt.Errorf("failed to locate the wave list: %w", err)
+	}
+
+	listEnd := listStart - 4 + int64(chunk.Size)
+
+	segments, err := d.scanWaveList(listStart, listEnd, blockAlign)
+	if err != nil {
+		return true, err
+	}
+
+	var total, silent int64
+
+	for _, seg := range segments {
+		total += seg.size
+
+		if seg.silent {
+			silent += seg.size
+		}
+	}
+
+	_, err = d.r.Seek(listStart, io.SeekStart)
+	if err != nil {
+		return true, fmt.Errorf("failed to seek back to the wave list: %w", err)
+	}
+
+	d.PCMSize = int(total)
+	d.PCMChunk = &riff.Chunk{ID: riff.DataFormatID, Size: int(total), R: newSegmentReader(d.r, segments, 0)}
+	d.pcmStart = listStart
+	d.dataSegments = segments
+	d.pcmConsumed = 0
+	d.pcmDone = false
+	d.SilentFrames = silent / blockAlign
+
+	return true, nil
+}
+
+// unreadListType hands the list type read by startWaveList back to the LIST
+// chunk decoders, seeking back if possible so the chunk can still be skipped
+// by seeking.
+func (d *Decoder) unreadListType(chunk *riff.Chunk, listType []byte) {
+	if lr, ok := chunk.R.(*io.LimitedReader); ok {
+		_, err := d.r.Seek(-int64(len(listType)), io.SeekCurrent)
+		if err == nil {
+			lr.N += int64(len(listType))
+
+			return
+		}
+	}
+
+	chunk.R = io.MultiReader(bytes.NewReader(listType), chunk.R)
+}
+
+// scanWaveList collects the data and silence segments of the wave list
+// payload between start and end.
+func (d *Decoder) scanWaveList(start, end, blockAlign int64) ([]dataSegment, error) {
+	var segments []dataSegment
+
+	for pos := start; pos+8 <= end; {
+		_, err := d.r.Seek(pos, io.SeekStart)
+		if err != nil {
+			return nil, fmt.Errorf("failed to seek in the wave list: %w", err)
+		}
+
+		id, size, err := d.readChunkHeader()
+		if err != nil {
+			// a truncated chunk header ends the list
+			break
+		}
+
+		payload := pos + 8
+		size = uint32(min(int64(size), end-payload))
+
+		switch id {
+		case riff.DataFormatID:
+			if size > 0 {
+				segments = append(segments, dataSegment{start: payload, size: int64(size)})
+			}
+		case CIDSlnt:
+			frames, err := d.readSilentFrames(d.r)
+			if err != nil {
+				return nil, err
+			}
+
+			if frames > 0 {
+				segments = append(segments, dataSegment{size: frames * blockAlign, silent: true, fill: d.silenceByte()})
+			}
+		}
+
+		pos = payload + int64(size) + int64(size%2)
+	}
+
+	return segments, nil
+}
+
+// isWaveListFormat reports whether silence can be expressed as repeated
+// bytes in the data format.
+func (d *Decoder) isWaveListFormat() bool {
+	switch d.WavAudioFormat {
+	case wavFormatPCM, wavFormatIEEEFloat, wavFormatALaw, wavFormatMuLaw:
+		return true
+	default:
+		return false
+	}
+}
+
+// silenceByte returns the byte every sample of a silent frame consists of.
+func (d *Decoder) silenceByte() byte {
+	switch {
+	case d.WavAudioFormat == wavFormatALaw:
+		return encodeALawSample(0)
+	case d.WavAudioFormat == wavFormatMuLaw:
+		return encodeMuLawSample(0)
+	case d.WavAudioFormat == wavFormatPCM && d.BitDepth == 8:
+		// 8-bit samples are unsigned with the zero line at 128
+		return 0x80
+	default:
+		return 0
+	}
+}
+
+// readSilentFrames reads the frame count of a slnt chunk from r.
+func (d *Decoder) readSilentFrames(r io.Reader) (int64, error) {
+	var frames [4]byte
+
+	_, err := io.ReadFull(r, frames[:])
+	if err != nil {
+		return 0, fmt.Errorf("failed to read the slnt chunk: %w", err)
+	}
+
+	return int64(d.byteOrder().Uint32(frames[:])), nil
+}
+
+// readLeadingSilence adds the frames of a slnt chunk found before the data
+// chunk to the silence decoded ahead of the samples.
+func (d *Decoder) readLeadingSilence(chunk *riff.Chunk) error {
+	frames, err := d.readSilentFrames(chunk.R)
+	if err != nil {
+		return err
+	}
+
+	d.leadingSilence += frames
+	d.skipChunk(chunk)
+
+	return nil
+}
@@ -0,0 +1,216 @@
+package wav
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/go-audio/audio"
+)
+
+func TestDecoder_WaveList(t *testing.T) {
+	data, err := os.ReadFile("fixtures/wavl.wav")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// data, three silent frames, data and two trailing silent frames
+	want := []float32{1000, 2000, 3000, 4000, 0, 0, 0, -1000, -2000, 0, 0}
+	for i := range want {
+		want[i] /= 32768
+	}
+
+	dec := NewDecoder(bytes.NewReader(data))
+
+	buf, err := dec.FullPCMBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertFloat32SlicesClose(t, buf.Data, want, 0)
+
+	if dec.PCMLen() != int64(2*len(want)) {
+		t.Fatalf("expected a PCM length of %d bytes, got %d", 2*len(want), dec.PCMLen())
+	}
+
+	err = dec.SeekToFrame(5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	frames := &audio.Float32Buffer{Data: make([]float32, 4)}
+
+	n, err := dec.PCMBuffer(frames)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertFloat32SlicesClose(t, frames.Data[:n], want[5:9], 0)
+
+	err = dec.Rewind()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	n, err = dec.PCMBuffer(frames)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertFloat32SlicesClose(t, frames.Data[:n], want[:4], 0)
+
+	fast := NewDecoder(bytes.NewReader(data))
+
+	err = fast.SkipToDataFast()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf, err = fast.FullPCMBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertFloat32SlicesClose(t, buf.Data, want, 0)
+
+	buf, metadata, err := NewDecoder(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertFloat32SlicesClose(t, buf.Data, want, 0)
+
+	if metadata == nil || metadata.Title != "wave list" {
+		t.Fatalf("expected the INFO chunk after the wave list, got %+v", metadata)
+	}
+}
+
+func TestDecoder_WaveListSilence(t *testing.T) {
+	testCases := []struct {
+		name     string
+		format   uint16
+		bitDepth uint16
+		// the zero line of 8-bit PCM and A-law is half a step off zero
+		eps float32
+	}{
+		{name: "unsigned 8-bit", format: wavFormatPCM, bitDepth: 8, eps: 1.0 / 128},
+		{name: "A-law", format: wavFormatALaw, bitDepth: 8, eps: 1.0 / 2048},
+		{name: "mu-law", format: wavFormatMuLaw, bitDepth: 8},
+		{name: "float", format: wavFormatIEEEFloat, bitDepth: 32},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			blockAlign := 2 * testCase.bitDepth / 8
+
+			var fmtChunk bytes.Buffer
+			_ = binary.Write(&fmtChunk, binary.LittleEndian, []uint16{testCase.format, 2})
+			_ = binary.Write(&fmtChunk, binary.LittleEndian, []uint32{8000, 8000 * uint32(blockAlign)})
+			_ = binary.Write(&fmtChunk, binary.LittleEndian, []uint16{blockAlign, testCase.bitDepth})
+
+			var list bytes.Buffer
+			list.WriteString("wavl")
+			writeTestChunk(t, &list, "slnt", []byte{4, 0, 0, 0})
+
+			var body bytes.Buffer
+			body.WriteString("WAVE")
+			writeTestChunk(t, &body, "fmt ", fmtChunk.Bytes())
+			writeTestChunk(t, &body, "LIST", list.Bytes())
+
+			var file bytes.Buffer
+			writeTestChunk(t, &file, "RIFF", body.Bytes())
+
+			buf, err := NewDecoder(bytes.NewReader(file.Bytes())).FullPCMBuffer()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assertFloat32SlicesClose(t, buf.Data, make([]float32, 8), testCase.eps)
+		})
+	}
+}
+
+func TestDecoder_WaveListUnsupportedFormat(t *testing.T) {
+	var list bytes.Buffer
+	list.WriteString("wavl")
+	writeTestChunk(t, &list, "slnt", []byte{4, 0, 0, 0})
+
+	var body bytes.Buffer
+	body.WriteString("WAVE")
+	writeTestChunk(t, &body, "fmt ", []byte{49, 0, 1, 0, 0x40, 0x1F, 0, 0, 0x59, 0x06, 0, 0, 65, 0, 0, 0})
+	writeTestChunk(t, &body, "LIST", list.Bytes())
+
+	var file bytes.Buffer
+	writeTestChunk(t, &file, "RIFF", body.Bytes())
+
+	err := NewDecoder(bytes.NewReader(file.Bytes())).FwdToPCM()
+	if !errors.Is(err, errWaveListFormat) {
+		t.Fatalf("expected errWaveListFormat, got %v", err)
+	}
+}
+
+func TestDecoder_SilentChunks(t *testing.T) {
+	data, err := os.ReadFile("fixtures/slnt.wav")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// two silent frames, the data chunk and three silent frames
+	want := []float32{0, 0, 1000, 2000, 3000, 0, 0, 0}
+	for i := range want {
+		want[i] /= 32768
+	}
+
+	dec := NewDecoder(bytes.NewReader(data))
+
+	buf, err := dec.FullPCMBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertFloat32SlicesClose(t, buf.Data, want, 0)
+
+	if dec.SilentFrames != 5 {
+		t.Fatalf("expected 5 silent frames, got %d", dec.SilentFrames)
+	}
+
+	sample, err := dec.FrameAt(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertFloat32SlicesClose(t, sample, want[3:4], 0)
+
+	fast := NewDecoder(bytes.NewReader(data))
+
+	err = fast.SkipToDataFast()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf, err = fast.FullPCMBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertFloat32SlicesClose(t, buf.Data, want, 0)
+
+	all := NewDecoder(bytes.NewReader(data))
+
+	buf, metadata, err := all.ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertFloat32SlicesClose(t, buf.Data, want, 0)
+
+	if metadata == nil || metadata.Title != "silence" {
+		t.Fatalf("expected the INFO chunk after the slnt chunk, got %+v", metadata)
+	}
+
+	if len(all.UnknownChunks) != 0 {
+		t.Fatalf("expected the slnt chunks to be decoded, got unknown chunks %+v", all.UnknownChunks)
+	}
+}